@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jbrudvik/gmc/create"
+	"github.com/urfave/cli/v2"
+)
+
+func addCommand(o *appOptions) *cli.Command {
+	return &cli.Command{
+		Name:      "add",
+		Usage:     "add a feature to the Go module in the current directory",
+		ArgsUsage: "[feature] [argument]",
+		Description: "Features: " + strings.Join(create.AddFeatures(), ", ") + "\n" +
+			"\n" +
+			"    $ " + Name + " add license mit\n" +
+			"    $ " + Name + " add ci github\n" +
+			"\n" +
+			"Existing files are never overwritten.",
+		Flags: concatFlags([]cli.Flag{
+			&cli.BoolFlag{
+				Name:  "git-exec",
+				Usage: "run the git executable for Git actions, instead of the built-in implementation",
+			},
+		}, outputFlags()),
+		OnUsageError: onCommandUsageError,
+		Action: func(c *cli.Context) error {
+			args := c.Args()
+			if args.Len() < 1 {
+				c.Set("help", "true")
+				return errors.New(fmt.Sprintf("Error: Feature is required (supported: %s)", strings.Join(create.AddFeatures(), ", ")))
+			} else if args.Len() > 2 {
+				c.Set("help", "true")
+				return errors.New("Error: Only one feature (and argument) is allowed")
+			}
+			feature := strings.ToLower(args.Get(0))
+
+			output, err := colorOutput(c, o.output, o.outputIsTerminal())
+			if err != nil {
+				return err
+			}
+			cfg, err := loadConfig()
+			if err != nil {
+				return errors.New(fmt.Sprintf("Failed to add %s: %s", feature, err))
+			}
+			opts := create.AddOptions{
+				Feature:          feature,
+				Arg:              args.Get(1),
+				GitExec:          c.Bool("git-exec"),
+				GitInitialBranch: o.gitInitialBranch,
+				Author:           cfg.Author,
+				Clock:            o.clock,
+				RunOptions:       runOptionsFromFlags(c, output, o.outputIsTerminal()),
+			}
+
+			ctx, stop := interruptible(c)
+			defer stop()
+			ctx = withVerbose(ctx, c)
+			ctx, closeLog, err := withLogFile(ctx, c)
+			if err != nil {
+				return err
+			}
+			defer closeLog()
+			ctx, cancel := withTimeout(ctx, c)
+			defer cancel()
+			_, err = create.Add(ctx, opts)
+			var usage create.UsageError
+			if errors.As(err, &usage) {
+				c.Set("help", "true")
+			} else if errors.Is(err, create.ErrInterrupted) {
+				return cli.Exit(err.Error(), interruptedExitCode)
+			}
+			return err
+		},
+	}
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"errors"
+
+	"github.com/jbrudvik/gmc/create"
+	"github.com/urfave/cli/v2"
+)
+
+func adoptCommand(o *appOptions) *cli.Command {
+	return &cli.Command{
+		Name:  "adopt",
+		Usage: "bring the Go module in the current directory, which " + Name + " didn't create, under " + Name + "'s management",
+		Description: "Infers which features the module has from its files (e.g. .git, a CI workflow, or .golangci.yml), records\n" +
+			"them in a manifest (.gmc/manifest.json), and notes which standard files are missing, with how to add them.\n" +
+			"No other file is changed.",
+		Flags:        outputFlags(),
+		OnUsageError: onCommandUsageError,
+		Action: func(c *cli.Context) error {
+			if c.Args().Present() {
+				c.Set("help", "true")
+				return errors.New("Error: No arguments are allowed")
+			}
+
+			output, err := colorOutput(c, o.output, o.outputIsTerminal())
+			if err != nil {
+				return err
+			}
+			opts := create.AdoptOptions{
+				RunOptions: runOptionsFromFlags(c, output, o.outputIsTerminal()),
+			}
+
+			ctx, stop := interruptible(c)
+			defer stop()
+			ctx = withVerbose(ctx, c)
+			ctx, closeLog, err := withLogFile(ctx, c)
+			if err != nil {
+				return err
+			}
+			defer closeLog()
+			ctx, cancel := withTimeout(ctx, c)
+			defer cancel()
+			_, err = create.Adopt(ctx, opts)
+			if errors.Is(err, create.ErrInterrupted) {
+				return cli.Exit(err.Error(), interruptedExitCode)
+			}
+			return err
+		},
+	}
+}
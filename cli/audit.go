@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jbrudvik/gmc/create"
+	"github.com/urfave/cli/v2"
+)
+
+func auditCommand(output io.Writer) *cli.Command {
+	return &cli.Command{
+		Name:      "audit",
+		Usage:     "report which modules in directories of repositories are outdated, missing required features, or changed",
+		ArgsUsage: "[directory...]",
+		Description: "Reads the manifest of each module in the directories (or of each repository in them, one level deep), and\n" +
+			"reports which were created by an earlier version of " + Name + ", which are missing required features, and which\n" +
+			"generated files have changed since. Modules without a manifest are reported as unmanaged.\n" +
+			"\n" +
+			"    $ " + Name + " audit --require ci,lint,license --csv ~/src/acme",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "require",
+				Usage: "features every module must have (comma-separated), named as their flags are (e.g. ci,lint,license)",
+			},
+			&cli.StringFlag{
+				Name:  "list",
+				Usage: "read directories from a file (- for standard input), one per line",
+			},
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "print the report as JSON",
+			},
+			&cli.BoolFlag{
+				Name:  "csv",
+				Usage: "print the report as CSV",
+			},
+		},
+		OnUsageError: onCommandUsageError,
+		Action: func(c *cli.Context) error {
+			if c.Bool("json") && c.Bool("csv") {
+				c.Set("help", "true")
+				return errors.New("Error: --json and --csv can't be used together")
+			}
+			paths := c.Args().Slice()
+			if list := c.String("list"); list != "" {
+				listed, err := readBatchFile(list)
+				if err != nil {
+					return errors.New(fmt.Sprintf("Failed to audit: %s", err))
+				}
+				paths = append(paths, listed...)
+			}
+			if len(paths) == 0 {
+				paths = []string{"."}
+			}
+
+			audits, err := create.Audit(create.AuditOptions{Paths: paths, Require: stageList(c.String("require"))})
+			if err != nil {
+				return err
+			}
+			switch {
+			case c.Bool("json"):
+				content, err := json.MarshalIndent(audits, "", "  ")
+				if err != nil {
+					return err
+				}
+				_, err = fmt.Fprintf(output, "%s\n", content)
+				return err
+			case c.Bool("csv"):
+				w := csv.NewWriter(output)
+				w.Write([]string{"dir", "module", "managed", "gmcVersion", "outdated", "missingFeatures", "modifiedFiles", "error"})
+				for _, a := range audits {
+					w.Write([]string{
+						a.Dir,
+						a.Module,
+						strconv.FormatBool(a.Managed),
+						a.GmcVersion,
+						strconv.FormatBool(a.Outdated),
+						strings.Join(a.MissingFeatures, ";"),
+						strings.Join(a.ModifiedFiles, ";"),
+						a.Error,
+					})
+				}
+				w.Flush()
+				return w.Error()
+			}
+			w := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "DIR\tMODULE\tGMC\tOUTDATED\tMISSING\tMODIFIED")
+			for _, a := range audits {
+				version := a.GmcVersion
+				switch {
+				case a.Error != "":
+					version = a.Error
+				case !a.Managed:
+					version = "unmanaged"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\n", a.Dir, a.Module, version, yesNo(a.Outdated), strings.Join(a.MissingFeatures, ","), len(a.ModifiedFiles))
+			}
+			return w.Flush()
+		},
+	}
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
@@ -1,29 +1,31 @@
 package cli
 
 import (
-	"embed"
+	"context"
 	"errors"
 	"fmt"
 	"io"
-	"io/fs"
 	"os"
-	"os/exec"
-	"path/filepath"
-	"runtime/debug"
+	"os/signal"
+	"path"
 	"strings"
+	"syscall"
+	"time"
 
+	"github.com/jbrudvik/gmc/create"
 	"github.com/urfave/cli/v2"
 )
 
-const Name string = "gmc"
+const Name string = create.Name
 
-var Version string = getVersion()
+var Version string = create.Version
 
 const Url string = "https://github.com/jbrudvik/" + Name
 
 const Description string = "`" + Name + " [module name]` creates a directory containing:\n" +
 	"- Go module metadata: go.mod\n" +
 	"- A place to start writing code: main.go\n" +
+	"- A test that builds and runs it: main_test.go\n" +
 	"- A .gitignore file\n" +
 	"\n" +
 	"This module can be immediately run:\n" +
@@ -33,369 +35,935 @@ const Description string = "`" + Name + " [module name]` creates a directory con
 	"\n" +
 	"Optionally, the directory can also include:\n" +
 	"- Git repository setup with .gitignore, README.md\n" +
+	"- A LICENSE file\n" +
+	"- A CI workflow\n" +
+	"- A golangci-lint configuration\n" +
+	"- A GoReleaser release configuration\n" +
+	"- A Dockerfile\n" +
+	"- A Makefile\n" +
+	"- A Taskfile\n" +
+	"- Scripts to bootstrap, build, test, and run (script/), optionally with PowerShell equivalents\n" +
+	"- A bootstrap script for Linux machines\n" +
+	"- Embedded assets (go:embed)\n" +
+	"- Translated messages (golang.org/x/text)\n" +
+	"- Feature flags (OpenFeature)\n" +
+	"- Golden file tests\n" +
+	"- Mutation testing (Gremlins)\n" +
+	"- Secret scanning (gitleaks)\n" +
+	"- OpenSSF Scorecard, with a Best Practices badge checklist\n" +
+	"- Visual Studio Code, GoLand, or Neovim configuration\n" +
+	"- A Gitpod or Codespaces configuration\n" +
 	"\n" +
 	"More information: " + Url
 
-//go:embed all:assets
-var assets embed.FS
+// Exit codes, besides 1 for any other failure, so that scripts can tell failures apart
+const (
+	usageExitCode       int = 2
+	conflictExitCode    int = 3
+	gitExitCode         int = 4
+	toolchainExitCode   int = 5
+	interruptedExitCode int = 130 // As shells report for SIGINT
+)
+
+// Listed after the options in help
+const exitCodesHelp string = `
+EXIT CODES:
+   0    success
+   1    failure
+   2    usage error (e.g. an unknown flag or an invalid module name)
+   3    filesystem conflict (e.g. the module's directory already exists)
+   4    Git failure (e.g. ` + "`git config --global user.email`" + ` is not set)
+   5    Go toolchain failure (e.g. go is not installed)
+   130  interrupted
+`
+
+// An Option customizes the app returned by App
+type Option func(*appOptions)
+
+type appOptions struct {
+	output           io.Writer
+	errorOutput      io.Writer
+	exitCodeHandler  func(int)
+	gitInitialBranch string
+	clock            func() time.Time
+	terminal         *bool
+	goToolchain      string // go executable provisioned for this run, since go isn't installed
+}
+
+// WithOutput writes output to w, instead of standard output
+func WithOutput(w io.Writer) Option {
+	return func(o *appOptions) {
+		o.output = w
+	}
+}
+
+// WithErrorOutput writes error output to w, instead of standard error
+func WithErrorOutput(w io.Writer) Option {
+	return func(o *appOptions) {
+		o.errorOutput = w
+	}
+}
 
-const assetsDir string = "assets"
-const assetsDefaultDir string = "default"
+// WithExitHandler calls f with the exit code, instead of exiting
+func WithExitHandler(f func(int)) Option {
+	return func(o *appOptions) {
+		o.exitCodeHandler = f
+	}
+}
 
-type gitRepo struct {
-	initialBranch *string
+// WithGitBranch names the initial branch of created Git repositories, instead of using Git's configured default
+func WithGitBranch(branch string) Option {
+	return func(o *appOptions) {
+		o.gitInitialBranch = branch
+	}
 }
 
-const gitignoreFileName string = ".gitignore"
-const readmeFileName string = "README.md"
+// WithClock reads the current time (e.g. for the year of a license) from now, instead of the system clock
+func WithClock(now func() time.Time) Option {
+	return func(o *appOptions) {
+		o.clock = now
+	}
+}
 
-func App() *cli.App {
-	return AppWithCustomOutput(os.Stdout, os.Stderr)
+// WithTerminal treats output as a terminal (or not), instead of checking whether it is one
+func WithTerminal(isTerminal bool) Option {
+	return func(o *appOptions) {
+		o.terminal = &isTerminal
+	}
 }
 
-func AppWithCustomOutput(output io.Writer, errorOutput io.Writer) *cli.App {
-	exitCodeHandler := func(exitCode int) {
-		os.Exit(exitCode)
+// outputIsTerminal reports whether output is shown on a terminal, where it can be redrawn and prompts can be answered
+func (o *appOptions) outputIsTerminal() bool {
+	if o.terminal != nil {
+		return *o.terminal
 	}
-	return AppWithCustomEverything(os.Stdout, os.Stderr, exitCodeHandler, nil)
+	f, ok := o.output.(*os.File)
+	return ok && isTerminal(f)
+}
+
+// errorOutputIsTerminal reports whether error output is shown on a terminal
+func (o *appOptions) errorOutputIsTerminal() bool {
+	f, ok := o.errorOutput.(*os.File)
+	return ok && isTerminal(f)
 }
 
-func AppWithCustomEverything(output io.Writer, errorOutput io.Writer, exitCodeHandler func(int), gitInitialBranch *string) *cli.App {
+// App returns the gmc command-line app, which writes to standard output and exits when run, unless options say otherwise
+func App(options ...Option) *cli.App {
+	o := &appOptions{
+		output:          os.Stdout,
+		errorOutput:     os.Stderr,
+		exitCodeHandler: os.Exit,
+	}
+	for _, option := range options {
+		option(o)
+	}
+	output := o.output
+	errorOutput := o.errorOutput
+	exitCodeHandler := o.exitCodeHandler
+
 	return &cli.App{
 		Name:        Name,
 		Usage:       "(Go mod create) creates Go modules",
+		UsageText:   Name + " [global options] [module name...]\n" + Name + " command [command options] [arguments...]",
 		Version:     Version,
 		Description: Description,
 		Writer:      output,
 		ErrWriter:   errorOutput,
 		ExitErrHandler: func(c *cli.Context, err error) {
-			quiet := c.Bool("quiet")
+			// Failures are reported even when quiet, so that scripts learn why a run failed, though without help
+			quiet := isQuiet(c)
 			if err != nil {
-				flogf(errorOutput, quiet, "%s\n", err)
+				flogf(errorOutput, c.Bool("silent"), "%s\n", errorText(c, o, err))
 				if c.Bool("help") {
 					flogln(errorOutput, quiet)
 					if !quiet {
-						cli.ShowAppHelp(c)
+						if c.Command.Name != "" {
+							cli.ShowCommandHelp(c, c.Command.Name)
+						} else {
+							cli.ShowAppHelp(c)
+						}
 					}
 				}
-				exitCodeHandler(1)
+				exitCodeHandler(exitCode(c, err))
 			} else {
 				exitCodeHandler(0)
 			}
 		},
-		OnUsageError: func(c *cli.Context, err error, isSubcommand bool) error {
-			c.Set("help", "true")
-			return errors.New("Error: Unknown flag")
-		},
+		CustomAppHelpTemplate:  cli.AppHelpTemplate + exitCodesHelp,
+		OnUsageError:           onUsageError,
 		HideHelpCommand:        true,
 		UseShortOptionHandling: true,
-		Flags: []cli.Flag{
-			&cli.BoolFlag{
-				Name:    "git",
-				Usage:   "create as Git repository",
-				Aliases: []string{"g"},
+		Commands: []*cli.Command{
+			{
+				Name:         "new",
+				Usage:        "create a Go module in a new directory (the default command)",
+				ArgsUsage:    "[module name...]",
+				Flags:        createFlags(),
+				OnUsageError: onCommandUsageError,
+				Action:       createAction(o, false),
 			},
-			&cli.BoolFlag{
-				Name:    "quiet",
-				Usage:   "silence output", // Q: What about error output?
-				Aliases: []string{"q"},
+			{
+				Name:         "init",
+				Usage:        "create a Go module in the current directory, keeping any files already there",
+				ArgsUsage:    "[module name]",
+				Flags:        createFlags(),
+				OnUsageError: onCommandUsageError,
+				Action:       createAction(o, true),
 			},
+			addCommand(o),
+			adoptCommand(o),
+			planCommand(o),
+			applyCommand(o),
+			configCommand(output),
+			serveCommand(output),
+			templatesCommand(output),
+			auditCommand(output),
+			releasePrepCommand(output),
+			doctorCommand(output),
+			upgradeSelfCommand(output),
+			completionCommand(output),
+			manCommand(output),
+			helpCommand(output),
+			completeCommand(output),
 		},
-		ArgsUsage: "[module name]",
-		Action: func(c *cli.Context) error {
-			args := c.Args()
-			if args.Len() < 1 {
-				c.Set("help", "true")
-				return errors.New("Error: Module name is required")
-			} else if args.Len() > 1 {
-				c.Set("help", "true")
-				return errors.New("Error: Only one module name is allowed")
-			} else {
-				// Get only arg: Module name
-				module := args.First()
-
-				// Parse flags
-				var repo *gitRepo
-				if c.Bool("git") {
-					repo = &gitRepo{
-						initialBranch: gitInitialBranch,
-					}
-				}
-				var extraDirs []string
-				quiet := c.Bool("quiet")
-
-				// Create module
-				err := createModule(module, repo, extraDirs, output, quiet)
-				if err != nil {
-					return errors.New(fmt.Sprintf("Failed to create Go module: %s: %s", module, err))
-				}
-			}
-			return nil
-		},
+		// The help command replaces urfave/cli's, which would otherwise add the help flag
+		Flags:     append(createFlags(), cli.HelpFlag),
+		ArgsUsage: "[module name...]",
+		Action:    createAction(o, false),
 	}
 }
 
-func createModule(module string, repo *gitRepo, extraDirs []string, output io.Writer, quiet bool) error {
-	flogf(output, quiet, "Creating Go module: %s\n", module)
+// exitCode returns the exit code for a failure, as listed in exitCodesHelp
+func exitCode(c *cli.Context, err error) int {
+	var exitCoder cli.ExitCoder
+	switch {
+	case errors.As(err, &exitCoder):
+		return exitCoder.ExitCode()
+	case c.Bool("help"):
+		// Usage errors are reported along with help
+		return usageExitCode
+	case errors.Is(err, create.ErrDirExists), errors.Is(err, create.ErrModuleExists), errors.Is(err, create.ErrArchiveExists):
+		return conflictExitCode
+	case errors.Is(err, create.ErrGit), errors.Is(err, create.ErrGitNotConfigured):
+		return gitExitCode
+	case errors.Is(err, create.ErrGoToolchainMissing):
+		return toolchainExitCode
+	}
+	return 1
+}
 
-	moduleBase := filepath.Base(module)
-	nextSteps := []string{}
+// onUsageError reports an unknown flag, along with help
+func onUsageError(c *cli.Context, err error, isSubcommand bool) error {
+	c.Set("help", "true")
+	return errors.New("Error: Unknown flag")
+}
 
-	// Create module directory && change into the directory
-	err := os.Mkdir(moduleBase, 0755)
-	if err != nil {
-		return err
-	}
-	reportCreatedDir(output, quiet, moduleBase)
+// onCommandUsageError is onUsageError for commands. A command's flags are unset when they fail to parse,
+// so help is requested through the app's flags instead.
+func onCommandUsageError(c *cli.Context, err error, isSubcommand bool) error {
+	c.Lineage()[1].Set("help", "true")
+	return errors.New("Error: Unknown flag")
+}
 
-	// Create go.mod
-	cmd := exec.Command("go", "mod", "init", module)
-	cmd.Dir = moduleBase
-	if err = cmd.Run(); err != nil {
-		return err
-	}
-	flogln(output, quiet, "- Initialized Go module")
+// createFlags returns the flags of commands that create modules
+func createFlags() []cli.Flag {
+	return concatFlags(moduleFlags(), []cli.Flag{
+		&cli.StringFlag{
+			Name:  "batch",
+			Usage: "also create each module named in a file, one per line (- for standard input)",
+		},
+		&cli.StringFlag{
+			Name:  "archive",
+			Usage: "write the module to an archive (e.g. out.tar.gz or out.zip, or - for a tarball on standard output) instead of a directory, without Git",
+		},
+	}, outputFlags())
+}
 
-	// Copy over assets
-	err = copyEmbeddedFS(assets, assetsDefaultDir, moduleBase, output, quiet)
-	if err != nil {
-		return err
-	}
+// moduleFlags returns the flags that determine what a created module contains
+func moduleFlags() []cli.Flag {
+	return concatFlags([]cli.Flag{
+		&cli.BoolFlag{
+			Name:  "local",
+			Usage: "keep a module name without a slash as is, instead of adding the configured prefix",
+		},
+		&cli.BoolFlag{
+			Name:  "infer",
+			Usage: "add github.com/<your GitHub login> to a module name without a slash",
+		},
+		&cli.StringFlag{
+			Name:    "output-dir",
+			Usage:   "create the module in this directory, instead of the current one",
+			Aliases: []string{"C"},
+		},
+		&cli.BoolFlag{
+			Name:  "full-path",
+			Usage: "create the module's directory at its full module path (e.g. github.com/you/mymodule), instead of its last element",
+		},
+		&cli.BoolFlag{
+			Name:  "force",
+			Usage: "create the module in its directory even if the directory exists, keeping any files already there",
+		},
+		&cli.BoolFlag{
+			Name:  "resume",
+			Usage: "finish creating a module whose creation was interrupted, skipping what was already done",
+		},
+		&cli.StringFlag{
+			Name:  "skip",
+			Usage: "skip these stages (comma-separated): " + strings.Join(create.Stages(), ", "),
+		},
+		&cli.StringFlag{
+			Name:  "only",
+			Usage: "run only these stages (comma-separated): " + strings.Join(create.Stages(), ", "),
+		},
+		&cli.BoolFlag{
+			Name:    "git",
+			Usage:   "create as Git repository",
+			Aliases: []string{"g"},
+		},
+		&cli.BoolFlag{
+			Name:  "git-exec",
+			Usage: "run the git executable for Git actions, instead of the built-in implementation",
+		},
+		&cli.BoolFlag{
+			Name:  "create-remote",
+			Usage: "create the remote repository on GitHub (with $GITHUB_TOKEN, or gh's login), named by the module path, and add it as origin (requires --git)",
+		},
+		&cli.BoolFlag{
+			Name:  "private",
+			Usage: "make the remote repository created by --create-remote private (the default)",
+		},
+		&cli.BoolFlag{
+			Name:  "public",
+			Usage: "make the remote repository created by --create-remote public",
+		},
+		&cli.BoolFlag{
+			Name:  "push",
+			Usage: "push the initial commit to the remote, once created by --create-remote or if it already exists (requires --git)",
+		},
+		&cli.StringFlag{
+			Name:  "remote-protocol",
+			Usage: "add the Git remote with an " + strings.Join(create.RemoteProtocols(), " or ") + " URL (default: ssh)",
+		},
+		&cli.BoolFlag{
+			Name:  "bundle",
+			Usage: "also write the Git repository to <name>.bundle next to the module's directory, to clone elsewhere (implies --git)",
+		},
+		&cli.BoolFlag{
+			Name:  "bundle-only",
+			Usage: "write the Git repository to <name>.bundle as --bundle does, and then remove the module's directory",
+		},
+		&cli.StringFlag{
+			Name:  "ci",
+			Usage: "add a CI workflow: " + strings.Join(create.CIProviders(), ", "),
+		},
+		&cli.StringFlag{
+			Name:  "fmt-check",
+			Usage: "check formatting in CI with " + strings.Join(create.Formatters(), " or ") + ", and files against an added .editorconfig (requires --ci)",
+		},
+		&cli.StringFlag{
+			Name:  "go-version",
+			Usage: "pin the Go version for go.mod, CI, and the Dockerfile (e.g. 1.21) instead of using the installed version",
+		},
+		&cli.StringFlag{
+			Name:  "toolchain",
+			Usage: "pin the Go toolchain (e.g. go1.22.3) in go.mod, and with GOTOOLCHAIN in an .envrc and CI, so that it's used even where an older Go is installed",
+		},
+		&cli.BoolFlag{
+			Name:  "in-container",
+			Usage: "run Go commands in a golang container (with Docker or Podman) instead of with the installed Go",
+		},
+		&cli.BoolFlag{
+			Name:  "provision-go",
+			Usage: "if Go isn't installed, download the official Go (checksum-verified) into gmc's cache and run Go commands with it, without asking",
+		},
+		&cli.StringFlag{
+			Name:  "goproxy",
+			Usage: "fetch dependencies from this module proxy (e.g. a private Athens or Artifactory URL) instead of the GOPROXY in go env",
+		},
+		&cli.StringFlag{
+			Name:  "private-proxy",
+			Usage: "resolve modules only through this private proxy (e.g. an Athens or Artifactory URL), documenting its setup, and configuring .envrc and CI to use it",
+		},
+		&cli.BoolFlag{
+			Name:  "static",
+			Usage: "build and verify a fully static binary in CI",
+		},
+		&cli.BoolFlag{
+			Name:  "reproducible",
+			Usage: "build byte-for-byte reproducible binaries, and verify in CI that two builds match",
+		},
+		&cli.BoolFlag{
+			Name:  "embed-assets",
+			Usage: "add an assets directory embedded into the binary with go:embed",
+		},
+		&cli.BoolFlag{
+			Name:  "i18n",
+			Usage: "add translated messages with golang.org/x/text",
+		},
+		&cli.StringFlag{
+			Name:  "feature-flags",
+			Usage: "add feature flags with an environment variable provider: openfeature",
+		},
+		&cli.BoolFlag{
+			Name:  "notice",
+			Usage: "generate NOTICE, attributing the dependencies added (with --i18n or --feature-flags), with go-licenses, and a make/task target that regenerates it",
+		},
+		&cli.StringFlag{
+			Name:  "layout",
+			Usage: "add a package layout: apiv1, for a public API in api/v1 that a v2 can be added beside",
+		},
+		&cli.BoolFlag{
+			Name:  "golden",
+			Usage: "add a golden file test helper and an example test",
+		},
+		&cli.BoolFlag{
+			Name:  "mutation",
+			Usage: "add a Gremlins mutation testing configuration, with a CI job and make/task target",
+		},
+		&cli.BoolFlag{
+			Name:  "secret-scan",
+			Usage: "configure gitleaks secret scanning, with a pre-commit hook the Git repository runs from its first commit, and a CI job",
+		},
+		&cli.BoolFlag{
+			Name:  "scorecard",
+			Usage: "add an OpenSSF Scorecard workflow and README badge, and a Best Practices badge checklist (requires a module under github.com)",
+		},
+		&cli.BoolFlag{
+			Name:  "lint",
+			Usage: "add a golangci-lint configuration, and run it in CI",
+		},
+		&cli.BoolFlag{
+			Name:  "pgo",
+			Usage: "add a default.pgo profile for profile-guided optimization",
+		},
+		&cli.BoolFlag{
+			Name:  "goreleaser",
+			Usage: "add a GoReleaser configuration and release workflow",
+		},
+		&cli.BoolFlag{
+			Name:  "make",
+			Usage: "add a Makefile with build, test, lint, fmt, run, and clean targets",
+		},
+		&cli.BoolFlag{
+			Name:  "taskfile",
+			Usage: "add a Taskfile.yml with the same tasks as --make",
+		},
+		&cli.StringFlag{
+			Name:  "targets",
+			Usage: "build for these platforms (comma-separated, e.g. linux/amd64,darwin/arm64,windows/amd64) with cgo disabled: into bin/dist/<os>_<arch>/ with a dist target in the Makefile and Taskfile, and with GoReleaser",
+		},
+		&cli.BoolFlag{
+			Name:  "scripts",
+			Usage: "add script/bootstrap, script/build, script/test, and script/server, used by --make, --taskfile, and CI",
+		},
+		&cli.BoolFlag{
+			Name:  "powershell",
+			Usage: "add PowerShell equivalents of the --scripts scripts, and also run CI on windows-latest",
+		},
+		&cli.BoolFlag{
+			Name:  "bootstrap-script",
+			Usage: "add a script/bootstrap that installs the Go toolchain and tools on a fresh Linux machine",
+		},
+		&cli.BoolFlag{
+			Name:  "docker",
+			Usage: "add a Dockerfile and .dockerignore",
+		},
+		&cli.StringFlag{
+			Name:  "license",
+			Usage: "add a LICENSE file: " + strings.Join(create.Licenses(), ", "),
+		},
+	}, editorExtraFlags(), []cli.Flag{
+		&cli.StringFlag{
+			Name:  "cloud-dev",
+			Usage: "add a prebuilt cloud development environment: " + strings.Join(create.CloudDevEnvironments(), ", "),
+		},
+		&cli.BoolFlag{
+			Name:  "split-cmd",
+			Usage: "create a library, with its command in a separate module in cmd/<name> that requires it through a replace directive",
+		},
+		&cli.BoolFlag{
+			Name:  "examples",
+			Usage: "add a runnable example program in a separate module in examples/, which CI builds (requires --split-cmd)",
+		},
+		&cli.BoolFlag{
+			Name:  "prop-tests",
+			Usage: "add an example property-based test of the library with rapid (requires --split-cmd)",
+		},
+		&cli.BoolFlag{
+			Name:  "no-deps",
+			Usage: "fail unless only the standard library is used",
+		},
+		&cli.BoolFlag{
+			Name:  "fail-on-vuln",
+			Usage: "fail if govulncheck finds known vulnerabilities in the dependencies added, instead of noting them",
+		},
+	})
+}
 
-	nextSteps = append(nextSteps, fmt.Sprintf("Change into module's directory: $ cd %s", moduleBase))
-	nextSteps = append(nextSteps, "Run module: $ go run .")
+// Module flags that only change how a module is created, not what it contains, so they're left out of its manifest
+var unrecordedFlags = map[string]bool{
+	"local": true, "infer": true, "output-dir": true, "full-path": true, "force": true, "resume": true, "git-exec": true, "in-container": true,
+	"provision-go": true, "goproxy": true, "create-remote": true, "private": true, "public": true,
+	"remote-protocol": true, "push": true, "fail-on-vuln": true,
+}
 
-	// Copy over extras
-	for _, extraDir := range extraDirs {
-		err = copyEmbeddedFS(assets, extraDir, moduleBase, output, quiet)
-		if err != nil {
-			return err
+// manifestFlags returns the module flags that were set, as recorded in the module's manifest (e.g. "--ci=github")
+func manifestFlags(c *cli.Context) []string {
+	flags := []string{}
+	for _, flag := range moduleFlags() {
+		name := flag.Names()[0]
+		if unrecordedFlags[name] || !c.IsSet(name) {
+			continue
+		}
+		if _, ok := flag.(*cli.BoolFlag); ok {
+			if c.Bool(name) {
+				flags = append(flags, "--"+name)
+			} else {
+				flags = append(flags, "--"+name+"=false")
+			}
+		} else {
+			flags = append(flags, fmt.Sprintf("--%s=%s", name, c.String(name)))
 		}
 	}
+	return flags
+}
 
-	// Create .gitignore
-	gitignoreFilePath := filepath.Join(moduleBase, gitignoreFileName)
-	err = os.WriteFile(gitignoreFilePath, []byte(moduleBase), 0644)
-	if err != nil {
-		errorMessage := fmt.Sprintf("Failed to create .gitignore file: %s", err.Error())
-		return errors.New(errorMessage)
+// editorExtraFlags returns a flag for each editor that can be configured
+func editorExtraFlags() []cli.Flag {
+	flags := []cli.Flag{}
+	for _, editor := range create.Editors() {
+		flags = append(flags, &cli.BoolFlag{
+			Name:  editor.Name,
+			Usage: editor.Usage,
+		})
 	}
-	reportCreatedFile(output, quiet, gitignoreFilePath)
+	return flags
+}
 
-	// Set up Git repo
-	if repo != nil {
-		err, gitRepoNextSteps := setUpGitRepo(repo, module, moduleBase, output, quiet)
-		if err != nil {
-			errorMessage := fmt.Sprintf("Failed to create as Git repository: %s", err.Error())
-			return errors.New(errorMessage)
+// createAction creates each module named by the arguments (and --batch file), in a new directory or (if inCurrentDir) the
+// current one. Failing to create one module doesn't stop the others from being created.
+func createAction(o *appOptions, inCurrentDir bool) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		output := o.output
+		modules := c.Args().Slice()
+		if c.IsSet("batch") {
+			batchModules, err := readBatchFile(c.String("batch"))
+			if err != nil {
+				return errors.New(fmt.Sprintf("Failed to read batch file: %s: %s", c.String("batch"), err))
+			}
+			modules = append(modules, batchModules...)
+		}
+		if len(modules) < 1 {
+			c.Set("help", "true")
+			return errors.New("Error: Module name is required")
+		} else if (inCurrentDir || c.IsSet("archive")) && len(modules) > 1 {
+			c.Set("help", "true")
+			return errors.New("Error: Only one module name is allowed")
 		}
-		nextSteps = append(nextSteps, gitRepoNextSteps...)
-	}
-
-	// Output success
-	flogf(output, quiet, "\nFinished creating Go module: %s\n", module)
-
-	// Add next step: Start coding!
-	editor := "$EDITOR"
-	editorEnvVar := os.Getenv("EDITOR")
-	if editorEnvVar != "" {
-		editor = editorEnvVar
-	}
-	nextSteps = append(nextSteps, fmt.Sprintf("Start coding: $ %s .", editor))
 
-	// Output next steps
-	if len(nextSteps) > 0 {
-		flogf(output, quiet, "\nNext steps:\n")
-		for _, nextStep := range nextSteps {
-			flogf(output, quiet, "- %s\n", nextStep)
+		// Catch invalid names before creating any module
+		for _, module := range modules {
+			err := create.CheckModulePath(module)
+			if err != nil {
+				c.Set("help", "true")
+				return err
+			}
 		}
-	}
 
-	return nil
-}
+		progressOutput, terminal := output, o.outputIsTerminal()
+		if c.String("archive") == "-" {
+			// The archive is written to output, so progress is reported to error output instead
+			progressOutput, terminal = o.errorOutput, o.errorOutputIsTerminal()
+		}
+		moduleOutput, err := colorOutput(c, progressOutput, terminal)
+		if err != nil {
+			return err
+		}
 
-func copyEmbeddedFS(srcFS embed.FS, src string, moduleBase string, output io.Writer, quiet bool) error {
-	srcRoot := filepath.Join(assetsDir, src)
+		if shouldOnboard(c, o) {
+			err := onboard(c.Context, c.App.Reader, output, c.Bool("git-exec"))
+			if err != nil {
+				return err
+			}
+		}
 
-	err := fs.WalkDir(srcFS, srcRoot, func(srcPath string, entry fs.DirEntry, err error) error {
+		goToolchain, err := provisionGo(c, o, output)
 		if err != nil {
 			return err
 		}
-		if srcRoot == srcPath {
-			// Ignore the root -- we only want its contents
-			return nil
-		}
 
-		dstPath := filepath.Join(moduleBase, withoutFilepathPrefix(srcPath, srcRoot))
+		ctx, stop := interruptible(c)
+		defer stop()
+		ctx = withVerbose(ctx, c)
+		ctx, closeLog, err := withLogFile(ctx, c)
+		if err != nil {
+			return err
+		}
+		defer closeLog()
 
-		if entry.IsDir() {
-			// Create dir
-			err = os.Mkdir(dstPath, 0755)
-			if err != nil {
-				return err
+		quiet := isQuiet(c)
+		var d *dashboard
+		if len(modules) > 1 && !quiet && !c.Bool("json") && o.outputIsTerminal() {
+			d = newDashboard(output, modules)
+		}
+		failed := []string{}
+		for i, module := range modules {
+			moduleOptions := *o
+			moduleOptions.output = moduleOutput
+			moduleOptions.terminal = &terminal
+			moduleOptions.goToolchain = goToolchain
+			if d != nil {
+				// Reported to the dashboard (which reads it as plain text), instead of interleaved with other modules'
+				// output
+				rowIsTerminal := false
+				moduleOptions.output = d.start(i)
+				moduleOptions.terminal = &rowIsTerminal
 			}
-			reportCreatedDir(output, quiet, dstPath)
-		} else {
-			// Copy file
-			fileBytes, err := fs.ReadFile(srcFS, srcPath)
-			if err != nil {
-				return err
+			err := createModule(ctx, c, &moduleOptions, module, inCurrentDir)
+			if d != nil {
+				d.finish(i, err)
 			}
-			err = os.WriteFile(dstPath, fileBytes, 0644)
 			if err != nil {
-				return err
+				var exitCoder cli.ExitCoder
+				if len(modules) == 1 || c.Bool("help") || errors.As(err, &exitCoder) {
+					return err
+				}
+				if d == nil {
+					flogf(c.App.ErrWriter, c.Bool("silent"), "%s\n", errorText(c, o, err))
+				}
+				failed = append(failed, module)
+			} else if len(modules) > 1 && d == nil {
+				flogln(output, quiet || c.Bool("json"))
 			}
-			reportCreatedFile(output, quiet, dstPath)
 		}
 
+		if d != nil {
+			d.summarize()
+		}
+		if len(modules) > 1 {
+			flogf(output, quiet || c.Bool("json"), "Created %d of %d Go modules\n", len(modules)-len(failed), len(modules))
+			if len(failed) > 0 {
+				return errors.New(fmt.Sprintf("Failed to create Go modules: %s", strings.Join(failed, ", ")))
+			}
+		}
 		return nil
-	})
+	}
+}
 
+// createModule creates a single module, in a new directory or (if inCurrentDir) the current one, with the options set by
+// flags and the config file
+func createModule(ctx context.Context, c *cli.Context, o *appOptions, module string, inCurrentDir bool) error {
+	opts, err := moduleOptions(ctx, c, o, module, inCurrentDir)
 	if err != nil {
 		return err
 	}
 
-	return nil
-}
+	ctx, cancel := withTimeout(ctx, c)
+	defer cancel()
 
-func setUpGitRepo(repo *gitRepo, module string, moduleBase string, output io.Writer, quiet bool) (error, []string) {
-	nextSteps := []string{}
+	// Create module
+	_, err = create.Create(ctx, opts)
+	var usage create.UsageError
+	if errors.As(err, &usage) {
+		c.Set("help", "true")
+	} else if errors.Is(err, create.ErrInterrupted) {
+		return cli.Exit(err.Error(), interruptedExitCode)
+	}
+	return err
+}
 
-	// Ensure Git user.email is set
-	cmd := exec.Command("git", "config", "--global", "user.email")
-	cmd.Dir = moduleBase
-	cmdOutput, err := cmd.Output()
+// moduleOptions returns the options for creating a module set by flags, falling back to the config file
+func moduleOptions(ctx context.Context, c *cli.Context, o *appOptions, module string, inCurrentDir bool) (create.Options, error) {
+	// Load config
+	cfg, err := loadConfig()
 	if err != nil {
-		return errors.New("Failed to look up Git user.email"), nil
+		return create.Options{}, errors.New(fmt.Sprintf("Failed to create Go module: %s: %s", module, err))
 	}
-	cmdOutputString := strings.TrimSpace(string(cmdOutput))
-	if cmdOutputString == "" {
-		return errors.New("`git config --global user.email` must be set"), nil
+
+	// Parse flags
+	opts := create.Options{
+		Module:           module,
+		OutputDir:        c.String("output-dir"),
+		FullPath:         c.Bool("full-path"),
+		Archive:          c.String("archive"),
+		ArchiveOutput:    c.App.Writer,
+		InPlace:          inCurrentDir,
+		Force:            c.Bool("force"),
+		Resume:           c.Bool("resume"),
+		GitExec:          c.Bool("git-exec"),
+		CreateRemote:     c.Bool("create-remote"),
+		PrivateRemote:    c.Bool("private"),
+		PublicRemote:     c.Bool("public"),
+		Push:             c.Bool("push"),
+		RemoteProtocol:   c.String("remote-protocol"),
+		Bundle:           c.Bool("bundle"),
+		BundleOnly:       c.Bool("bundle-only"),
+		CI:               c.String("ci"),
+		FmtCheck:         c.String("fmt-check"),
+		GoVersion:        c.String("go-version"),
+		Toolchain:        c.String("toolchain"),
+		InContainer:      c.Bool("in-container"),
+		GoToolchain:      o.goToolchain,
+		GoProxy:          c.String("goproxy"),
+		PrivateProxy:     c.String("private-proxy"),
+		License:          c.String("license"),
+		Author:           cfg.Author,
+		Static:           c.Bool("static"),
+		Reproducible:     c.Bool("reproducible"),
+		EmbedAssets:      c.Bool("embed-assets"),
+		I18n:             c.Bool("i18n"),
+		FeatureFlags:     c.String("feature-flags"),
+		Notice:           c.Bool("notice"),
+		Layout:           c.String("layout"),
+		Golden:           c.Bool("golden"),
+		Mutation:         c.Bool("mutation"),
+		SecretScan:       c.Bool("secret-scan"),
+		Scorecard:        c.Bool("scorecard"),
+		SplitCmd:         c.Bool("split-cmd"),
+		Examples:         c.Bool("examples"),
+		PropTests:        c.Bool("prop-tests"),
+		Lint:             c.Bool("lint"),
+		Linters:          cfg.Lint.Linters,
+		PGO:              c.Bool("pgo"),
+		GoReleaser:       c.Bool("goreleaser"),
+		Make:             c.Bool("make"),
+		Taskfile:         c.Bool("taskfile"),
+		Targets:          commaList(c.String("targets")),
+		Scripts:          c.Bool("scripts"),
+		PowerShell:       c.Bool("powershell"),
+		BootstrapScript:  c.Bool("bootstrap-script"),
+		Docker:           c.Bool("docker"),
+		CloudDev:         c.String("cloud-dev"),
+		NoDeps:           c.Bool("no-deps"),
+		FailOnVuln:       c.Bool("fail-on-vuln"),
+		Flags:            manifestFlags(c),
+		Skip:             stageList(c.String("skip")),
+		Only:             stageList(c.String("only")),
+		GitInitialBranch: o.gitInitialBranch,
+		Clock:            o.clock,
+		RunOptions:       runOptionsFromFlags(c, o.output, o.outputIsTerminal()),
 	}
 
-	// Ensure Git user.name is set
-	cmd = exec.Command("git", "config", "--global", "user.name")
-	cmd.Dir = moduleBase
-	cmdOutput, err = cmd.Output()
-	if err != nil {
-		return errors.New("Failed to look up Git user.name"), nil
+	// Flags override the config file, so that e.g. --git=false skips a configured Git repository
+	// An archive has no Git repository, so a configured one is left out
+	opts.Git = cfg.Git && !c.IsSet("archive")
+	if c.IsSet("git") {
+		opts.Git = c.Bool("git")
 	}
-	cmdOutputString = strings.TrimSpace(string(cmdOutput))
-	if cmdOutputString == "" {
-		return errors.New("`git config --global user.name` must be set"), nil
+	if !c.IsSet("make") {
+		opts.Make = cfg.Make
 	}
-
-	// Initialize Git repository
-	cmd = exec.Command("git", "init")
-	if repo.initialBranch != nil {
-		cmd = exec.Command("git", "init", "--initial-branch", *repo.initialBranch)
+	if !c.IsSet("taskfile") {
+		opts.Taskfile = cfg.Taskfile
+	}
+	if !c.IsSet("toolchain") {
+		opts.Toolchain = cfg.Toolchain
+	}
+	if !c.IsSet("private-proxy") {
+		opts.PrivateProxy = cfg.PrivateProxy
 	}
-	cmd.Dir = moduleBase
-	if err := cmd.Run(); err != nil {
-		return errors.New("Failed to initialize Git repository"), nil
+	if !c.IsSet("remote-protocol") {
+		opts.RemoteProtocol = cfg.RemoteProtocol
+	}
+	for _, editor := range create.Editors() {
+		useEditor := cfg.Editor == editor.Name
+		if c.IsSet(editor.Name) {
+			useEditor = c.Bool(editor.Name)
+		}
+		if useEditor {
+			opts.Editors = append(opts.Editors, editor.Name)
+		}
 	}
-	flogln(output, quiet, "- Initialized Git repository")
 
-	// Create README.md (with title)
-	readmeFilePath := filepath.Join(moduleBase, readmeFileName)
-	readmeContent := fmt.Sprintf("# %s\n\n", moduleBase)
-	err = os.WriteFile(readmeFilePath, []byte(readmeContent), 0644)
-	if err != nil {
-		return err, nil
-	}
-	reportCreatedFile(output, quiet, readmeFilePath)
-
-	// Commit all files to Git repository
-	cmd = exec.Command("git", "add", ".")
-	cmd.Dir = moduleBase
-	if err = cmd.Run(); err != nil {
-		return errors.New("Failed to stage files for Git commit"), nil
-	}
-	cmd = exec.Command("git", "commit", "-m", "Initial commit")
-	cmd.Dir = moduleBase
-	if err = cmd.Run(); err != nil {
-		errorMessage := fmt.Sprintf("Failed to commit files into Git repository")
-		return errors.New(errorMessage), nil
-	}
-	flogln(output, quiet, "- Committed all files to Git repository")
-
-	// Add Git repository remote
-	gitUrlCore := strings.Replace(module, "/", ":", 1)
-	var gitUrl string
-	if gitUrlCore != module {
-		gitUrl = fmt.Sprintf("git@%s.git", gitUrlCore)
-		cmd = exec.Command("git", "remote", "add", "origin", gitUrl)
-		cmd.Dir = moduleBase
-		if err = cmd.Run(); err != nil {
-			return errors.New("Failed to stage files for Git commit"), nil
+	// Expand a bare module name with the configured (or inferred) prefix
+	if !c.Bool("local") && !strings.Contains(module, "/") {
+		modulePrefix := cfg.ModulePrefix
+		infer := cfg.Infer
+		if c.IsSet("infer") {
+			infer = c.Bool("infer")
 		}
-		flogf(output, quiet, "- Added remote for Git repository: %s\n", gitUrl)
-	} else {
-		flogln(output, quiet, "- NOTE: Unable to add remote for Git repository")
+		if infer {
+			login, err := create.GitHubLogin(ctx, opts.GitExec)
+			if err != nil {
+				return create.Options{}, errors.New(fmt.Sprintf("Failed to create Go module: %s: %s", module, err))
+			}
+			modulePrefix = path.Join("github.com", login)
+		}
+		if modulePrefix != "" {
+			opts.ExpandedFrom = module
+			opts.Module = path.Join(modulePrefix, module)
+		}
+	}
+
+	return opts, nil
+}
+
+// stageList splits a comma-separated list of stages (e.g. "git,deps"), which are case-insensitive
+func stageList(list string) []string {
+	stages := commaList(list)
+	for i, name := range stages {
+		stages[i] = strings.ToLower(name)
 	}
+	return stages
+}
 
-	// Add next step: Create remote repository
-	nextStepCreateRemote := "Create remote Git repository"
-	if len(gitUrl) > 0 {
-		nextStepCreateRemote += fmt.Sprintf(" %s", gitUrl)
-		if strings.Contains(gitUrl, "github.com") {
-			nextStepCreateRemote += ": https://github.com/new"
+// commaList splits a comma-separated list (e.g. "linux/amd64,windows/amd64"), dropping blank items
+func commaList(list string) []string {
+	items := []string{}
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
 		}
 	}
-	nextSteps = append(nextSteps, nextStepCreateRemote)
-
-	// Add next step: Push to remote
-	cmd = exec.Command("git", "symbolic-ref", "--short", "HEAD")
-	cmd.Dir = moduleBase
-	cmdOutput, err = cmd.Output()
-	if err == nil {
-		cmdOutputString = strings.TrimSpace(string(cmdOutput))
-		nextStepPush := "Push to remote Git repository: $ git push -u origin "
-		if cmdOutputString != "" {
-			nextStepPush += cmdOutputString
-		} else {
-			nextStepPush += "$(git branch --show-current)"
+	return items
+}
+
+// readBatchFile reads module names from a file ("-" for standard input), one per line. Blank lines and # comments are
+// ignored.
+func readBatchFile(name string) ([]string, error) {
+	var content []byte
+	var err error
+	if name == "-" {
+		content, err = io.ReadAll(os.Stdin)
+	} else {
+		content, err = os.ReadFile(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	modules := []string{}
+	for _, line := range strings.Split(string(content), "\n") {
+		module := strings.TrimSpace(line)
+		if module != "" && !strings.HasPrefix(module, "#") {
+			modules = append(modules, module)
 		}
-		nextSteps = append(nextSteps, nextStepPush)
 	}
+	return modules, nil
+}
 
-	return nil, nextSteps
+// runOptionsFromFlags returns how to run and report creation to output (shown on a terminal, or not), as set by
+// outputFlags
+func runOptionsFromFlags(c *cli.Context, output io.Writer, terminal bool) create.RunOptions {
+	opts := create.RunOptions{
+		DryRun:      c.Bool("dry-run"),
+		KeepPartial: c.Bool("keep-partial"),
+		JSON:        c.Bool("json"),
+		Terminal:    terminal,
+	}
+	if !isQuiet(c) {
+		opts.Output = output
+	}
+	return opts
 }
 
-func flogf(output io.Writer, quiet bool, format string, a ...any) {
-	if !quiet {
-		fmt.Fprintf(output, format, a...)
+// withTimeout returns a context that is canceled after --timeout, if it's set
+func withTimeout(ctx context.Context, c *cli.Context) (context.Context, context.CancelFunc) {
+	if !c.IsSet("timeout") {
+		return context.WithCancel(ctx)
 	}
+	return context.WithTimeout(ctx, c.Duration("timeout"))
 }
 
-func flogln(output io.Writer, quiet bool, a ...any) {
-	if !quiet {
-		fmt.Fprintln(output, a...)
+// withVerbose returns a context that traces the commands run to error output, if --verbose is set
+func withVerbose(ctx context.Context, c *cli.Context) context.Context {
+	if !c.Bool("verbose") || isQuiet(c) {
+		return ctx
 	}
+	return create.WithTrace(ctx, c.App.ErrWriter)
 }
 
-func reportCreatedAtPath(output io.Writer, quiet bool, fileType string, filePath string) {
-	flogf(output, quiet, "- Created %-9s: %s\n", fileType, filePath)
+// withLogFile returns a context that logs what is done to the file set by --log-file (or the config file), appending to
+// it, and a function that closes the file
+func withLogFile(ctx context.Context, c *cli.Context) (context.Context, func(), error) {
+	path := c.String("log-file")
+	if !c.IsSet("log-file") {
+		// An invalid config file is reported where its other settings are read, so it's only skipped here
+		if cfg, err := loadConfig(); err == nil {
+			path = cfg.LogFile
+		}
+	}
+	if path == "" {
+		return ctx, func() {}, nil
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, nil, errors.New(fmt.Sprintf("Failed to open log file: %s", err))
+	}
+	return create.WithLog(ctx, f), func() { f.Close() }, nil
 }
 
-func reportCreatedDir(output io.Writer, quiet bool, filePath string) {
-	reportCreatedAtPath(output, quiet, "directory", filePath)
+// interruptible returns a context that is canceled on Ctrl-C (or SIGTERM), so that creation can stop and remove what
+// was created, rather than leave a half-created module
+func interruptible(c *cli.Context) (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(c.Context, os.Interrupt, syscall.SIGTERM)
 }
 
-func reportCreatedFile(output io.Writer, quiet bool, filePath string) {
-	reportCreatedAtPath(output, quiet, "file", filePath)
+// outputFlags returns the flags that control how a plan is run and reported
+func outputFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "print what would be created without creating anything",
+		},
+		&cli.BoolFlag{
+			Name:  "keep-partial",
+			Usage: "keep what was created when creation fails partway, instead of removing it",
+		},
+		&cli.DurationFlag{
+			Name:  "timeout",
+			Usage: "give up, removing what was created, if creating takes longer than this (e.g. 2m)",
+		},
+		&cli.BoolFlag{
+			Name:  "json",
+			Usage: "print a JSON report instead of progress output",
+		},
+		&cli.StringFlag{
+			Name:  "color",
+			Usage: "color output: auto (on a terminal, unless $NO_COLOR is set), always, never",
+			Value: "auto",
+		},
+		&cli.BoolFlag{
+			Name:    "verbose",
+			Usage:   "print each command run (e.g. git, go), with where and how long it ran, to error output",
+			Aliases: []string{"V"},
+		},
+		&cli.StringFlag{
+			Name:  "log-file",
+			Usage: "append a timestamped log of every step and command run (with its error output) to this file",
+		},
+		&cli.BoolFlag{
+			Name:    "quiet",
+			Usage:   "silence progress output, still reporting errors",
+			Aliases: []string{"q"},
+		},
+		&cli.BoolFlag{
+			Name:  "silent",
+			Usage: "silence all output, including errors (the exit code still reports failure)",
+		},
+	}
+}
+
+// isQuiet reports whether progress output is silenced, by --quiet or --silent
+func isQuiet(c *cli.Context) bool {
+	return c.Bool("quiet") || c.Bool("silent")
 }
 
-func withoutFilepathPrefix(filePath string, filePathPrefix string) string {
-	filePathPrefixWithSeparator := filePathPrefix + string(filepath.Separator)
-	return strings.TrimPrefix(filePath, filePathPrefixWithSeparator)
+func concatFlags(flagLists ...[]cli.Flag) []cli.Flag {
+	flags := []cli.Flag{}
+	for _, flagList := range flagLists {
+		flags = append(flags, flagList...)
+	}
+	return flags
 }
 
-func getVersion() string {
-	info, _ := debug.ReadBuildInfo()
-	version := info.Main.Version
-	if version == "" {
-		version = "(devel)"
+func flogf(output io.Writer, quiet bool, format string, a ...any) {
+	if !quiet {
+		fmt.Fprintf(output, format, a...)
+	}
+}
+
+func flogln(output io.Writer, quiet bool, a ...any) {
+	if !quiet {
+		fmt.Fprintln(output, a...)
 	}
-	return version
 }
@@ -2,13 +2,17 @@ package cli_test
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/jbrudvik/gmc/cli"
 )
@@ -16,11 +20,15 @@ import (
 const editor string = "vim"
 const gitBranchName string = "main"
 
+// Time that license years are read from
+var licenseTime = time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)
+
 var helpOutput string = fmt.Sprintf("NAME:\n"+
 	"   %s - (Go mod create) creates Go modules\n"+
 	"\n"+
 	"USAGE:\n"+
-	"   %s [global options] [module name]\n"+
+	"   %s [global options] [module name...]\n"+
+	"   %s command [command options] [arguments...]\n"+
 	"\n"+
 	"VERSION:\n"+
 	"   %s\n"+
@@ -29,6 +37,7 @@ var helpOutput string = fmt.Sprintf("NAME:\n"+
 	"   `%s [module name]` creates a directory containing:\n"+
 	"   - Go module metadata: go.mod\n"+
 	"   - A place to start writing code: main.go\n"+
+	"   - A test that builds and runs it: main_test.go\n"+
 	"   - A .gitignore file\n"+
 	"   \n"+
 	"   This module can be immediately run:\n"+
@@ -38,14 +47,125 @@ var helpOutput string = fmt.Sprintf("NAME:\n"+
 	"   \n"+
 	"   Optionally, the directory can also include:\n"+
 	"   - Git repository setup with .gitignore, README.md\n"+
+	"   - A LICENSE file\n"+
+	"   - A CI workflow\n"+
+	"   - A golangci-lint configuration\n"+
+	"   - A GoReleaser release configuration\n"+
+	"   - A Dockerfile\n"+
+	"   - A Makefile\n"+
+	"   - A Taskfile\n"+
+	"   - Scripts to bootstrap, build, test, and run (script/), optionally with PowerShell equivalents\n"+
+	"   - A bootstrap script for Linux machines\n"+
+	"   - Embedded assets (go:embed)\n"+
+	"   - Translated messages (golang.org/x/text)\n"+
+	"   - Feature flags (OpenFeature)\n"+
+	"   - Golden file tests\n"+
+	"   - Mutation testing (Gremlins)\n"+
+	"   - Secret scanning (gitleaks)\n"+
+	"   - OpenSSF Scorecard, with a Best Practices badge checklist\n"+
+	"   - Visual Studio Code, GoLand, or Neovim configuration\n"+
+	"   - A Gitpod or Codespaces configuration\n"+
 	"   \n"+
 	"   More information: %s\n"+
 	"\n"+
+	"COMMANDS:\n"+
+	"   new           create a Go module in a new directory (the default command)\n"+
+	"   init          create a Go module in the current directory, keeping any files already there\n"+
+	"   add           add a feature to the Go module in the current directory\n"+
+	"   adopt         bring the Go module in the current directory, which gmc didn't create, under gmc's management\n"+
+	"   plan          print what creating a Go module would do as JSON, to review, and then carry out with `gmc apply`\n"+
+	"   apply         carry out a plan saved from `gmc plan` (- for standard input)\n"+
+	"   config        print where the config file is read from, and the settings in effect\n"+
+	"   serve         create Go modules on request, over HTTP\n"+
+	"   templates     list the templates modules can be created from, with where each comes from and what it adds\n"+
+	"   audit         report which modules in directories of repositories are outdated, missing required features, or changed\n"+
+	"   release-prep  check that the Go module in the current directory is ready to release, then tag the release\n"+
+	"   doctor        check that the tools and settings gmc uses are installed and configured\n"+
+	"   upgrade-self  replace gmc with its latest release\n"+
+	"   completion    print a shell completion script: bash, zsh, fish, powershell\n"+
+	"   man           print the man page, generated from this help\n"+
+	"   help          show help, or a help topic: config, manifest, remote, templates\n"+
+	"\n"+
 	"GLOBAL OPTIONS:\n"+
-	"   --git, -g      create as Git repository (default: false)\n"+
-	"   --quiet, -q    silence output (default: false)\n"+
-	"   --help, -h     show help (default: false)\n"+
-	"   --version, -v  print the version (default: false)\n",
+	"   --local                       keep a module name without a slash as is, instead of adding the configured prefix (default: false)\n"+
+	"   --infer                       add github.com/<your GitHub login> to a module name without a slash (default: false)\n"+
+	"   --output-dir value, -C value  create the module in this directory, instead of the current one\n"+
+	"   --full-path                   create the module's directory at its full module path (e.g. github.com/you/mymodule), instead of its last element (default: false)\n"+
+	"   --force                       create the module in its directory even if the directory exists, keeping any files already there (default: false)\n"+
+	"   --resume                      finish creating a module whose creation was interrupted, skipping what was already done (default: false)\n"+
+	"   --skip value                  skip these stages (comma-separated): files, deps, git\n"+
+	"   --only value                  run only these stages (comma-separated): files, deps, git\n"+
+	"   --git, -g                     create as Git repository (default: false)\n"+
+	"   --git-exec                    run the git executable for Git actions, instead of the built-in implementation (default: false)\n"+
+	"   --create-remote               create the remote repository on GitHub (with $GITHUB_TOKEN, or gh's login), named by the module path, and add it as origin (requires --git) (default: false)\n"+
+	"   --private                     make the remote repository created by --create-remote private (the default) (default: false)\n"+
+	"   --public                      make the remote repository created by --create-remote public (default: false)\n"+
+	"   --push                        push the initial commit to the remote, once created by --create-remote or if it already exists (requires --git) (default: false)\n"+
+	"   --remote-protocol value       add the Git remote with an ssh or https URL (default: ssh)\n"+
+	"   --bundle                      also write the Git repository to <name>.bundle next to the module's directory, to clone elsewhere (implies --git) (default: false)\n"+
+	"   --bundle-only                 write the Git repository to <name>.bundle as --bundle does, and then remove the module's directory (default: false)\n"+
+	"   --ci value                    add a CI workflow: github, gitlab, auto\n"+
+	"   --fmt-check value             check formatting in CI with gofmt or gofumpt, and files against an added .editorconfig (requires --ci)\n"+
+	"   --go-version value            pin the Go version for go.mod, CI, and the Dockerfile (e.g. 1.21) instead of using the installed version\n"+
+	"   --toolchain value             pin the Go toolchain (e.g. go1.22.3) in go.mod, and with GOTOOLCHAIN in an .envrc and CI, so that it's used even where an older Go is installed\n"+
+	"   --in-container                run Go commands in a golang container (with Docker or Podman) instead of with the installed Go (default: false)\n"+
+	"   --provision-go                if Go isn't installed, download the official Go (checksum-verified) into gmc's cache and run Go commands with it, without asking (default: false)\n"+
+	"   --goproxy value               fetch dependencies from this module proxy (e.g. a private Athens or Artifactory URL) instead of the GOPROXY in go env\n"+
+	"   --private-proxy value         resolve modules only through this private proxy (e.g. an Athens or Artifactory URL), documenting its setup, and configuring .envrc and CI to use it\n"+
+	"   --static                      build and verify a fully static binary in CI (default: false)\n"+
+	"   --reproducible                build byte-for-byte reproducible binaries, and verify in CI that two builds match (default: false)\n"+
+	"   --embed-assets                add an assets directory embedded into the binary with go:embed (default: false)\n"+
+	"   --i18n                        add translated messages with golang.org/x/text (default: false)\n"+
+	"   --feature-flags value         add feature flags with an environment variable provider: openfeature\n"+
+	"   --notice                      generate NOTICE, attributing the dependencies added (with --i18n or --feature-flags), with go-licenses, and a make/task target that regenerates it (default: false)\n"+
+	"   --layout value                add a package layout: apiv1, for a public API in api/v1 that a v2 can be added beside\n"+
+	"   --golden                      add a golden file test helper and an example test (default: false)\n"+
+	"   --mutation                    add a Gremlins mutation testing configuration, with a CI job and make/task target (default: false)\n"+
+	"   --secret-scan                 configure gitleaks secret scanning, with a pre-commit hook the Git repository runs from its first commit, and a CI job (default: false)\n"+
+	"   --scorecard                   add an OpenSSF Scorecard workflow and README badge, and a Best Practices badge checklist (requires a module under github.com) (default: false)\n"+
+	"   --lint                        add a golangci-lint configuration, and run it in CI (default: false)\n"+
+	"   --pgo                         add a default.pgo profile for profile-guided optimization (default: false)\n"+
+	"   --goreleaser                  add a GoReleaser configuration and release workflow (default: false)\n"+
+	"   --make                        add a Makefile with build, test, lint, fmt, run, and clean targets (default: false)\n"+
+	"   --taskfile                    add a Taskfile.yml with the same tasks as --make (default: false)\n"+
+	"   --targets value               build for these platforms (comma-separated, e.g. linux/amd64,darwin/arm64,windows/amd64) with cgo disabled: into bin/dist/<os>_<arch>/ with a dist target in the Makefile and Taskfile, and with GoReleaser\n"+
+	"   --scripts                     add script/bootstrap, script/build, script/test, and script/server, used by --make, --taskfile, and CI (default: false)\n"+
+	"   --powershell                  add PowerShell equivalents of the --scripts scripts, and also run CI on windows-latest (default: false)\n"+
+	"   --bootstrap-script            add a script/bootstrap that installs the Go toolchain and tools on a fresh Linux machine (default: false)\n"+
+	"   --docker                      add a Dockerfile and .dockerignore (default: false)\n"+
+	"   --license value               add a LICENSE file: mit, apache-2.0, bsd-3-clause\n"+
+	"   --vscode                      add Visual Studio Code settings, launch configuration, and tasks (default: false)\n"+
+	"   --goland                      add GoLand run configurations for build, run, and test (default: false)\n"+
+	"   --nvim                        add a project-local Neovim configuration for gopls and debugging (default: false)\n"+
+	"   --cloud-dev value             add a prebuilt cloud development environment: gitpod, codespaces\n"+
+	"   --split-cmd                   create a library, with its command in a separate module in cmd/<name> that requires it through a replace directive (default: false)\n"+
+	"   --examples                    add a runnable example program in a separate module in examples/, which CI builds (requires --split-cmd) (default: false)\n"+
+	"   --prop-tests                  add an example property-based test of the library with rapid (requires --split-cmd) (default: false)\n"+
+	"   --no-deps                     fail unless only the standard library is used (default: false)\n"+
+	"   --fail-on-vuln                fail if govulncheck finds known vulnerabilities in the dependencies added, instead of noting them (default: false)\n"+
+	"   --batch value                 also create each module named in a file, one per line (- for standard input)\n"+
+	"   --archive value               write the module to an archive (e.g. out.tar.gz or out.zip, or - for a tarball on standard output) instead of a directory, without Git\n"+
+	"   --dry-run                     print what would be created without creating anything (default: false)\n"+
+	"   --keep-partial                keep what was created when creation fails partway, instead of removing it (default: false)\n"+
+	"   --timeout value               give up, removing what was created, if creating takes longer than this (e.g. 2m) (default: 0s)\n"+
+	"   --json                        print a JSON report instead of progress output (default: false)\n"+
+	"   --color value                 color output: auto (on a terminal, unless $NO_COLOR is set), always, never (default: \"auto\")\n"+
+	"   --verbose, -V                 print each command run (e.g. git, go), with where and how long it ran, to error output (default: false)\n"+
+	"   --log-file value              append a timestamped log of every step and command run (with its error output) to this file\n"+
+	"   --quiet, -q                   silence progress output, still reporting errors (default: false)\n"+
+	"   --silent                      silence all output, including errors (the exit code still reports failure) (default: false)\n"+
+	"   --help, -h                    show help (default: false)\n"+
+	"   --version, -v                 print the version (default: false)\n"+
+	"\n"+
+	"EXIT CODES:\n"+
+	"   0    success\n"+
+	"   1    failure\n"+
+	"   2    usage error (e.g. an unknown flag or an invalid module name)\n"+
+	"   3    filesystem conflict (e.g. the module's directory already exists)\n"+
+	"   4    Git failure (e.g. `git config --global user.email` is not set)\n"+
+	"   5    Go toolchain failure (e.g. go is not installed)\n"+
+	"   130  interrupted\n",
+	cli.Name,
 	cli.Name,
 	cli.Name,
 	cli.Version,
@@ -53,6 +173,39 @@ var helpOutput string = fmt.Sprintf("NAME:\n"+
 	cli.Url,
 )
 
+var addHelpOutput string = fmt.Sprintf("NAME:\n"+
+	"   %s add - add a feature to the Go module in the current directory\n"+
+	"\n"+
+	"USAGE:\n"+
+	"   %s add [command options] [feature] [argument]\n"+
+	"\n"+
+	"DESCRIPTION:\n"+
+	"   Features: git, license, ci, make, taskfile, docker, vscode, goland, nvim\n"+
+	"   \n"+
+	"       $ %s add license mit\n"+
+	"       $ %s add ci github\n"+
+	"   \n"+
+	"   Existing files are never overwritten.\n"+
+	"\n"+
+	"OPTIONS:\n"+
+	"   --git-exec        run the git executable for Git actions, instead of the built-in implementation (default: false)\n"+
+	"   --dry-run         print what would be created without creating anything (default: false)\n"+
+	"   --keep-partial    keep what was created when creation fails partway, instead of removing it (default: false)\n"+
+	"   --timeout value   give up, removing what was created, if creating takes longer than this (e.g. 2m) (default: 0s)\n"+
+	"   --json            print a JSON report instead of progress output (default: false)\n"+
+	"   --color value     color output: auto (on a terminal, unless $NO_COLOR is set), always, never (default: \"auto\")\n"+
+	"   --verbose, -V     print each command run (e.g. git, go), with where and how long it ran, to error output (default: false)\n"+
+	"   --log-file value  append a timestamped log of every step and command run (with its error output) to this file\n"+
+	"   --quiet, -q       silence progress output, still reporting errors (default: false)\n"+
+	"   --silent          silence all output, including errors (the exit code still reports failure) (default: false)\n"+
+	"   --help, -h        show help (default: false)\n"+
+	"   \n",
+	cli.Name,
+	cli.Name,
+	cli.Name,
+	cli.Name,
+)
+
 var versionOutput string = fmt.Sprintf("%s version %s\n", cli.Name, cli.Version)
 
 const mainGoContents string = "package main\n" +
@@ -65,12 +218,831 @@ const mainGoContents string = "package main\n" +
 	"	fmt.Println(\"hello, world!\")\n" +
 	"}\n"
 
+const mainTestGoContents string = "package main\n" +
+	"\n" +
+	"import (\n" +
+	"	\"bytes\"\n" +
+	"	\"errors\"\n" +
+	"	\"os/exec\"\n" +
+	"	\"path/filepath\"\n" +
+	"	\"runtime\"\n" +
+	"	\"testing\"\n" +
+	")\n" +
+	"\n" +
+	"// TestExecutable builds the executable, runs it with each test case's arguments, and checks what it prints and how it\n" +
+	"// exits\n" +
+	"func TestExecutable(t *testing.T) {\n" +
+	"	tests := []struct {\n" +
+	"		args             []string\n" +
+	"		expectedExitCode int\n" +
+	"		expectedStdout   string\n" +
+	"		expectedStderr   string\n" +
+	"	}{\n" +
+	"		{\n" +
+	"			args:             nil,\n" +
+	"			expectedExitCode: 0,\n" +
+	"			expectedStdout:   \"hello, world!\\n\",\n" +
+	"			expectedStderr:   \"\",\n" +
+	"		},\n" +
+	"	}\n" +
+	"\n" +
+	"	// Build executable into a temporary directory (automatically cleaned up)\n" +
+	"	executablePath := filepath.Join(t.TempDir(), \"main\")\n" +
+	"	if runtime.GOOS == \"windows\" {\n" +
+	"		executablePath += \".exe\"\n" +
+	"	}\n" +
+	"	buildOutput, err := exec.Command(\"go\", \"build\", \"-o\", executablePath, \".\").CombinedOutput()\n" +
+	"	if err != nil {\n" +
+	"		t.Fatalf(\"Unable to build executable: %s\\n%s\", err, buildOutput)\n" +
+	"	}\n" +
+	"\n" +
+	"	for _, tc := range tests {\n" +
+	"		// Run executable and test outputs\n" +
+	"		cmd := exec.Command(executablePath, tc.args...)\n" +
+	"		var stdout bytes.Buffer\n" +
+	"		var stderr bytes.Buffer\n" +
+	"		cmd.Stdout = &stdout\n" +
+	"		cmd.Stderr = &stderr\n" +
+	"		err := cmd.Run()\n" +
+	"		exitCode := 0\n" +
+	"		var exitError *exec.ExitError\n" +
+	"		if errors.As(err, &exitError) {\n" +
+	"			exitCode = exitError.ExitCode()\n" +
+	"		} else if err != nil {\n" +
+	"			t.Fatalf(\"Unable to run executable: %s\", err)\n" +
+	"		}\n" +
+	"		if exitCode != tc.expectedExitCode {\n" +
+	"			t.Errorf(\"%v: exit code = %d, want %d\", tc.args, exitCode, tc.expectedExitCode)\n" +
+	"		}\n" +
+	"		if stdout.String() != tc.expectedStdout {\n" +
+	"			t.Errorf(\"%v: stdout = %q, want %q\", tc.args, stdout.String(), tc.expectedStdout)\n" +
+	"		}\n" +
+	"		if stderr.String() != tc.expectedStderr {\n" +
+	"			t.Errorf(\"%v: stderr = %q, want %q\", tc.args, stderr.String(), tc.expectedStderr)\n" +
+	"		}\n" +
+	"	}\n" +
+	"}\n"
+
+const githubWorkflowContents string = "name: CI\n" +
+	"on: [push, pull_request]\n" +
+	"jobs:\n" +
+	"  Build:\n" +
+	"    runs-on: ubuntu-latest\n" +
+	"    steps:\n" +
+	"      - name: Git checkout\n" +
+	"        uses: actions/checkout@v4\n" +
+	"      - name: Set up Go\n" +
+	"        uses: actions/setup-go@v5\n" +
+	"        with:\n" +
+	"          go-version-file: go.mod\n" +
+	"      - name: Build\n" +
+	"        run: go build ./...\n" +
+	"      - name: Lint\n" +
+	"        run: go vet ./...\n" +
+	"      - name: Test\n" +
+	"        run: go test ./...\n"
+
+const githubWorkflowStaticContents string = githubWorkflowContents +
+	"      - name: Build static binary\n" +
+	"        run: go build -tags netgo,osusergo -o a1 .\n" +
+	"        env:\n" +
+	"          CGO_ENABLED: 0\n" +
+	"      - name: Check binary is static\n" +
+	"        run: ldd a1 2>&1 | grep -q \"not a dynamic executable\"\n"
+
+const githubWorkflowReproducibleContents string = githubWorkflowContents +
+	"  Reproducible:\n" +
+	"    runs-on: ubuntu-latest\n" +
+	"    steps:\n" +
+	"      - name: Git checkout\n" +
+	"        uses: actions/checkout@v4\n" +
+	"      - name: Set up Go\n" +
+	"        uses: actions/setup-go@v5\n" +
+	"        with:\n" +
+	"          go-version-file: go.mod\n" +
+	"      - name: Build\n" +
+	"        run: go build -trimpath -o \"$RUNNER_TEMP/first\" .\n" +
+	"        env:\n" +
+	"          CGO_ENABLED: 0\n" +
+	"      - name: Build again, from another directory with an empty build cache\n" +
+	"        run: |\n" +
+	"          cp -R . \"$RUNNER_TEMP/src\"\n" +
+	"          cd \"$RUNNER_TEMP/src\"\n" +
+	"          go build -trimpath -o \"$RUNNER_TEMP/second\" .\n" +
+	"        env:\n" +
+	"          CGO_ENABLED: 0\n" +
+	"          GOCACHE: ${{ runner.temp }}/cache\n" +
+	"      - name: Check builds are identical\n" +
+	"        run: cmp \"$RUNNER_TEMP/first\" \"$RUNNER_TEMP/second\"\n"
+
+const envrcContents string = "# Go settings for this module, loaded by direnv (https://direnv.net)\n" +
+	"# Build with the toolchain pinned in go.mod, even where another Go is installed\n" +
+	"export GOTOOLCHAIN=go1.22.3\n"
+
+const gitlabCiContents string = "image: golang:%s\n" +
+	"\n" +
+	"stages:\n" +
+	"  - build\n" +
+	"  - lint\n" +
+	"  - test\n" +
+	"\n" +
+	"build:\n" +
+	"  stage: build\n" +
+	"  script:\n" +
+	"    - go build ./...\n" +
+	"\n" +
+	"lint:\n" +
+	"  stage: lint\n" +
+	"  script:\n" +
+	"    - go vet ./...\n" +
+	"\n" +
+	"test:\n" +
+	"  stage: test\n" +
+	"  script:\n" +
+	"    - go test ./...\n"
+
+const pgoDocContents string = "# Profile-guided optimization\n" +
+	"\n" +
+	"`default.pgo` is the CPU profile `go build` uses to optimize a1. It starts out\n" +
+	"empty, which builds exactly as if there were no profile.\n" +
+	"\n" +
+	"## Collect a profile\n" +
+	"\n" +
+	"Profile a representative workload, e.g. a benchmark:\n" +
+	"\n" +
+	"```sh\n" +
+	"$ go test -run '^$' -bench . -cpuprofile cpu.pprof .\n" +
+	"```\n" +
+	"\n" +
+	"or a running service, via [net/http/pprof](https://pkg.go.dev/net/http/pprof):\n" +
+	"\n" +
+	"```sh\n" +
+	"$ curl -o cpu.pprof \"http://localhost:6060/debug/pprof/profile?seconds=30\"\n" +
+	"```\n" +
+	"\n" +
+	"## Refresh default.pgo\n" +
+	"\n" +
+	"Merge one or more profiles into `default.pgo` and commit it:\n" +
+	"\n" +
+	"```sh\n" +
+	"$ go tool pprof -proto cpu.pprof > default.pgo\n" +
+	"```\n" +
+	"\n" +
+	"## Build with the profile\n" +
+	"\n" +
+	"`go build` picks up `default.pgo` from the main package's directory automatically (`-pgo=auto`,\n" +
+	"Go 1.21+). CI builds pass `-pgo=auto` explicitly.\n" +
+	"\n" +
+	"More information: https://go.dev/doc/pgo\n"
+
+const goreleaserConfigContents string = "version: 2\n" +
+	"\n" +
+	"project_name: bar\n" +
+	"\n" +
+	"before:\n" +
+	"  hooks:\n" +
+	"    - go mod tidy\n" +
+	"\n" +
+	"builds:\n" +
+	"  - main: .\n" +
+	"    binary: bar\n" +
+	"    env:\n" +
+	"      - CGO_ENABLED=0\n" +
+	"    goos:\n" +
+	"      - linux\n" +
+	"      - darwin\n" +
+	"      - windows\n" +
+	"    goarch:\n" +
+	"      - amd64\n" +
+	"      - arm64\n" +
+	"\n" +
+	"archives:\n" +
+	"  - formats: [tar.gz]\n" +
+	"    name_template: \"{{ .ProjectName }}_{{ .Version }}_{{ .Os }}_{{ .Arch }}\"\n" +
+	"    format_overrides:\n" +
+	"      - goos: windows\n" +
+	"        formats: [zip]\n" +
+	"\n" +
+	"checksum:\n" +
+	"  name_template: checksums.txt\n" +
+	"\n" +
+	"release:\n" +
+	"  github:\n" +
+	"    owner: foo\n" +
+	"    name: bar\n"
+
+const releaseWorkflowContents string = "name: Release\n" +
+	"on:\n" +
+	"  push:\n" +
+	"    tags:\n" +
+	"      - \"v*\"\n" +
+	"permissions:\n" +
+	"  contents: write\n" +
+	"jobs:\n" +
+	"  Release:\n" +
+	"    runs-on: ubuntu-latest\n" +
+	"    steps:\n" +
+	"      - name: Git checkout\n" +
+	"        uses: actions/checkout@v4\n" +
+	"        with:\n" +
+	"          fetch-depth: 0\n" +
+	"      - name: Set up Go\n" +
+	"        uses: actions/setup-go@v5\n" +
+	"        with:\n" +
+	"          go-version-file: go.mod\n" +
+	"      - name: Release\n" +
+	"        uses: goreleaser/goreleaser-action@v6\n" +
+	"        with:\n" +
+	"          version: \"~> v2\"\n" +
+	"          args: release --clean\n" +
+	"        env:\n" +
+	"          GITHUB_TOKEN: ${{ secrets.GITHUB_TOKEN }}\n"
+
+const dockerfileStaticContents string = "FROM golang:%s AS build\n" +
+	"WORKDIR /src\n" +
+	"COPY go.mod go.sum* ./\n" +
+	"RUN go mod download\n" +
+	"COPY . .\n" +
+	"RUN CGO_ENABLED=0 go build -tags netgo,osusergo -o /out/a1 .\n" +
+	"\n" +
+	"FROM scratch\n" +
+	"COPY --from=build /out/a1 /a1\n" +
+	"ENTRYPOINT [\"/a1\"]\n"
+
+const makefileContents string = "BINARY := a1\n" +
+	"\n" +
+	".PHONY: build test lint fmt run clean\n" +
+	"\n" +
+	"build:\n" +
+	"\tgo build -o $(BINARY) .\n" +
+	"\n" +
+	"test:\n" +
+	"\tgo test ./...\n" +
+	"\n" +
+	"lint:\n" +
+	"\tgo vet ./...\n" +
+	"\n" +
+	"fmt:\n" +
+	"\tgofmt -w .\n" +
+	"\n" +
+	"run: build\n" +
+	"\t./$(BINARY)\n" +
+	"\n" +
+	"clean:\n" +
+	"\trm -f $(BINARY)\n"
+
+const makefileTargetsContents string = "BINARY := a1\n" +
+	"\n" +
+	".PHONY: build test lint fmt run clean dist dist-linux-amd64 dist-windows-amd64\n" +
+	"\n" +
+	"build:\n" +
+	"\tgo build -o $(BINARY) .\n" +
+	"\n" +
+	"test:\n" +
+	"\tgo test ./...\n" +
+	"\n" +
+	"lint:\n" +
+	"\tgo vet ./...\n" +
+	"\n" +
+	"fmt:\n" +
+	"\tgofmt -w .\n" +
+	"\n" +
+	"run: build\n" +
+	"\t./$(BINARY)\n" +
+	"\n" +
+	"clean:\n" +
+	"\trm -f $(BINARY)\n" +
+	"\trm -rf bin/dist\n" +
+	"\n" +
+	"# Build a binary for each target platform into bin/dist/<os>_<arch>/ (apart from GoReleaser's dist/), with cgo disabled so no C cross-compiler is needed\n" +
+	"dist: dist-linux-amd64 dist-windows-amd64\n" +
+	"\n" +
+	"dist-linux-amd64:\n" +
+	"\tCGO_ENABLED=0 GOOS=linux GOARCH=amd64 go build -o bin/dist/linux_amd64/$(BINARY) .\n" +
+	"\n" +
+	"dist-windows-amd64:\n" +
+	"\tCGO_ENABLED=0 GOOS=windows GOARCH=amd64 go build -o bin/dist/windows_amd64/$(BINARY).exe .\n"
+
+const taskfileContents string = "version: \"3\"\n" +
+	"\n" +
+	"vars:\n" +
+	"  BINARY: a1\n" +
+	"\n" +
+	"tasks:\n" +
+	"  build:\n" +
+	"    cmds:\n" +
+	"      - go build -o {{.BINARY}} .\n" +
+	"\n" +
+	"  test:\n" +
+	"    cmds:\n" +
+	"      - go test ./...\n" +
+	"\n" +
+	"  lint:\n" +
+	"    cmds:\n" +
+	"      - go vet ./...\n" +
+	"\n" +
+	"  fmt:\n" +
+	"    cmds:\n" +
+	"      - gofmt -w .\n" +
+	"\n" +
+	"  run:\n" +
+	"    deps: [build]\n" +
+	"    cmds:\n" +
+	"      - ./{{.BINARY}}\n" +
+	"\n" +
+	"  clean:\n" +
+	"    cmds:\n" +
+	"      - rm -f {{.BINARY}}\n"
+
+const golangciConfigContents string = "version: \"2\"\n" +
+	"\n" +
+	"linters:\n" +
+	"  default: none\n" +
+	"  enable:\n" +
+	"%s"
+
+const githubWorkflowLintContents string = "name: CI\n" +
+	"on: [push, pull_request]\n" +
+	"jobs:\n" +
+	"  Build:\n" +
+	"    runs-on: ubuntu-latest\n" +
+	"    steps:\n" +
+	"      - name: Git checkout\n" +
+	"        uses: actions/checkout@v4\n" +
+	"      - name: Set up Go\n" +
+	"        uses: actions/setup-go@v5\n" +
+	"        with:\n" +
+	"          go-version-file: go.mod\n" +
+	"      - name: Build\n" +
+	"        run: go build ./...\n" +
+	"      - name: Lint\n" +
+	"        run: go vet ./...\n" +
+	"      - name: golangci-lint\n" +
+	"        uses: golangci/golangci-lint-action@v8\n" +
+	"      - name: Test\n" +
+	"        run: go test ./...\n"
+
+const vscodeSettingsContents string = "{\n" +
+	"  \"[go]\": {\n" +
+	"    \"editor.formatOnSave\": true,\n" +
+	"    \"editor.codeActionsOnSave\": {\n" +
+	"      \"source.organizeImports\": \"explicit\"\n" +
+	"    }\n" +
+	"  },\n" +
+	"  \"go.useLanguageServer\": true,\n" +
+	"  \"go.testFlags\": [\"-v\"]\n" +
+	"}\n"
+
+const vscodeLaunchContents string = "{\n" +
+	"  \"version\": \"0.2.0\",\n" +
+	"  \"configurations\": [\n" +
+	"    {\n" +
+	"      \"name\": \"Launch module\",\n" +
+	"      \"type\": \"go\",\n" +
+	"      \"request\": \"launch\",\n" +
+	"      \"mode\": \"auto\",\n" +
+	"      \"program\": \"${workspaceFolder}\"\n" +
+	"    }\n" +
+	"  ]\n" +
+	"}\n"
+
+const vscodeExtensionsContents string = "{\n" +
+	"  \"recommendations\": [\"golang.go\"]\n" +
+	"}\n"
+
+const vscodeTasksContents string = "{\n" +
+	"  \"version\": \"2.0.0\",\n" +
+	"  \"tasks\": [\n" +
+	"    {\n" +
+	"      \"label\": \"go: build\",\n" +
+	"      \"type\": \"shell\",\n" +
+	"      \"command\": \"go build ./...\",\n" +
+	"      \"group\": {\n" +
+	"        \"kind\": \"build\",\n" +
+	"        \"isDefault\": true\n" +
+	"      },\n" +
+	"      \"problemMatcher\": [\"$go\"]\n" +
+	"    },\n" +
+	"    {\n" +
+	"      \"label\": \"go: test\",\n" +
+	"      \"type\": \"shell\",\n" +
+	"      \"command\": \"go test ./...\",\n" +
+	"      \"group\": {\n" +
+	"        \"kind\": \"test\",\n" +
+	"        \"isDefault\": true\n" +
+	"      },\n" +
+	"      \"problemMatcher\": [\"$go\"]\n" +
+	"    }\n" +
+	"  ]\n" +
+	"}\n"
+
+const golandModulesContents string = "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n" +
+	"<project version=\"4\">\n" +
+	"  <component name=\"ProjectModuleManager\">\n" +
+	"    <modules>\n" +
+	"      <module fileurl=\"file://$PROJECT_DIR$/.idea/module.iml\" filepath=\"$PROJECT_DIR$/.idea/module.iml\" />\n" +
+	"    </modules>\n" +
+	"  </component>\n" +
+	"</project>\n"
+
+const golandModuleImlContents string = "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n" +
+	"<module type=\"WEB_MODULE\" version=\"4\">\n" +
+	"  <component name=\"Go\" enabled=\"true\" />\n" +
+	"  <component name=\"NewModuleRootManager\">\n" +
+	"    <content url=\"file://$MODULE_DIR$\">\n" +
+	"      <sourceFolder url=\"file://$MODULE_DIR$\" isTestSource=\"false\" />\n" +
+	"      <excludeFolder url=\"file://$MODULE_DIR$/.git\" />\n" +
+	"    </content>\n" +
+	"    <orderEntry type=\"inheritedJdk\" />\n" +
+	"    <orderEntry type=\"sourceFolder\" forTests=\"false\" />\n" +
+	"  </component>\n" +
+	"</module>\n"
+
+const golandBuildContents string = "<component name=\"ProjectRunConfigurationManager\">\n" +
+	"  <configuration default=\"false\" name=\"Build\" type=\"GoApplicationRunConfiguration\" factoryName=\"Go Application\">\n" +
+	"    <module name=\"module\" />\n" +
+	"    <working_directory value=\"$PROJECT_DIR$\" />\n" +
+	"    <kind value=\"PACKAGE\" />\n" +
+	"    <package value=\"a1\" />\n" +
+	"    <directory value=\"$PROJECT_DIR$\" />\n" +
+	"    <output_directory value=\"$PROJECT_DIR$\" />\n" +
+	"    <run_after_build value=\"false\" />\n" +
+	"    <method v=\"2\" />\n" +
+	"  </configuration>\n" +
+	"</component>\n"
+
+const golandRunContents string = "<component name=\"ProjectRunConfigurationManager\">\n" +
+	"  <configuration default=\"false\" name=\"Run\" type=\"GoApplicationRunConfiguration\" factoryName=\"Go Application\">\n" +
+	"    <module name=\"module\" />\n" +
+	"    <working_directory value=\"$PROJECT_DIR$\" />\n" +
+	"    <kind value=\"PACKAGE\" />\n" +
+	"    <package value=\"a1\" />\n" +
+	"    <directory value=\"$PROJECT_DIR$\" />\n" +
+	"    <method v=\"2\" />\n" +
+	"  </configuration>\n" +
+	"</component>\n"
+
+const golandTestContents string = "<component name=\"ProjectRunConfigurationManager\">\n" +
+	"  <configuration default=\"false\" name=\"Test\" type=\"GoTestRunConfiguration\" factoryName=\"Go Test\">\n" +
+	"    <module name=\"module\" />\n" +
+	"    <working_directory value=\"$PROJECT_DIR$\" />\n" +
+	"    <kind value=\"DIRECTORY\" />\n" +
+	"    <package value=\"a1\" />\n" +
+	"    <directory value=\"$PROJECT_DIR$\" />\n" +
+	"    <framework value=\"gotest\" />\n" +
+	"    <method v=\"2\" />\n" +
+	"  </configuration>\n" +
+	"</component>\n"
+
+const nvimConfigContents string = "-- Project-local Neovim configuration. Loaded when 'exrc' is set (see :help exrc).\n" +
+	"\n" +
+	"-- gopls settings, for nvim-lspconfig\n" +
+	"local ok, lspconfig = pcall(require, \"lspconfig\")\n" +
+	"if ok then\n" +
+	"  lspconfig.gopls.setup({\n" +
+	"    settings = {\n" +
+	"      gopls = {\n" +
+	"        gofumpt = false,\n" +
+	"        staticcheck = true,\n" +
+	"        usePlaceholders = true,\n" +
+	"        analyses = {\n" +
+	"          unusedparams = true,\n" +
+	"        },\n" +
+	"      },\n" +
+	"    },\n" +
+	"  })\n" +
+	"end\n" +
+	"\n" +
+	"-- Debug configuration, for nvim-dap-go\n" +
+	"local ok_dap, dap = pcall(require, \"dap\")\n" +
+	"if ok_dap then\n" +
+	"  dap.configurations.go = dap.configurations.go or {}\n" +
+	"  table.insert(dap.configurations.go, {\n" +
+	"    type = \"go\",\n" +
+	"    name = \"Debug module\",\n" +
+	"    request = \"launch\",\n" +
+	"    program = \"${workspaceFolder}\",\n" +
+	"  })\n" +
+	"end\n"
+
+const goldenHelperContents string = "package main\n" +
+	"\n" +
+	"import (\n" +
+	"\t\"bytes\"\n" +
+	"\t\"flag\"\n" +
+	"\t\"os\"\n" +
+	"\t\"path/filepath\"\n" +
+	"\t\"testing\"\n" +
+	")\n" +
+	"\n" +
+	"// Rewrite golden files with the current output: $ go test -update\n" +
+	"var update = flag.Bool(\"update\", false, \"update golden files in testdata\")\n" +
+	"\n" +
+	"// assertGolden compares got with the contents of testdata/<name>.golden\n" +
+	"func assertGolden(t *testing.T, name string, got []byte) {\n" +
+	"\tt.Helper()\n" +
+	"\tpath := filepath.Join(\"testdata\", name+\".golden\")\n" +
+	"\tif *update {\n" +
+	"\t\terr := os.WriteFile(path, got, 0644)\n" +
+	"\t\tif err != nil {\n" +
+	"\t\t\tt.Fatal(err)\n" +
+	"\t\t}\n" +
+	"\t}\n" +
+	"\twant, err := os.ReadFile(path)\n" +
+	"\tif err != nil {\n" +
+	"\t\tt.Fatal(err)\n" +
+	"\t}\n" +
+	"\tif !bytes.Equal(got, want) {\n" +
+	"\t\tt.Errorf(\"Output does not match %s (update it with `go test -update`)\\ngot:\\n%s\\nwant:\\n%s\", path, got, want)\n" +
+	"\t}\n" +
+	"}\n"
+
+const goldenOutputTestContents string = "package main\n" +
+	"\n" +
+	"import (\n" +
+	"\t\"io\"\n" +
+	"\t\"os\"\n" +
+	"\t\"testing\"\n" +
+	")\n" +
+	"\n" +
+	"func TestMainOutput(t *testing.T) {\n" +
+	"\tassertGolden(t, \"main_output\", captureStdout(t, main))\n" +
+	"}\n" +
+	"\n" +
+	"// captureStdout returns everything f writes to standard output\n" +
+	"func captureStdout(t *testing.T, f func()) []byte {\n" +
+	"\tt.Helper()\n" +
+	"\tr, w, err := os.Pipe()\n" +
+	"\tif err != nil {\n" +
+	"\t\tt.Fatal(err)\n" +
+	"\t}\n" +
+	"\tstdout := os.Stdout\n" +
+	"\tos.Stdout = w\n" +
+	"\tdefer func() {\n" +
+	"\t\tos.Stdout = stdout\n" +
+	"\t}()\n" +
+	"\n" +
+	"\tf()\n" +
+	"\n" +
+	"\terr = w.Close()\n" +
+	"\tif err != nil {\n" +
+	"\t\tt.Fatal(err)\n" +
+	"\t}\n" +
+	"\toutput, err := io.ReadAll(r)\n" +
+	"\tif err != nil {\n" +
+	"\t\tt.Fatal(err)\n" +
+	"\t}\n" +
+	"\treturn output\n" +
+	"}\n"
+
+const gremlinsConfigContents string = "# Mutation testing with Gremlins: https://gremlins.dev\n" +
+	"unleash:\n" +
+	"  # Fail when fewer mutants than this (%) are killed by tests, or covered by tests\n" +
+	"  threshold:\n" +
+	"    efficacy: 80\n" +
+	"    mutant-coverage: 80\n"
+
+const makefileMutationContents string = "BINARY := a1\n" +
+	"\n" +
+	".PHONY: build test lint fmt run clean mutation\n" +
+	"\n" +
+	"build:\n" +
+	"\tgo build -o $(BINARY) .\n" +
+	"\n" +
+	"test:\n" +
+	"\tgo test ./...\n" +
+	"\n" +
+	"lint:\n" +
+	"\tgo vet ./...\n" +
+	"\n" +
+	"fmt:\n" +
+	"\tgofmt -w .\n" +
+	"\n" +
+	"run: build\n" +
+	"\t./$(BINARY)\n" +
+	"\n" +
+	"clean:\n" +
+	"\trm -f $(BINARY)\n" +
+	"\n" +
+	"# Run mutation tests (install: go install github.com/go-gremlins/gremlins/cmd/gremlins@latest)\n" +
+	"mutation:\n" +
+	"\tgremlins unleash\n"
+
+const gitpodConfigContents string = "# Prebuilds run init, so workspaces start with the module cache and build cache warm\n" +
+	"tasks:\n" +
+	"  - init: go mod download && go build ./...\n" +
+	"    command: go run .\n" +
+	"\n" +
+	"vscode:\n" +
+	"  extensions:\n" +
+	"    - golang.go\n"
+
+const devcontainerContents string = "{\n" +
+	"  \"name\": \"a1\",\n" +
+	"  \"image\": \"mcr.microsoft.com/devcontainers/go:1-%s\",\n" +
+	"  \"updateContentCommand\": \"go mod download && go build ./...\",\n" +
+	"  \"customizations\": {\n" +
+	"    \"vscode\": {\n" +
+	"      \"extensions\": [\"golang.go\"]\n" +
+	"    }\n" +
+	"  }\n" +
+	"}\n"
+
+const bootstrapScriptContents string = "#!/bin/sh\n" +
+	"# Install the Go toolchain and tools this module needs on a fresh Linux machine.\n" +
+	"# Safe to rerun: anything already installed is left alone.\n" +
+	"set -eu\n" +
+	"\n" +
+	"GO_VERSION=\"1.22.0\"\n" +
+	"GO_ROOT=\"/usr/local/go\"\n" +
+	"\n" +
+	"case \"$(uname -m)\" in\n" +
+	"  x86_64) GO_ARCH=\"amd64\" ;;\n" +
+	"  aarch64 | arm64) GO_ARCH=\"arm64\" ;;\n" +
+	"  *) echo \"Unsupported architecture: $(uname -m)\" >&2; exit 1 ;;\n" +
+	"esac\n" +
+	"\n" +
+	"if [ \"$(\"$GO_ROOT/bin/go\" env GOVERSION 2>/dev/null)\" != \"go$GO_VERSION\" ]; then\n" +
+	"  archive=\"go$GO_VERSION.linux-$GO_ARCH.tar.gz\"\n" +
+	"  tmp=\"$(mktemp -d)\"\n" +
+	"  trap 'rm -rf \"$tmp\"' EXIT\n" +
+	"\n" +
+	"  echo \"Installing Go $GO_VERSION\"\n" +
+	"  curl -fsSL -o \"$tmp/$archive\" \"https://dl.google.com/go/$archive\"\n" +
+	"  checksum=\"$(curl -fsSL \"https://dl.google.com/go/$archive.sha256\")\"\n" +
+	"  echo \"$checksum  $tmp/$archive\" | sha256sum -c -\n" +
+	"\n" +
+	"  sudo rm -rf \"$GO_ROOT\"\n" +
+	"  sudo tar -C /usr/local -xzf \"$tmp/$archive\"\n" +
+	"fi\n" +
+	"\n" +
+	"export PATH=\"$GO_ROOT/bin:$(\"$GO_ROOT/bin/go\" env GOPATH)/bin:$PATH\"\n" +
+	"\n" +
+	"echo \"Installing tools\"\n" +
+	"go install golang.org/x/tools/cmd/goimports@latest\n" +
+	"\n" +
+	"echo \"Downloading dependencies\"\n" +
+	"go mod download\n" +
+	"\n" +
+	"echo \"Done. Make sure $GO_ROOT/bin and $(go env GOPATH)/bin are on your PATH\"\n"
+
+const scriptsMakefileContents string = "BINARY := a1\n" +
+	"\n" +
+	".PHONY: build test lint fmt run clean\n" +
+	"\n" +
+	"build:\n" +
+	"\tscript/build\n" +
+	"\n" +
+	"test:\n" +
+	"\tscript/test\n" +
+	"\n" +
+	"lint:\n" +
+	"\tgo vet ./...\n" +
+	"\n" +
+	"fmt:\n" +
+	"\tgofmt -w .\n" +
+	"\n" +
+	"run:\n" +
+	"\tscript/server\n" +
+	"\n" +
+	"clean:\n" +
+	"\trm -f $(BINARY)\n"
+
+const githubWorkflowScriptsContents string = "name: CI\n" +
+	"on: [push, pull_request]\n" +
+	"jobs:\n" +
+	"  Build:\n" +
+	"    runs-on: ubuntu-latest\n" +
+	"    steps:\n" +
+	"      - name: Git checkout\n" +
+	"        uses: actions/checkout@v4\n" +
+	"      - name: Set up Go\n" +
+	"        uses: actions/setup-go@v5\n" +
+	"        with:\n" +
+	"          go-version-file: go.mod\n" +
+	"      - name: Build\n" +
+	"        run: script/build\n" +
+	"      - name: Test\n" +
+	"        run: script/test\n"
+
+const scriptBootstrapContents string = "#!/bin/sh\n" +
+	"# Resolve all dependencies the module needs to build, test, and run.\n" +
+	"set -eu\n" +
+	"\n" +
+	"cd \"$(dirname \"$0\")/..\"\n" +
+	"\n" +
+	"echo \"Downloading dependencies\"\n" +
+	"go mod download\n"
+
+const scriptBuildContents string = "#!/bin/sh\n" +
+	"# Build the module's binary.\n" +
+	"set -eu\n" +
+	"\n" +
+	"cd \"$(dirname \"$0\")/..\"\n" +
+	"\n" +
+	"go build -o a1 .\n"
+
+const scriptTestContents string = "#!/bin/sh\n" +
+	"# Vet and run the module's tests. Arguments are passed to go test (e.g. -run TestName).\n" +
+	"set -eu\n" +
+	"\n" +
+	"cd \"$(dirname \"$0\")/..\"\n" +
+	"\n" +
+	"go vet ./...\n" +
+	"go test \"$@\" ./...\n"
+
+const scriptServerContents string = "#!/bin/sh\n" +
+	"# Build and run the module's binary. Arguments are passed to the binary.\n" +
+	"set -eu\n" +
+	"\n" +
+	"cd \"$(dirname \"$0\")/..\"\n" +
+	"\n" +
+	"script/build\n" +
+	"exec ./a1 \"$@\"\n"
+
+const scriptBootstrapPowerShellContents string = "# Resolve all dependencies the module needs to build, test, and run.\n" +
+	"$ErrorActionPreference = \"Stop\"\n" +
+	"Set-Location (Join-Path $PSScriptRoot \"..\")\n" +
+	"\n" +
+	"Write-Host \"Downloading dependencies\"\n" +
+	"go mod download\n" +
+	"if ($LASTEXITCODE -ne 0) { exit $LASTEXITCODE }\n"
+
+const scriptBuildPowerShellContents string = "# Build the module's binary.\n" +
+	"$ErrorActionPreference = \"Stop\"\n" +
+	"Set-Location (Join-Path $PSScriptRoot \"..\")\n" +
+	"\n" +
+	"go build -o a1.exe .\n" +
+	"exit $LASTEXITCODE\n"
+
+const scriptTestPowerShellContents string = "# Vet and run the module's tests. Arguments are passed to go test (e.g. -run TestName).\n" +
+	"$ErrorActionPreference = \"Stop\"\n" +
+	"Set-Location (Join-Path $PSScriptRoot \"..\")\n" +
+	"\n" +
+	"go vet ./...\n" +
+	"if ($LASTEXITCODE -ne 0) { exit $LASTEXITCODE }\n" +
+	"go test @args ./...\n" +
+	"exit $LASTEXITCODE\n"
+
+const scriptServerPowerShellContents string = "# Build and run the module's binary. Arguments are passed to the binary.\n" +
+	"$ErrorActionPreference = \"Stop\"\n" +
+	"Set-Location (Join-Path $PSScriptRoot \"..\")\n" +
+	"\n" +
+	"& (Join-Path $PSScriptRoot \"build.ps1\")\n" +
+	"if ($LASTEXITCODE -ne 0) { exit $LASTEXITCODE }\n" +
+	"& \".\\a1.exe\" @args\n" +
+	"exit $LASTEXITCODE\n"
+
+const mitLicenseContents string = "MIT License\n" +
+	"\n" +
+	"Copyright (c) %d %s\n" +
+	"\n" +
+	"Permission is hereby granted, free of charge, to any person obtaining a copy\n" +
+	"of this software and associated documentation files (the \"Software\"), to deal\n" +
+	"in the Software without restriction, including without limitation the rights\n" +
+	"to use, copy, modify, merge, publish, distribute, sublicense, and/or sell\n" +
+	"copies of the Software, and to permit persons to whom the Software is\n" +
+	"furnished to do so, subject to the following conditions:\n" +
+	"\n" +
+	"The above copyright notice and this permission notice shall be included in all\n" +
+	"copies or substantial portions of the Software.\n" +
+	"\n" +
+	"THE SOFTWARE IS PROVIDED \"AS IS\", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR\n" +
+	"IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,\n" +
+	"FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE\n" +
+	"AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER\n" +
+	"LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,\n" +
+	"OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE\n" +
+	"SOFTWARE.\n"
+
+const embedAssetsMainGoContents string = "package main\n" +
+	"\n" +
+	"import (\n" +
+	"\t\"embed\"\n" +
+	"\t\"fmt\"\n" +
+	")\n" +
+	"\n" +
+	"// Everything in the assets directory is embedded into the binary at build time\n" +
+	"//\n" +
+	"//go:embed assets\n" +
+	"var assets embed.FS\n" +
+	"\n" +
+	"func main() {\n" +
+	"\tgreeting, err := assets.ReadFile(\"assets/hello.txt\")\n" +
+	"\tif err != nil {\n" +
+	"\t\tpanic(err)\n" +
+	"\t}\n" +
+	"\tfmt.Print(string(greeting))\n" +
+	"}\n"
+
 const errorMessageUnknownFlag string = "Error: Unknown flag\n\n"
 const errorMessageModuleNameRequired string = "Error: Module name is required\n\n"
-const errorMessageTooManyModuleNames string = "Error: Only one module name is allowed\n\n"
 
 type testRunTestCaseData struct {
 	args                []string
+	existingModule      *file             // Module to run in, if any
+	config              string            // Config file content, if any
+	env                 map[string]string // Environment variables to set, if any
 	expectedOutput      string
 	expectedErrorOutput string
 	expectedExitCode    int
@@ -87,6 +1059,7 @@ type file struct {
 
 const dirPerms fs.FileMode = 0755 | fs.ModeDir
 const filePerms fs.FileMode = 0644
+const executablePerms fs.FileMode = 0755
 
 type gitRepo struct {
 	dir            string
@@ -95,7 +1068,29 @@ type gitRepo struct {
 	remote         *string
 }
 
+// TestMain gives Git a global config with a test user, so that the tests don't depend on the config of the machine
+// they run on (which may set no user at all)
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "gmc-test")
+	if err != nil {
+		panic(err)
+	}
+	gitConfig := filepath.Join(dir, "gitconfig")
+	err = os.WriteFile(gitConfig, []byte("[user]\n\tname = Test Gopher\n\temail = gopher@example.com\n"), 0644)
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("GIT_CONFIG_GLOBAL", gitConfig)
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
 func TestRun(t *testing.T) {
+	licenseYear := licenseTime.Year()
+	licenseAuthor := gitUserName(t)
+	goVersion := localGoVersion(t)
+
 	tests := []testRunTestCaseData{
 		{
 			args:                []string{"-h"},
@@ -124,8 +1119,16 @@ func TestRun(t *testing.T) {
 		{
 			args:                []string{"-q"},
 			expectedOutput:      "",
+			expectedErrorOutput: "Error: Module name is required\n",
+			expectedExitCode:    2,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args:                []string{"--silent"},
+			expectedOutput:      "",
 			expectedErrorOutput: "",
-			expectedExitCode:    1,
+			expectedExitCode:    2,
 			expectedFiles:       nil,
 			expectedGitRepo:     nil,
 		},
@@ -157,7 +1160,7 @@ func TestRun(t *testing.T) {
 			args:                []string{},
 			expectedOutput:      helpOutput,
 			expectedErrorOutput: errorMessageModuleNameRequired,
-			expectedExitCode:    1,
+			expectedExitCode:    2,
 			expectedFiles:       nil,
 			expectedGitRepo:     nil,
 		},
@@ -165,7 +1168,7 @@ func TestRun(t *testing.T) {
 			args:                []string{"-e"},
 			expectedOutput:      helpOutput,
 			expectedErrorOutput: errorMessageUnknownFlag,
-			expectedExitCode:    1,
+			expectedExitCode:    2,
 			expectedFiles:       nil,
 			expectedGitRepo:     nil,
 		},
@@ -173,83 +1176,363 @@ func TestRun(t *testing.T) {
 			args:                []string{"-e", "a1"},
 			expectedOutput:      helpOutput,
 			expectedErrorOutput: errorMessageUnknownFlag,
-			expectedExitCode:    1,
-			expectedFiles:       nil,
-			expectedGitRepo:     nil,
-		},
-		{
-			args:                []string{"a1", "a2"},
-			expectedOutput:      helpOutput,
-			expectedErrorOutput: errorMessageTooManyModuleNames,
-			expectedExitCode:    1,
-			expectedFiles:       nil,
-			expectedGitRepo:     nil,
-		},
-		{
-			args:                []string{"-q", "a1", "a2"},
-			expectedOutput:      "",
-			expectedErrorOutput: "",
-			expectedExitCode:    1,
+			expectedExitCode:    2,
 			expectedFiles:       nil,
 			expectedGitRepo:     nil,
 		},
 		{
-			args: []string{"a1"},
+			args: []string{"a1", "a2"},
 			expectedOutput: fmt.Sprintf("Creating Go module: a1\n"+
 				"- Created directory: a1\n"+
 				"- Initialized Go module\n"+
 				"- Created file     : a1/main.go\n"+
+				"- Created file     : a1/main_test.go\n"+
 				"- Created file     : a1/.gitignore\n"+
+				"- Created directory: a1/.gmc\n"+
+				"- Created file     : a1/.gmc/manifest.json\n"+
 				"\n"+
 				"Finished creating Go module: a1\n"+
 				"\n"+
 				"Next steps:\n"+
 				"- Change into module's directory: $ cd a1\n"+
 				"- Run module: $ go run .\n"+
-				"- Start coding: $ %s .\n",
+				"- Start coding: $ %[1]s .\n"+
+				"\n"+
+				"Creating Go module: a2\n"+
+				"- Created directory: a2\n"+
+				"- Initialized Go module\n"+
+				"- Created file     : a2/main.go\n"+
+				"- Created file     : a2/main_test.go\n"+
+				"- Created file     : a2/.gitignore\n"+
+				"- Created directory: a2/.gmc\n"+
+				"- Created file     : a2/.gmc/manifest.json\n"+
+				"\n"+
+				"Finished creating Go module: a2\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd a2\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %[1]s .\n"+
+				"\n"+
+				"Created 2 of 2 Go modules\n",
 				editor),
 			expectedErrorOutput: "",
 			expectedExitCode:    0,
-			expectedFiles: &file{"a1", dirPerms, nil, []file{
-				{"go.mod", filePerms, []byte("module a1\n\ngo 1.18\n"), nil},
+			expectedFiles: &file{"a2", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module a2\n\ngo %s\n", goVersion)), nil},
 				{"main.go", filePerms, []byte(mainGoContents), nil},
-				{".gitignore", filePerms, []byte("a1"), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{".gitignore", filePerms, []byte("a2"), nil},
 			}},
 			expectedGitRepo: nil,
 		},
 		{
-			args: []string{"github.com/foo"},
-			expectedOutput: fmt.Sprintf("Creating Go module: github.com/foo\n"+
-				"- Created directory: foo\n"+
+			args: []string{"a1", "b/a1"},
+			expectedOutput: fmt.Sprintf("Creating Go module: a1\n"+
+				"- Created directory: a1\n"+
 				"- Initialized Go module\n"+
-				"- Created file     : foo/main.go\n"+
-				"- Created file     : foo/.gitignore\n"+
+				"- Created file     : a1/main.go\n"+
+				"- Created file     : a1/main_test.go\n"+
+				"- Created file     : a1/.gitignore\n"+
+				"- Created directory: a1/.gmc\n"+
+				"- Created file     : a1/.gmc/manifest.json\n"+
 				"\n"+
-				"Finished creating Go module: github.com/foo\n"+
+				"Finished creating Go module: a1\n"+
 				"\n"+
 				"Next steps:\n"+
-				"- Change into module's directory: $ cd foo\n"+
+				"- Change into module's directory: $ cd a1\n"+
 				"- Run module: $ go run .\n"+
-				"- Start coding: $ %s .\n",
+				"- Start coding: $ %s .\n"+
+				"\n"+
+				"Created 1 of 2 Go modules\n",
 				editor),
-			expectedErrorOutput: "",
-			expectedExitCode:    0,
-			expectedFiles: &file{"foo", dirPerms, nil, []file{
-				{"go.mod", filePerms, []byte("module github.com/foo\n\ngo 1.18\n"), nil},
+			expectedErrorOutput: "Failed to create Go module: b/a1: Directory already exists: a1 (use --force to create the module in it)\n" +
+				"Failed to create Go modules: b/a1\n",
+			expectedExitCode: 1,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module a1\n\ngo %s\n", goVersion)), nil},
 				{"main.go", filePerms, []byte(mainGoContents), nil},
-				{".gitignore", filePerms, []byte("foo"), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{".gitignore", filePerms, []byte("a1"), nil},
 			}},
 			expectedGitRepo: nil,
 		},
 		{
-			args: []string{"--git", "github.com/foo/bar"},
-			expectedOutput: fmt.Sprintf("Creating Go module: github.com/foo/bar\n"+
-				"- Created directory: bar\n"+
+			args: []string{"a1", "a1"},
+			expectedOutput: fmt.Sprintf("Creating Go module: a1\n"+
+				"- Created directory: a1\n"+
 				"- Initialized Go module\n"+
-				"- Created file     : bar/main.go\n"+
-				"- Created file     : bar/.gitignore\n"+
-				"- Initialized Git repository\n"+
-				"- Created file     : bar/README.md\n"+
+				"- Created file     : a1/main.go\n"+
+				"- Created file     : a1/main_test.go\n"+
+				"- Created file     : a1/.gitignore\n"+
+				"- Created directory: a1/.gmc\n"+
+				"- Created file     : a1/.gmc/manifest.json\n"+
+				"\n"+
+				"Finished creating Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd a1\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n"+
+				"\n"+
+				"Creating Go module: a1\n"+
+				"- NOTE: Already created with these options: a1 (nothing to do)\n"+
+				"\n"+
+				"Finished creating Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd a1\n"+
+				"- Start coding: $ %s .\n"+
+				"\n"+
+				"Created 2 of 2 Go modules\n",
+				editor,
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module a1\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{".gitignore", filePerms, []byte("a1"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args: []string{"-g", "a1", "a1"},
+			expectedOutput: fmt.Sprintf("Creating Go module: a1\n"+
+				"- Created directory: a1\n"+
+				"- Initialized Go module\n"+
+				"- Created file     : a1/main.go\n"+
+				"- Created file     : a1/main_test.go\n"+
+				"- Created file     : a1/.gitignore\n"+
+				"- Initialized Git repository\n"+
+				"- Created file     : a1/README.md\n"+
+				"- Created directory: a1/.gmc\n"+
+				"- Created file     : a1/.gmc/manifest.json\n"+
+				"- Committed all files to Git repository\n"+
+				"- NOTE: Unable to add remote for Git repository\n"+
+				"\n"+
+				"Finished creating Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd a1\n"+
+				"- Run module: $ go run .\n"+
+				"- Create remote Git repository\n"+
+				"- Push to remote Git repository: $ git push -u origin %[1]s\n"+
+				"- Start coding: $ %[2]s .\n"+
+				"\n"+
+				"Creating Go module: a1\n"+
+				"- NOTE: Already created with these options: a1 (nothing to do)\n"+
+				"\n"+
+				"Finished creating Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd a1\n"+
+				"- Create remote Git repository\n"+
+				"- Push to remote Git repository: $ git push -u origin %[1]s\n"+
+				"- Start coding: $ %[2]s .\n"+
+				"\n"+
+				"Created 2 of 2 Go modules\n",
+				gitBranchName,
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module a1\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{".git", dirPerms, nil, nil},
+				{".gitignore", filePerms, []byte("a1"), nil},
+				{"README.md", filePerms, []byte("# a1\n\n"), nil},
+			}},
+			expectedGitRepo: &gitRepo{
+				"a1",
+				gitBranchName,
+				[]string{"Initial commit"},
+				nil,
+			},
+		},
+		{
+			args:           []string{"-q", "a1", "b/a1"},
+			expectedOutput: "",
+			expectedErrorOutput: "Failed to create Go module: b/a1: Directory already exists: a1 (use --force to create the module in it)\n" +
+				"Failed to create Go modules: b/a1\n",
+			expectedExitCode: 1,
+			expectedFiles:    &file{"a1", dirPerms, nil, nil},
+			expectedGitRepo:  nil,
+		},
+		{
+			args:                []string{"--silent", "a1", "b/a1"},
+			expectedOutput:      "",
+			expectedErrorOutput: "",
+			expectedExitCode:    1,
+			expectedFiles:       &file{"a1", dirPerms, nil, nil},
+			expectedGitRepo:     nil,
+		},
+		{
+			args:             []string{"-q", "--batch", "modules.txt"},
+			existingModule:   &file{"workshop", dirPerms, nil, []file{{"modules.txt", filePerms, []byte("# Workshop\na1\n\na2\n"), nil}}},
+			expectedOutput:   "",
+			expectedExitCode: 0,
+			expectedFiles: &file{"workshop", dirPerms, nil, []file{
+				{"modules.txt", filePerms, []byte("# Workshop\na1\n\na2\n"), nil},
+				{"a1", dirPerms, nil, nil},
+				{"a2", dirPerms, nil, nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args: []string{"a1"},
+			env:  map[string]string{"WSL_DISTRO_NAME": "Ubuntu", "EDITOR": ""},
+			expectedOutput: "Creating Go module: a1\n" +
+				"- Created directory: a1\n" +
+				"- Initialized Go module\n" +
+				"- Created file     : a1/main.go\n" +
+				"- Created file     : a1/main_test.go\n" +
+				"- Created file     : a1/.gitignore\n" +
+				"- Created directory: a1/.gmc\n" +
+				"- Created file     : a1/.gmc/manifest.json\n" +
+				"\n" +
+				"Finished creating Go module: a1\n" +
+				"\n" +
+				"Next steps:\n" +
+				"- Change into module's directory: $ cd a1\n" +
+				"- Run module: $ go run .\n" +
+				"- Start coding: $ code .\n",
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module a1\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{".gitignore", filePerms, []byte("a1"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args:           []string{"--force", "--make", "a1"},
+			existingModule: &file{"ws", dirPerms, nil, []file{{"a1", dirPerms, nil, []file{{"Makefile", filePerms, []byte("all:\n"), nil}}}}},
+			expectedOutput: fmt.Sprintf("Creating Go module: a1\n"+
+				"- Initialized Go module\n"+
+				"- Created file     : a1/main.go\n"+
+				"- Created file     : a1/main_test.go\n"+
+				"- NOTE: Kept existing file: a1/Makefile\n"+
+				"- Created file     : a1/.gitignore\n"+
+				"- Created directory: a1/.gmc\n"+
+				"- Created file     : a1/.gmc/manifest.json\n"+
+				"\n"+
+				"Finished creating Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd a1\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"ws", dirPerms, nil, []file{
+				{"a1", dirPerms, nil, []file{
+					{".gmc", dirPerms, nil, nil},
+					{"go.mod", filePerms, []byte(fmt.Sprintf("module a1\n\ngo %s\n", goVersion)), nil},
+					{"main.go", filePerms, []byte(mainGoContents), nil},
+					{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+					{"Makefile", filePerms, []byte("all:\n"), nil},
+					{".gitignore", filePerms, []byte("a1"), nil},
+				}},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args:                []string{"a1", "Bad Name"},
+			expectedOutput:      helpOutput,
+			expectedErrorOutput: "Error: Invalid module name: Bad Name: invalid char ' '\n\n",
+			expectedExitCode:    2,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args:                []string{"init", "-q", "a1", "a2"},
+			expectedOutput:      "",
+			expectedErrorOutput: "Error: Only one module name is allowed\n",
+			expectedExitCode:    2,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args: []string{"a1"},
+			expectedOutput: fmt.Sprintf("Creating Go module: a1\n"+
+				"- Created directory: a1\n"+
+				"- Initialized Go module\n"+
+				"- Created file     : a1/main.go\n"+
+				"- Created file     : a1/main_test.go\n"+
+				"- Created file     : a1/.gitignore\n"+
+				"- Created directory: a1/.gmc\n"+
+				"- Created file     : a1/.gmc/manifest.json\n"+
+				"\n"+
+				"Finished creating Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd a1\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module a1\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{".gitignore", filePerms, []byte("a1"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args: []string{"github.com/foo"},
+			expectedOutput: fmt.Sprintf("Creating Go module: github.com/foo\n"+
+				"- Created directory: foo\n"+
+				"- Initialized Go module\n"+
+				"- Created file     : foo/main.go\n"+
+				"- Created file     : foo/main_test.go\n"+
+				"- Created file     : foo/.gitignore\n"+
+				"- Created directory: foo/.gmc\n"+
+				"- Created file     : foo/.gmc/manifest.json\n"+
+				"\n"+
+				"Finished creating Go module: github.com/foo\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd foo\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"foo", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module github.com/foo\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{".gitignore", filePerms, []byte("foo"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args: []string{"--git", "github.com/foo/bar"},
+			expectedOutput: fmt.Sprintf("Creating Go module: github.com/foo/bar\n"+
+				"- Created directory: bar\n"+
+				"- Initialized Go module\n"+
+				"- Created file     : bar/main.go\n"+
+				"- Created file     : bar/main_test.go\n"+
+				"- Created file     : bar/.gitignore\n"+
+				"- Initialized Git repository\n"+
+				"- Created file     : bar/README.md\n"+
+				"- Created directory: bar/.gmc\n"+
+				"- Created file     : bar/.gmc/manifest.json\n"+
 				"- Committed all files to Git repository\n"+
 				"- Added remote for Git repository: git@github.com:foo/bar.git\n"+
 				"\n"+
@@ -266,8 +1549,10 @@ func TestRun(t *testing.T) {
 			expectedErrorOutput: "",
 			expectedExitCode:    0,
 			expectedFiles: &file{"bar", dirPerms, nil, []file{
-				{"go.mod", filePerms, []byte("module github.com/foo/bar\n\ngo 1.18\n"), nil},
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module github.com/foo/bar\n\ngo %s\n", goVersion)), nil},
 				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
 				{".git", dirPerms, nil, nil},
 				{".gitignore", filePerms, []byte("bar"), nil},
 				{"README.md", filePerms, []byte("# bar\n\n"), nil},
@@ -279,15 +1564,52 @@ func TestRun(t *testing.T) {
 				ptr("git@github.com:foo/bar.git"),
 			},
 		},
+		{
+			args: []string{"--git", "github.com/foo/bar"},
+			env:  map[string]string{"GIT_CONFIG_GLOBAL": os.DevNull},
+			expectedOutput: "Creating Go module: github.com/foo/bar\n" +
+				"- Created directory: bar\n" +
+				"- Initialized Go module\n" +
+				"- Created file     : bar/main.go\n" +
+				"- Created file     : bar/main_test.go\n" +
+				"- Created file     : bar/.gitignore\n" +
+				"- Removed directory: bar\n",
+			expectedErrorOutput: "Failed to create Go module: github.com/foo/bar: Failed to create as Git repository: `git config --global user.email` must be set\n",
+			expectedExitCode:    4,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args: []string{"--git", "--keep-partial", "github.com/foo/bar"},
+			env:  map[string]string{"GIT_CONFIG_GLOBAL": os.DevNull},
+			expectedOutput: "Creating Go module: github.com/foo/bar\n" +
+				"- Created directory: bar\n" +
+				"- Initialized Go module\n" +
+				"- Created file     : bar/main.go\n" +
+				"- Created file     : bar/main_test.go\n" +
+				"- Created file     : bar/.gitignore\n",
+			expectedErrorOutput: "Failed to create Go module: github.com/foo/bar: Failed to create as Git repository: `git config --global user.email` must be set\n",
+			expectedExitCode:    4,
+			expectedFiles: &file{"bar", dirPerms, nil, []file{
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module github.com/foo/bar\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{".gitignore", filePerms, []byte("bar"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
 		{
 			args: []string{"-g", "github.com/foo/bar"},
 			expectedOutput: fmt.Sprintf("Creating Go module: github.com/foo/bar\n"+
 				"- Created directory: bar\n"+
 				"- Initialized Go module\n"+
 				"- Created file     : bar/main.go\n"+
+				"- Created file     : bar/main_test.go\n"+
 				"- Created file     : bar/.gitignore\n"+
 				"- Initialized Git repository\n"+
 				"- Created file     : bar/README.md\n"+
+				"- Created directory: bar/.gmc\n"+
+				"- Created file     : bar/.gmc/manifest.json\n"+
 				"- Committed all files to Git repository\n"+
 				"- Added remote for Git repository: git@github.com:foo/bar.git\n"+
 				"\n"+
@@ -305,8 +1627,10 @@ func TestRun(t *testing.T) {
 			expectedErrorOutput: "",
 			expectedExitCode:    0,
 			expectedFiles: &file{"bar", dirPerms, nil, []file{
-				{"go.mod", filePerms, []byte("module github.com/foo/bar\n\ngo 1.18\n"), nil},
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module github.com/foo/bar\n\ngo %s\n", goVersion)), nil},
 				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
 				{".git", dirPerms, nil, nil},
 				{".gitignore", filePerms, []byte("bar"), nil},
 				{"README.md", filePerms, []byte("# bar\n\n"), nil},
@@ -318,35 +1642,2112 @@ func TestRun(t *testing.T) {
 				ptr("git@github.com:foo/bar.git"),
 			},
 		},
-	}
-
-	t.Setenv("EDITOR", editor) // Automatically reset
-
-	for _, tc := range tests {
-		testName := strings.Join(tc.args, " ")
-		t.Run(testName, func(t *testing.T) {
-			testRunTestCase(t, tc)
-		})
-	}
-}
-
-func testRunTestCase(t *testing.T, tc testRunTestCaseData) {
-	tempTestDir := t.TempDir() // Automatically cleaned up
-
-	cwd, err := os.Getwd()
-	if err != nil {
-		t.Fatal(err)
-	}
-	err = os.Chdir(tempTestDir)
-	if err != nil {
-		t.Fatal(err)
-	}
-	t.Cleanup(func() {
-		err = os.Chdir(cwd)
+		{
+			args: []string{"-g", "--git-exec", "github.com/foo/bar"},
+			expectedOutput: fmt.Sprintf("Creating Go module: github.com/foo/bar\n"+
+				"- Created directory: bar\n"+
+				"- Initialized Go module\n"+
+				"- Created file     : bar/main.go\n"+
+				"- Created file     : bar/main_test.go\n"+
+				"- Created file     : bar/.gitignore\n"+
+				"- Initialized Git repository\n"+
+				"- Created file     : bar/README.md\n"+
+				"- Created directory: bar/.gmc\n"+
+				"- Created file     : bar/.gmc/manifest.json\n"+
+				"- Committed all files to Git repository\n"+
+				"- Added remote for Git repository: git@github.com:foo/bar.git\n"+
+				"\n"+
+				"Finished creating Go module: github.com/foo/bar\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd bar\n"+
+				"- Run module: $ go run .\n"+
+				"- Create remote Git repository git@github.com:foo/bar.git: https://github.com/new\n"+
+				"- Push to remote Git repository: $ git push -u origin %s\n"+
+				"- Start coding: $ %s .\n",
+				gitBranchName,
+				editor,
+			),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"bar", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module github.com/foo/bar\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{".git", dirPerms, nil, nil},
+				{".gitignore", filePerms, []byte("bar"), nil},
+				{"README.md", filePerms, []byte("# bar\n\n"), nil},
+			}},
+			expectedGitRepo: &gitRepo{
+				"bar",
+				gitBranchName,
+				[]string{"Initial commit"},
+				ptr("git@github.com:foo/bar.git"),
+			},
+		},
+		{
+			args: []string{"--dry-run", "a1"},
+			expectedOutput: fmt.Sprintf("Creating Go module (dry run): a1\n"+
+				"- Would create directory: a1\n"+
+				"- Would initialize Go module\n"+
+				"- Would create file     : a1/main.go\n"+
+				"- Would create file     : a1/main_test.go\n"+
+				"- Would create file     : a1/.gitignore\n"+
+				"- Would create directory: a1/.gmc\n"+
+				"- Would create file     : a1/.gmc/manifest.json\n"+
+				"\n"+
+				"Finished dry run of creating Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd a1\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args: []string{"--dry-run", "-g", "github.com/foo/bar"},
+			expectedOutput: fmt.Sprintf("Creating Go module (dry run): github.com/foo/bar\n"+
+				"- Would create directory: bar\n"+
+				"- Would initialize Go module\n"+
+				"- Would create file     : bar/main.go\n"+
+				"- Would create file     : bar/main_test.go\n"+
+				"- Would create file     : bar/.gitignore\n"+
+				"- Would initialize Git repository\n"+
+				"- Would create file     : bar/README.md\n"+
+				"- Would create directory: bar/.gmc\n"+
+				"- Would create file     : bar/.gmc/manifest.json\n"+
+				"- Would commit all files to Git repository\n"+
+				"- Would add remote for Git repository: git@github.com:foo/bar.git\n"+
+				"\n"+
+				"Finished dry run of creating Go module: github.com/foo/bar\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd bar\n"+
+				"- Run module: $ go run .\n"+
+				"- Create remote Git repository git@github.com:foo/bar.git: https://github.com/new\n"+
+				"- Push to remote Git repository: $ git push -u origin %s\n"+
+				"- Start coding: $ %s .\n",
+				gitBranchName,
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args: []string{"--dry-run", "-g", "--skip", "git", "github.com/foo/bar"},
+			expectedOutput: fmt.Sprintf("Creating Go module (dry run): github.com/foo/bar\n"+
+				"- Would create directory: bar\n"+
+				"- Would initialize Go module\n"+
+				"- Would create file     : bar/main.go\n"+
+				"- Would create file     : bar/main_test.go\n"+
+				"- Would create file     : bar/.gitignore\n"+
+				"- Would create file     : bar/README.md\n"+
+				"- Would create directory: bar/.gmc\n"+
+				"- Would create file     : bar/.gmc/manifest.json\n"+
+				"\n"+
+				"Finished dry run of creating Go module: github.com/foo/bar\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd bar\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args: []string{"--dry-run", "-g", "--only", "git", "github.com/foo/bar"},
+			expectedOutput: fmt.Sprintf("Creating Go module (dry run): github.com/foo/bar\n"+
+				"- Would initialize Git repository\n"+
+				"- Would commit all files to Git repository\n"+
+				"- Would add remote for Git repository: git@github.com:foo/bar.git\n"+
+				"\n"+
+				"Finished dry run of creating Go module: github.com/foo/bar\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd bar\n"+
+				"- Run module: $ go run .\n"+
+				"- Create remote Git repository git@github.com:foo/bar.git: https://github.com/new\n"+
+				"- Push to remote Git repository: $ git push -u origin %s\n"+
+				"- Start coding: $ %s .\n",
+				gitBranchName,
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args:                []string{"--skip", "git", "--only", "files", "a1"},
+			expectedOutput:      helpOutput,
+			expectedErrorOutput: "Error: --skip and --only can't be used together\n\n",
+			expectedExitCode:    2,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args:                []string{"--only", "docs", "a1"},
+			expectedOutput:      helpOutput,
+			expectedErrorOutput: "Error: Unknown stage: docs (stages: files, deps, git)\n\n",
+			expectedExitCode:    2,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args: []string{"--no-deps", "a1"},
+			expectedOutput: fmt.Sprintf("Creating Go module: a1\n"+
+				"- Created directory: a1\n"+
+				"- Initialized Go module\n"+
+				"- Created file     : a1/main.go\n"+
+				"- Created file     : a1/main_test.go\n"+
+				"- Created file     : a1/.gitignore\n"+
+				"- Created directory: a1/.gmc\n"+
+				"- Created file     : a1/.gmc/manifest.json\n"+
+				"\n"+
+				"Finished creating Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd a1\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module a1\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{".gitignore", filePerms, []byte("a1"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args: []string{"--json", "a1"},
+			expectedOutput: fmt.Sprintf("{\n"+
+				"  \"module\": \"a1\",\n"+
+				"  \"goVersion\": \"%s\",\n"+
+				"  \"dryRun\": false,\n"+
+				"  \"createdDirectories\": [\n"+
+				"    \"a1\",\n"+
+				"    \"a1/.gmc\"\n"+
+				"  ],\n"+
+				"  \"createdFiles\": [\n"+
+				"    \"a1/main.go\",\n"+
+				"    \"a1/main_test.go\",\n"+
+				"    \"a1/.gitignore\",\n"+
+				"    \"a1/.gmc/manifest.json\"\n"+
+				"  ],\n"+
+				"  \"dependencies\": [],\n"+
+				"  \"gitActions\": [],\n"+
+				"  \"notes\": [],\n"+
+				"  \"nextSteps\": [\n"+
+				"    \"Change into module's directory: $ cd a1\",\n"+
+				"    \"Run module: $ go run .\",\n"+
+				"    \"Start coding: $ %s .\"\n"+
+				"  ]\n"+
+				"}\n",
+				goVersion,
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module a1\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{".gitignore", filePerms, []byte("a1"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args: []string{"--json", "--dry-run", "-g", "github.com/foo/bar"},
+			expectedOutput: fmt.Sprintf("{\n"+
+				"  \"module\": \"github.com/foo/bar\",\n"+
+				"  \"goVersion\": \"1.18\",\n"+
+				"  \"dryRun\": true,\n"+
+				"  \"createdDirectories\": [\n"+
+				"    \"bar\",\n"+
+				"    \"bar/.gmc\"\n"+
+				"  ],\n"+
+				"  \"createdFiles\": [\n"+
+				"    \"bar/main.go\",\n"+
+				"    \"bar/main_test.go\",\n"+
+				"    \"bar/.gitignore\",\n"+
+				"    \"bar/README.md\",\n"+
+				"    \"bar/.gmc/manifest.json\"\n"+
+				"  ],\n"+
+				"  \"dependencies\": [],\n"+
+				"  \"gitActions\": [\n"+
+				"    \"init\",\n"+
+				"    \"commit\",\n"+
+				"    \"addRemote\"\n"+
+				"  ],\n"+
+				"  \"gitRemote\": \"git@github.com:foo/bar.git\",\n"+
+				"  \"notes\": [],\n"+
+				"  \"nextSteps\": [\n"+
+				"    \"Change into module's directory: $ cd bar\",\n"+
+				"    \"Run module: $ go run .\",\n"+
+				"    \"Create remote Git repository git@github.com:foo/bar.git: https://github.com/new\",\n"+
+				"    \"Push to remote Git repository: $ git push -u origin %s\",\n"+
+				"    \"Start coding: $ %s .\"\n"+
+				"  ]\n"+
+				"}\n",
+				gitBranchName,
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args: []string{"--license", "mit", "a1"},
+			expectedOutput: fmt.Sprintf("Creating Go module: a1\n"+
+				"- Created directory: a1\n"+
+				"- Initialized Go module\n"+
+				"- Created file     : a1/main.go\n"+
+				"- Created file     : a1/main_test.go\n"+
+				"- Created file     : a1/.gitignore\n"+
+				"- Created file     : a1/LICENSE\n"+
+				"- Created directory: a1/.gmc\n"+
+				"- Created file     : a1/.gmc/manifest.json\n"+
+				"\n"+
+				"Finished creating Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd a1\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module a1\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{".gitignore", filePerms, []byte("a1"), nil},
+				{"LICENSE", filePerms, []byte(fmt.Sprintf(mitLicenseContents, licenseYear, licenseAuthor)), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args:   []string{"--license", "mit", "a1"},
+			env:    map[string]string{"GIT_CONFIG_GLOBAL": os.DevNull},
+			config: `{"author": "Ada Lovelace"}`,
+			expectedOutput: fmt.Sprintf("Creating Go module: a1\n"+
+				"- Created directory: a1\n"+
+				"- Initialized Go module\n"+
+				"- Created file     : a1/main.go\n"+
+				"- Created file     : a1/main_test.go\n"+
+				"- Created file     : a1/.gitignore\n"+
+				"- Created file     : a1/LICENSE\n"+
+				"- Created directory: a1/.gmc\n"+
+				"- Created file     : a1/.gmc/manifest.json\n"+
+				"\n"+
+				"Finished creating Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd a1\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module a1\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{".gitignore", filePerms, []byte("a1"), nil},
+				{"LICENSE", filePerms, []byte(fmt.Sprintf(mitLicenseContents, licenseYear, "Ada Lovelace")), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args:                []string{"--license", "mit", "a1"},
+			env:                 map[string]string{"GIT_CONFIG_GLOBAL": os.DevNull},
+			expectedOutput:      "",
+			expectedErrorOutput: "Failed to create Go module: a1: `git config --global user.name` (or an author in gmc's config) must be set to attribute the license\n",
+			expectedExitCode:    4,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args: []string{"-g", "--license", "MIT", "github.com/foo/bar"},
+			expectedOutput: fmt.Sprintf("Creating Go module: github.com/foo/bar\n"+
+				"- Created directory: bar\n"+
+				"- Initialized Go module\n"+
+				"- Created file     : bar/main.go\n"+
+				"- Created file     : bar/main_test.go\n"+
+				"- Created file     : bar/.gitignore\n"+
+				"- Created file     : bar/LICENSE\n"+
+				"- Initialized Git repository\n"+
+				"- Created file     : bar/README.md\n"+
+				"- Created directory: bar/.gmc\n"+
+				"- Created file     : bar/.gmc/manifest.json\n"+
+				"- Committed all files to Git repository\n"+
+				"- Added remote for Git repository: git@github.com:foo/bar.git\n"+
+				"\n"+
+				"Finished creating Go module: github.com/foo/bar\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd bar\n"+
+				"- Run module: $ go run .\n"+
+				"- Create remote Git repository git@github.com:foo/bar.git: https://github.com/new\n"+
+				"- Push to remote Git repository: $ git push -u origin %s\n"+
+				"- Start coding: $ %s .\n",
+				gitBranchName,
+				editor,
+			),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"bar", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module github.com/foo/bar\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{".git", dirPerms, nil, nil},
+				{".gitignore", filePerms, []byte("bar"), nil},
+				{"LICENSE", filePerms, []byte(fmt.Sprintf(mitLicenseContents, licenseYear, licenseAuthor)), nil},
+				{"README.md", filePerms, []byte("# bar\n\n## License\n\n[MIT License](LICENSE)\n"), nil},
+			}},
+			expectedGitRepo: &gitRepo{
+				"bar",
+				gitBranchName,
+				[]string{"Initial commit"},
+				ptr("git@github.com:foo/bar.git"),
+			},
+		},
+		{
+			args:                []string{"--license", "gpl", "a1"},
+			expectedOutput:      helpOutput,
+			expectedErrorOutput: "Error: Unsupported license: gpl (supported: mit, apache-2.0, bsd-3-clause)\n\n",
+			expectedExitCode:    2,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args: []string{"--ci", "github", "a1"},
+			expectedOutput: fmt.Sprintf("Creating Go module: a1\n"+
+				"- Created directory: a1\n"+
+				"- Initialized Go module\n"+
+				"- Created file     : a1/main.go\n"+
+				"- Created file     : a1/main_test.go\n"+
+				"- Created directory: a1/.github\n"+
+				"- Created directory: a1/.github/workflows\n"+
+				"- Created file     : a1/.github/workflows/ci.yml\n"+
+				"- Created file     : a1/.gitignore\n"+
+				"- Created directory: a1/.gmc\n"+
+				"- Created file     : a1/.gmc/manifest.json\n"+
+				"\n"+
+				"Finished creating Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd a1\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module a1\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{".github", dirPerms, nil, []file{
+					{"workflows", dirPerms, nil, []file{
+						{"ci.yml", filePerms, []byte(githubWorkflowContents), nil},
+					}},
+				}},
+				{".gitignore", filePerms, []byte("a1"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args:                []string{"github.com/foo/bar/"},
+			expectedOutput:      helpOutput,
+			expectedErrorOutput: "Error: Invalid module name: github.com/foo/bar/: trailing slash\n\n",
+			expectedExitCode:    2,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args:                []string{"Github.com/foo/bar"},
+			expectedOutput:      helpOutput,
+			expectedErrorOutput: "Error: Invalid module name: Github.com/foo/bar: invalid char 'G' in first path element\n\n",
+			expectedExitCode:    2,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args:                []string{"a b"},
+			expectedOutput:      helpOutput,
+			expectedErrorOutput: "Error: Invalid module name: a b: invalid char ' '\n\n",
+			expectedExitCode:    2,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args:                []string{"--ci", "travis", "a1"},
+			expectedOutput:      helpOutput,
+			expectedErrorOutput: "Error: Unsupported CI provider: travis (supported: github, gitlab, auto)\n\n",
+			expectedExitCode:    2,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args: []string{"--ci", "gitlab", "a1"},
+			expectedOutput: fmt.Sprintf("Creating Go module: a1\n"+
+				"- Created directory: a1\n"+
+				"- Initialized Go module\n"+
+				"- Created file     : a1/main.go\n"+
+				"- Created file     : a1/main_test.go\n"+
+				"- Created file     : a1/.gitlab-ci.yml\n"+
+				"- Created file     : a1/.gitignore\n"+
+				"- Created directory: a1/.gmc\n"+
+				"- Created file     : a1/.gmc/manifest.json\n"+
+				"\n"+
+				"Finished creating Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd a1\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module a1\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{".gitlab-ci.yml", filePerms, []byte(fmt.Sprintf(gitlabCiContents, goVersion)), nil},
+				{".gitignore", filePerms, []byte("a1"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args: []string{"--go-version", "1.21", "--ci", "gitlab", "a1"},
+			expectedOutput: fmt.Sprintf("Creating Go module: a1\n"+
+				"- Created directory: a1\n"+
+				"- Initialized Go module\n"+
+				"- Created file     : a1/main.go\n"+
+				"- Created file     : a1/main_test.go\n"+
+				"- Created file     : a1/.gitlab-ci.yml\n"+
+				"- Created file     : a1/.gitignore\n"+
+				"- Created directory: a1/.gmc\n"+
+				"- Created file     : a1/.gmc/manifest.json\n"+
+				"\n"+
+				"Finished creating Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd a1\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte("module a1\n\ngo 1.21\n"), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{".gitlab-ci.yml", filePerms, []byte(fmt.Sprintf(gitlabCiContents, "1.21")), nil},
+				{".gitignore", filePerms, []byte("a1"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args: []string{"--toolchain", "go1.22.3", "--ci", "gitlab", "a1"},
+			expectedOutput: fmt.Sprintf("Creating Go module: a1\n"+
+				"- Created directory: a1\n"+
+				"- Initialized Go module\n"+
+				"- Created file     : a1/main.go\n"+
+				"- Created file     : a1/main_test.go\n"+
+				"- Created file     : a1/.envrc\n"+
+				"- Created file     : a1/.gitlab-ci.yml\n"+
+				"- Created file     : a1/.gitignore\n"+
+				"- Created directory: a1/.gmc\n"+
+				"- Created file     : a1/.gmc/manifest.json\n"+
+				"\n"+
+				"Finished creating Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd a1\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte("module a1\n\ngo 1.22\n\ntoolchain go1.22.3\n"), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{".envrc", filePerms, []byte(envrcContents), nil},
+				{".gitlab-ci.yml", filePerms, []byte(strings.Replace(fmt.Sprintf(gitlabCiContents, "1.22.3"), "\n\n", "\n\nvariables:\n  GOTOOLCHAIN: go1.22.3\n\n", 1)), nil},
+				{".gitignore", filePerms, []byte("a1"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args:                []string{"--toolchain", "go1.20.5", "a1"},
+			expectedOutput:      helpOutput,
+			expectedErrorOutput: "Error: Toolchain go1.20.5 can't be pinned: toolchain directives need Go 1.21 or later\n\n",
+			expectedExitCode:    2,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args:                []string{"--go-version", "1.23", "--toolchain", "1.22.3", "a1"},
+			expectedOutput:      helpOutput,
+			expectedErrorOutput: "Error: Toolchain go1.22.3 is older than Go version 1.23\n\n",
+			expectedExitCode:    2,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args:                []string{"--private-proxy", "athens.example.com", "a1"},
+			expectedOutput:      helpOutput,
+			expectedErrorOutput: "Error: Invalid private proxy: athens.example.com (e.g. https://athens.example.com)\n\n",
+			expectedExitCode:    2,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args:                []string{"--create-remote", "github.com/acme/a1"},
+			expectedOutput:      helpOutput,
+			expectedErrorOutput: "Error: --create-remote requires --git\n\n",
+			expectedExitCode:    2,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args:                []string{"--git", "--create-remote", "--private", "--public", "github.com/acme/a1"},
+			expectedOutput:      helpOutput,
+			expectedErrorOutput: "Error: --private and --public can't be used together\n\n",
+			expectedExitCode:    2,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args:                []string{"--split-cmd", "--make", "a1"},
+			expectedOutput:      helpOutput,
+			expectedErrorOutput: "Error: --split-cmd can't be used with --make, which builds the module's root package\n\n",
+			expectedExitCode:    2,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args:                []string{"--push", "github.com/foo/bar"},
+			expectedOutput:      helpOutput,
+			expectedErrorOutput: "Error: --push requires --git\n\n",
+			expectedExitCode:    2,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args:                []string{"--examples", "a1"},
+			expectedOutput:      helpOutput,
+			expectedErrorOutput: "Error: --examples requires --split-cmd\n\n",
+			expectedExitCode:    2,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args:                []string{"--scorecard", "a1"},
+			expectedOutput:      helpOutput,
+			expectedErrorOutput: "Error: --scorecard requires a module under github.com (e.g. github.com/owner/mymodule)\n\n",
+			expectedExitCode:    2,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args:                []string{"--fmt-check", "gofmt", "a1"},
+			expectedOutput:      helpOutput,
+			expectedErrorOutput: "Error: --fmt-check requires --ci\n\n",
+			expectedExitCode:    2,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args:                []string{"--ci", "github", "--fmt-check", "prettier", "a1"},
+			expectedOutput:      helpOutput,
+			expectedErrorOutput: "Error: Unsupported formatter: prettier (supported: gofmt, gofumpt)\n\n",
+			expectedExitCode:    2,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args:                []string{"--go-version", "go1.21", "a1"},
+			expectedOutput:      helpOutput,
+			expectedErrorOutput: "Error: Invalid Go version: go1.21 (e.g. 1.21 or 1.21.3)\n\n",
+			expectedExitCode:    2,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args: []string{"--ci", "auto", "gitlab.com/foo/bar"},
+			expectedOutput: fmt.Sprintf("Creating Go module: gitlab.com/foo/bar\n"+
+				"- Created directory: bar\n"+
+				"- Initialized Go module\n"+
+				"- Created file     : bar/main.go\n"+
+				"- Created file     : bar/main_test.go\n"+
+				"- Created file     : bar/.gitlab-ci.yml\n"+
+				"- Created file     : bar/.gitignore\n"+
+				"- Created directory: bar/.gmc\n"+
+				"- Created file     : bar/.gmc/manifest.json\n"+
+				"\n"+
+				"Finished creating Go module: gitlab.com/foo/bar\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd bar\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"bar", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module gitlab.com/foo/bar\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{".gitlab-ci.yml", filePerms, []byte(fmt.Sprintf(gitlabCiContents, goVersion)), nil},
+				{".gitignore", filePerms, []byte("bar"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args: []string{"--static", "--ci", "github", "a1"},
+			expectedOutput: fmt.Sprintf("Creating Go module: a1\n"+
+				"- Created directory: a1\n"+
+				"- Initialized Go module\n"+
+				"- Created file     : a1/main.go\n"+
+				"- Created file     : a1/main_test.go\n"+
+				"- Created directory: a1/.github\n"+
+				"- Created directory: a1/.github/workflows\n"+
+				"- Created file     : a1/.github/workflows/ci.yml\n"+
+				"- Created file     : a1/.gitignore\n"+
+				"- Created directory: a1/.gmc\n"+
+				"- Created file     : a1/.gmc/manifest.json\n"+
+				"\n"+
+				"Finished creating Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd a1\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module a1\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{".github", dirPerms, nil, []file{
+					{"workflows", dirPerms, nil, []file{
+						{"ci.yml", filePerms, []byte(githubWorkflowStaticContents), nil},
+					}},
+				}},
+				{".gitignore", filePerms, []byte("a1"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args: []string{"--reproducible", "--make", "--ci", "github", "a1"},
+			expectedOutput: fmt.Sprintf("Creating Go module: a1\n"+
+				"- Created directory: a1\n"+
+				"- Initialized Go module\n"+
+				"- Created file     : a1/main.go\n"+
+				"- Created file     : a1/main_test.go\n"+
+				"- Created directory: a1/.github\n"+
+				"- Created directory: a1/.github/workflows\n"+
+				"- Created file     : a1/.github/workflows/ci.yml\n"+
+				"- Created file     : a1/Makefile\n"+
+				"- Created file     : a1/.gitignore\n"+
+				"- Created directory: a1/.gmc\n"+
+				"- Created file     : a1/.gmc/manifest.json\n"+
+				"\n"+
+				"Finished creating Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd a1\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module a1\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{".github", dirPerms, nil, []file{
+					{"workflows", dirPerms, nil, []file{
+						{"ci.yml", filePerms, []byte(githubWorkflowReproducibleContents), nil},
+					}},
+				}},
+				{"Makefile", filePerms, []byte(strings.Replace(makefileContents, "\tgo build -o", "\tCGO_ENABLED=0 go build -trimpath -o", 1)), nil},
+				{".gitignore", filePerms, []byte("a1"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args: []string{"--pgo", "a1"},
+			expectedOutput: fmt.Sprintf("Creating Go module: a1\n"+
+				"- Created directory: a1\n"+
+				"- Initialized Go module\n"+
+				"- Created file     : a1/main.go\n"+
+				"- Created file     : a1/main_test.go\n"+
+				"- Created file     : a1/PGO.md\n"+
+				"- Created file     : a1/default.pgo\n"+
+				"- Created file     : a1/.gitignore\n"+
+				"- Created directory: a1/.gmc\n"+
+				"- Created file     : a1/.gmc/manifest.json\n"+
+				"\n"+
+				"Finished creating Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd a1\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module a1\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{"PGO.md", filePerms, []byte(pgoDocContents), nil},
+				{"default.pgo", filePerms, []byte{}, nil},
+				{".gitignore", filePerms, []byte("a1"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args: []string{"--goreleaser", "--ci", "github", "github.com/foo/bar"},
+			expectedOutput: fmt.Sprintf("Creating Go module: github.com/foo/bar\n"+
+				"- Created directory: bar\n"+
+				"- Initialized Go module\n"+
+				"- Created file     : bar/main.go\n"+
+				"- Created file     : bar/main_test.go\n"+
+				"- Created directory: bar/.github\n"+
+				"- Created directory: bar/.github/workflows\n"+
+				"- Created file     : bar/.github/workflows/ci.yml\n"+
+				"- Created file     : bar/.github/workflows/release.yml\n"+
+				"- Created file     : bar/.goreleaser.yaml\n"+
+				"- Created file     : bar/.gitignore\n"+
+				"- Created directory: bar/.gmc\n"+
+				"- Created file     : bar/.gmc/manifest.json\n"+
+				"\n"+
+				"Finished creating Go module: github.com/foo/bar\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd bar\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"bar", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module github.com/foo/bar\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{".github", dirPerms, nil, []file{
+					{"workflows", dirPerms, nil, []file{
+						{"ci.yml", filePerms, []byte(githubWorkflowContents), nil},
+						{"release.yml", filePerms, []byte(releaseWorkflowContents), nil},
+					}},
+				}},
+				{".goreleaser.yaml", filePerms, []byte(goreleaserConfigContents), nil},
+				{".gitignore", filePerms, []byte("bar\ndist/"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args: []string{"--docker", "--static", "a1"},
+			expectedOutput: fmt.Sprintf("Creating Go module: a1\n"+
+				"- Created directory: a1\n"+
+				"- Initialized Go module\n"+
+				"- Created file     : a1/main.go\n"+
+				"- Created file     : a1/main_test.go\n"+
+				"- Created file     : a1/.dockerignore\n"+
+				"- Created file     : a1/Dockerfile\n"+
+				"- Created file     : a1/.gitignore\n"+
+				"- Created directory: a1/.gmc\n"+
+				"- Created file     : a1/.gmc/manifest.json\n"+
+				"\n"+
+				"Finished creating Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd a1\n"+
+				"- Run module: $ go run .\n"+
+				"- Build container image: $ docker build -t a1 .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module a1\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{".dockerignore", filePerms, []byte(".git\ndist\na1\n"), nil},
+				{"Dockerfile", filePerms, []byte(fmt.Sprintf(dockerfileStaticContents, goVersion)), nil},
+				{".gitignore", filePerms, []byte("a1"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args: []string{"--embed-assets", "a1"},
+			expectedOutput: fmt.Sprintf("Creating Go module: a1\n"+
+				"- Created directory: a1\n"+
+				"- Initialized Go module\n"+
+				"- Created file     : a1/main.go\n"+
+				"- Created file     : a1/main_test.go\n"+
+				"- Created directory: a1/assets\n"+
+				"- Created file     : a1/assets/hello.txt\n"+
+				"- Created file     : a1/.gitignore\n"+
+				"- Created directory: a1/.gmc\n"+
+				"- Created file     : a1/.gmc/manifest.json\n"+
+				"\n"+
+				"Finished creating Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd a1\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module a1\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(embedAssetsMainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{"assets", dirPerms, nil, []file{
+					{"hello.txt", filePerms, []byte("hello, world!\n"), nil},
+				}},
+				{".gitignore", filePerms, []byte("a1"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args: []string{"--dry-run", "--i18n", "a1"},
+			expectedOutput: fmt.Sprintf("Creating Go module (dry run): a1\n"+
+				"- Would create directory: a1\n"+
+				"- Would initialize Go module\n"+
+				"- Would create file     : a1/main.go\n"+
+				"- Would create file     : a1/main_test.go\n"+
+				"- Would create file     : a1/catalog.go\n"+
+				"- Would create directory: a1/locales\n"+
+				"- Would create directory: a1/locales/es\n"+
+				"- Would create file     : a1/locales/es/messages.gotext.json\n"+
+				"- Would add dependency: golang.org/x/text\n"+
+				"- Would create file     : a1/.gitignore\n"+
+				"- Would check dependencies for known vulnerabilities\n"+
+				"- Would create directory: a1/.gmc\n"+
+				"- Would create file     : a1/.gmc/manifest.json\n"+
+				"\n"+
+				"Finished dry run of creating Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd a1\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args:                []string{"--no-deps", "--i18n", "a1"},
+			expectedOutput:      "",
+			expectedErrorOutput: "Failed to create Go module: a1: a1/main.go imports non-standard library package: golang.org/x/text/language\n",
+			expectedExitCode:    1,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args: []string{"--dry-run", "--fail-on-vuln", "--feature-flags", "openfeature", "a1"},
+			expectedOutput: fmt.Sprintf("Creating Go module (dry run): a1\n"+
+				"- Would create directory: a1\n"+
+				"- Would initialize Go module\n"+
+				"- Would create file     : a1/main.go\n"+
+				"- Would create file     : a1/main_test.go\n"+
+				"- Would create file     : a1/flags.go\n"+
+				"- Would add dependency: github.com/open-feature/go-sdk\n"+
+				"- Would create file     : a1/.gitignore\n"+
+				"- Would check dependencies for known vulnerabilities, failing if any are found\n"+
+				"- Would create directory: a1/.gmc\n"+
+				"- Would create file     : a1/.gmc/manifest.json\n"+
+				"\n"+
+				"Finished dry run of creating Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd a1\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args:                []string{"--layout", "flat", "a1"},
+			expectedOutput:      helpOutput,
+			expectedErrorOutput: "Error: Unsupported layout: flat (supported: apiv1)\n\n",
+			expectedExitCode:    2,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args:                []string{"--feature-flags", "launchdarkly", "a1"},
+			expectedOutput:      helpOutput,
+			expectedErrorOutput: "Error: Unsupported feature flags SDK: launchdarkly (supported: openfeature)\n\n",
+			expectedExitCode:    2,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args: []string{"--make", "a1"},
+			expectedOutput: fmt.Sprintf("Creating Go module: a1\n"+
+				"- Created directory: a1\n"+
+				"- Initialized Go module\n"+
+				"- Created file     : a1/main.go\n"+
+				"- Created file     : a1/main_test.go\n"+
+				"- Created file     : a1/Makefile\n"+
+				"- Created file     : a1/.gitignore\n"+
+				"- Created directory: a1/.gmc\n"+
+				"- Created file     : a1/.gmc/manifest.json\n"+
+				"\n"+
+				"Finished creating Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd a1\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module a1\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{"Makefile", filePerms, []byte(makefileContents), nil},
+				{".gitignore", filePerms, []byte("a1"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args: []string{"--targets", "linux/amd64, windows/amd64,,linux/amd64", "--make", "a1"},
+			expectedOutput: fmt.Sprintf("Creating Go module: a1\n"+
+				"- Created directory: a1\n"+
+				"- Initialized Go module\n"+
+				"- Created file     : a1/main.go\n"+
+				"- Created file     : a1/main_test.go\n"+
+				"- Created file     : a1/Makefile\n"+
+				"- Created file     : a1/.gitignore\n"+
+				"- Created directory: a1/.gmc\n"+
+				"- Created file     : a1/.gmc/manifest.json\n"+
+				"\n"+
+				"Finished creating Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd a1\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module a1\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{"Makefile", filePerms, []byte(makefileTargetsContents), nil},
+				{".gitignore", filePerms, []byte("a1\nbin/dist/"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args:                []string{"--targets", "linux/amd64", "a1"},
+			expectedOutput:      helpOutput,
+			expectedErrorOutput: "Error: --targets requires --make, --taskfile, or --goreleaser\n\n",
+			expectedExitCode:    2,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args:                []string{"--targets", "linux/amd64,ios/arm64", "--make", "a1"},
+			expectedOutput:      helpOutput,
+			expectedErrorOutput: "Error: Unsupported target: ios/arm64 (e.g. linux/amd64, darwin/arm64, or windows/amd64)\n\n",
+			expectedExitCode:    2,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args: []string{"--lint", "--ci", "github", "a1"},
+			expectedOutput: fmt.Sprintf("Creating Go module: a1\n"+
+				"- Created directory: a1\n"+
+				"- Initialized Go module\n"+
+				"- Created file     : a1/main.go\n"+
+				"- Created file     : a1/main_test.go\n"+
+				"- Created file     : a1/.golangci.yml\n"+
+				"- Created directory: a1/.github\n"+
+				"- Created directory: a1/.github/workflows\n"+
+				"- Created file     : a1/.github/workflows/ci.yml\n"+
+				"- Created file     : a1/.gitignore\n"+
+				"- Created directory: a1/.gmc\n"+
+				"- Created file     : a1/.gmc/manifest.json\n"+
+				"\n"+
+				"Finished creating Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd a1\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module a1\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{".golangci.yml", filePerms, []byte(fmt.Sprintf(golangciConfigContents,
+					"    - errcheck\n"+
+						"    - govet\n"+
+						"    - ineffassign\n"+
+						"    - staticcheck\n"+
+						"    - unused\n")), nil},
+				{".github", dirPerms, nil, []file{
+					{"workflows", dirPerms, nil, []file{
+						{"ci.yml", filePerms, []byte(githubWorkflowLintContents), nil},
+					}},
+				}},
+				{".gitignore", filePerms, []byte("a1"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args: []string{"--lint", "--make", "--taskfile", "a1"},
+			expectedOutput: fmt.Sprintf("Creating Go module: a1\n"+
+				"- Created directory: a1\n"+
+				"- Initialized Go module\n"+
+				"- Created file     : a1/main.go\n"+
+				"- Created file     : a1/main_test.go\n"+
+				"- Created file     : a1/.golangci.yml\n"+
+				"- Created file     : a1/Makefile\n"+
+				"- Created file     : a1/Taskfile.yml\n"+
+				"- Created file     : a1/.gitignore\n"+
+				"- Created directory: a1/.gmc\n"+
+				"- Created file     : a1/.gmc/manifest.json\n"+
+				"\n"+
+				"Finished creating Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd a1\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module a1\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{".golangci.yml", filePerms, []byte(fmt.Sprintf(golangciConfigContents,
+					"    - errcheck\n"+
+						"    - govet\n"+
+						"    - ineffassign\n"+
+						"    - staticcheck\n"+
+						"    - unused\n")), nil},
+				{"Makefile", filePerms, []byte(strings.Replace(makefileContents, "lint:\n\tgo vet ./...\n", "lint:\n\tgolangci-lint run\n", 1)), nil},
+				{"Taskfile.yml", filePerms, []byte(strings.Replace(taskfileContents, "      - go vet ./...\n", "      - golangci-lint run\n", 1)), nil},
+				{".gitignore", filePerms, []byte("a1"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args:   []string{"--lint", "a1"},
+			config: `{"lint": {"linters": ["revive", "gosec"]}}`,
+			expectedOutput: fmt.Sprintf("Creating Go module: a1\n"+
+				"- Created directory: a1\n"+
+				"- Initialized Go module\n"+
+				"- Created file     : a1/main.go\n"+
+				"- Created file     : a1/main_test.go\n"+
+				"- Created file     : a1/.golangci.yml\n"+
+				"- Created file     : a1/.gitignore\n"+
+				"- Created directory: a1/.gmc\n"+
+				"- Created file     : a1/.gmc/manifest.json\n"+
+				"\n"+
+				"Finished creating Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd a1\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module a1\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{".golangci.yml", filePerms, []byte(fmt.Sprintf(golangciConfigContents,
+					"    - revive\n"+
+						"    - gosec\n")), nil},
+				{".gitignore", filePerms, []byte("a1"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args:   []string{"bar"},
+			config: `{"modulePrefix": "github.com/foo"}`,
+			expectedOutput: fmt.Sprintf("Creating Go module: github.com/foo/bar\n"+
+				"- NOTE: Expanded bar to github.com/foo/bar (use --local to keep it as is)\n"+
+				"- Created directory: bar\n"+
+				"- Initialized Go module\n"+
+				"- Created file     : bar/main.go\n"+
+				"- Created file     : bar/main_test.go\n"+
+				"- Created file     : bar/.gitignore\n"+
+				"- Created directory: bar/.gmc\n"+
+				"- Created file     : bar/.gmc/manifest.json\n"+
+				"\n"+
+				"Finished creating Go module: github.com/foo/bar\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd bar\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"bar", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module github.com/foo/bar\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{".gitignore", filePerms, []byte("bar"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args:   []string{"--local", "a1"},
+			config: `{"modulePrefix": "github.com/foo"}`,
+			expectedOutput: fmt.Sprintf("Creating Go module: a1\n"+
+				"- Created directory: a1\n"+
+				"- Initialized Go module\n"+
+				"- Created file     : a1/main.go\n"+
+				"- Created file     : a1/main_test.go\n"+
+				"- Created file     : a1/.gitignore\n"+
+				"- Created directory: a1/.gmc\n"+
+				"- Created file     : a1/.gmc/manifest.json\n"+
+				"\n"+
+				"Finished creating Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd a1\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module a1\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{".gitignore", filePerms, []byte("a1"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args:                []string{"a1"},
+			config:              `{"lint": `,
+			expectedOutput:      "",
+			expectedErrorOutput: "Failed to create Go module: a1: Invalid config file: unexpected end of JSON input\n",
+			expectedExitCode:    1,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args:   []string{"--git=false", "a1"},
+			config: `{"git": true}`,
+			expectedOutput: fmt.Sprintf("Creating Go module: a1\n"+
+				"- Created directory: a1\n"+
+				"- Initialized Go module\n"+
+				"- Created file     : a1/main.go\n"+
+				"- Created file     : a1/main_test.go\n"+
+				"- Created file     : a1/.gitignore\n"+
+				"- Created directory: a1/.gmc\n"+
+				"- Created file     : a1/.gmc/manifest.json\n"+
+				"\n"+
+				"Finished creating Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd a1\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module a1\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{".gitignore", filePerms, []byte("a1"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args:   []string{"--git", "github.com/foo/bar"},
+			config: `{"remoteProtocol": "https"}`,
+			expectedOutput: fmt.Sprintf("Creating Go module: github.com/foo/bar\n"+
+				"- Created directory: bar\n"+
+				"- Initialized Go module\n"+
+				"- Created file     : bar/main.go\n"+
+				"- Created file     : bar/main_test.go\n"+
+				"- Created file     : bar/.gitignore\n"+
+				"- Initialized Git repository\n"+
+				"- Created file     : bar/README.md\n"+
+				"- Created directory: bar/.gmc\n"+
+				"- Created file     : bar/.gmc/manifest.json\n"+
+				"- Committed all files to Git repository\n"+
+				"- Added remote for Git repository: https://github.com/foo/bar.git\n"+
+				"\n"+
+				"Finished creating Go module: github.com/foo/bar\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd bar\n"+
+				"- Run module: $ go run .\n"+
+				"- Create remote Git repository https://github.com/foo/bar.git: https://github.com/new\n"+
+				"- Push to remote Git repository: $ git push -u origin %s\n"+
+				"- Start coding: $ %s .\n",
+				gitBranchName,
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"bar", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module github.com/foo/bar\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{".git", dirPerms, nil, nil},
+				{".gitignore", filePerms, []byte("bar"), nil},
+				{"README.md", filePerms, []byte("# bar\n\n"), nil},
+			}},
+			expectedGitRepo: &gitRepo{
+				"bar",
+				gitBranchName,
+				[]string{"Initial commit"},
+				ptr("https://github.com/foo/bar.git"),
+			},
+		},
+		{
+			args:                []string{"a1"},
+			config:              `{"remoteProtocol": "ftp"}`,
+			expectedOutput:      "",
+			expectedErrorOutput: "Failed to create Go module: a1: Invalid config file: unsupported remote protocol: ftp (supported: ssh, https)\n",
+			expectedExitCode:    1,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args:                []string{"--git", "--remote-protocol", "ftp", "github.com/foo/bar"},
+			expectedOutput:      helpOutput,
+			expectedErrorOutput: "Error: Unsupported remote protocol: ftp (supported: ssh, https)\n\n",
+			expectedExitCode:    2,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args:                []string{"a1"},
+			config:              `{"editor": "emacs"}`,
+			expectedOutput:      "",
+			expectedErrorOutput: "Failed to create Go module: a1: Invalid config file: unsupported editor: emacs (supported: vscode, goland, nvim)\n",
+			expectedExitCode:    1,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args: []string{"--vscode", "a1"},
+			expectedOutput: "Creating Go module: a1\n" +
+				"- Created directory: a1\n" +
+				"- Initialized Go module\n" +
+				"- Created file     : a1/main.go\n" +
+				"- Created file     : a1/main_test.go\n" +
+				"- Created directory: a1/.vscode\n" +
+				"- Created file     : a1/.vscode/extensions.json\n" +
+				"- Created file     : a1/.vscode/launch.json\n" +
+				"- Created file     : a1/.vscode/settings.json\n" +
+				"- Created file     : a1/.vscode/tasks.json\n" +
+				"- Created file     : a1/.gitignore\n" +
+				"- Created directory: a1/.gmc\n" +
+				"- Created file     : a1/.gmc/manifest.json\n" +
+				"\n" +
+				"Finished creating Go module: a1\n" +
+				"\n" +
+				"Next steps:\n" +
+				"- Change into module's directory: $ cd a1\n" +
+				"- Run module: $ go run .\n" +
+				"- Start coding: $ code .\n",
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module a1\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{".vscode", dirPerms, nil, []file{
+					{"extensions.json", filePerms, []byte(vscodeExtensionsContents), nil},
+					{"launch.json", filePerms, []byte(vscodeLaunchContents), nil},
+					{"settings.json", filePerms, []byte(vscodeSettingsContents), nil},
+					{"tasks.json", filePerms, []byte(vscodeTasksContents), nil},
+				}},
+				{".gitignore", filePerms, []byte("a1"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args: []string{"--goland", "a1"},
+			expectedOutput: "Creating Go module: a1\n" +
+				"- Created directory: a1\n" +
+				"- Initialized Go module\n" +
+				"- Created file     : a1/main.go\n" +
+				"- Created file     : a1/main_test.go\n" +
+				"- Created directory: a1/.idea\n" +
+				"- Created file     : a1/.idea/module.iml\n" +
+				"- Created file     : a1/.idea/modules.xml\n" +
+				"- Created directory: a1/.idea/runConfigurations\n" +
+				"- Created file     : a1/.idea/runConfigurations/Build.xml\n" +
+				"- Created file     : a1/.idea/runConfigurations/Run.xml\n" +
+				"- Created file     : a1/.idea/runConfigurations/Test.xml\n" +
+				"- Created file     : a1/.gitignore\n" +
+				"- Created directory: a1/.gmc\n" +
+				"- Created file     : a1/.gmc/manifest.json\n" +
+				"\n" +
+				"Finished creating Go module: a1\n" +
+				"\n" +
+				"Next steps:\n" +
+				"- Change into module's directory: $ cd a1\n" +
+				"- Run module: $ go run .\n" +
+				"- Start coding: $ goland .\n",
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module a1\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{".idea", dirPerms, nil, []file{
+					{"module.iml", filePerms, []byte(golandModuleImlContents), nil},
+					{"modules.xml", filePerms, []byte(golandModulesContents), nil},
+					{"runConfigurations", dirPerms, nil, []file{
+						{"Build.xml", filePerms, []byte(golandBuildContents), nil},
+						{"Run.xml", filePerms, []byte(golandRunContents), nil},
+						{"Test.xml", filePerms, []byte(golandTestContents), nil},
+					}},
+				}},
+				{".gitignore", filePerms, []byte("a1"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args: []string{"--nvim", "a1"},
+			expectedOutput: "Creating Go module: a1\n" +
+				"- Created directory: a1\n" +
+				"- Initialized Go module\n" +
+				"- Created file     : a1/main.go\n" +
+				"- Created file     : a1/main_test.go\n" +
+				"- Created file     : a1/.nvim.lua\n" +
+				"- Created file     : a1/.gitignore\n" +
+				"- Created directory: a1/.gmc\n" +
+				"- Created file     : a1/.gmc/manifest.json\n" +
+				"\n" +
+				"Finished creating Go module: a1\n" +
+				"\n" +
+				"Next steps:\n" +
+				"- Change into module's directory: $ cd a1\n" +
+				"- Run module: $ go run .\n" +
+				"- Start coding: $ nvim .\n",
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module a1\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{".nvim.lua", filePerms, []byte(nvimConfigContents), nil},
+				{".gitignore", filePerms, []byte("a1"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args: []string{"--golden", "a1"},
+			expectedOutput: fmt.Sprintf("Creating Go module: a1\n"+
+				"- Created directory: a1\n"+
+				"- Initialized Go module\n"+
+				"- Created file     : a1/main.go\n"+
+				"- Created file     : a1/main_test.go\n"+
+				"- Created file     : a1/golden_test.go\n"+
+				"- Created file     : a1/output_test.go\n"+
+				"- Created directory: a1/testdata\n"+
+				"- Created file     : a1/testdata/main_output.golden\n"+
+				"- Created file     : a1/.gitignore\n"+
+				"- Created directory: a1/.gmc\n"+
+				"- Created file     : a1/.gmc/manifest.json\n"+
+				"\n"+
+				"Finished creating Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd a1\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module a1\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{"golden_test.go", filePerms, []byte(goldenHelperContents), nil},
+				{"output_test.go", filePerms, []byte(goldenOutputTestContents), nil},
+				{"testdata", dirPerms, nil, []file{
+					{"main_output.golden", filePerms, []byte("hello, world!\n"), nil},
+				}},
+				{".gitignore", filePerms, []byte("a1"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args: []string{"--mutation", "--make", "a1"},
+			expectedOutput: fmt.Sprintf("Creating Go module: a1\n"+
+				"- Created directory: a1\n"+
+				"- Initialized Go module\n"+
+				"- Created file     : a1/main.go\n"+
+				"- Created file     : a1/main_test.go\n"+
+				"- Created file     : a1/.gremlins.yaml\n"+
+				"- Created file     : a1/Makefile\n"+
+				"- Created file     : a1/.gitignore\n"+
+				"- Created directory: a1/.gmc\n"+
+				"- Created file     : a1/.gmc/manifest.json\n"+
+				"\n"+
+				"Finished creating Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd a1\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module a1\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{".gremlins.yaml", filePerms, []byte(gremlinsConfigContents), nil},
+				{"Makefile", filePerms, []byte(makefileMutationContents), nil},
+				{".gitignore", filePerms, []byte("a1"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args: []string{"--cloud-dev", "gitpod", "a1"},
+			expectedOutput: fmt.Sprintf("Creating Go module: a1\n"+
+				"- Created directory: a1\n"+
+				"- Initialized Go module\n"+
+				"- Created file     : a1/main.go\n"+
+				"- Created file     : a1/main_test.go\n"+
+				"- Created file     : a1/.gitpod.yml\n"+
+				"- Created file     : a1/.gitignore\n"+
+				"- Created directory: a1/.gmc\n"+
+				"- Created file     : a1/.gmc/manifest.json\n"+
+				"\n"+
+				"Finished creating Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd a1\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module a1\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{".gitpod.yml", filePerms, []byte(gitpodConfigContents), nil},
+				{".gitignore", filePerms, []byte("a1"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args: []string{"--cloud-dev", "codespaces", "--go-version", "1.22", "a1"},
+			expectedOutput: fmt.Sprintf("Creating Go module: a1\n"+
+				"- Created directory: a1\n"+
+				"- Initialized Go module\n"+
+				"- Created file     : a1/main.go\n"+
+				"- Created file     : a1/main_test.go\n"+
+				"- Created directory: a1/.devcontainer\n"+
+				"- Created file     : a1/.devcontainer/devcontainer.json\n"+
+				"- Created file     : a1/.gitignore\n"+
+				"- Created directory: a1/.gmc\n"+
+				"- Created file     : a1/.gmc/manifest.json\n"+
+				"\n"+
+				"Finished creating Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd a1\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte("module a1\n\ngo 1.22\n"), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{".devcontainer", dirPerms, nil, []file{
+					{"devcontainer.json", filePerms, []byte(fmt.Sprintf(devcontainerContents, "1.22")), nil},
+				}},
+				{".gitignore", filePerms, []byte("a1"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args:                []string{"--cloud-dev", "replit", "a1"},
+			expectedOutput:      helpOutput,
+			expectedErrorOutput: "Error: Unsupported cloud development environment: replit (supported: gitpod, codespaces)\n\n",
+			expectedExitCode:    2,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args: []string{"--scripts", "--make", "--ci", "github", "a1"},
+			expectedOutput: fmt.Sprintf("Creating Go module: a1\n"+
+				"- Created directory: a1\n"+
+				"- Initialized Go module\n"+
+				"- Created file     : a1/main.go\n"+
+				"- Created file     : a1/main_test.go\n"+
+				"- Created directory: a1/.github\n"+
+				"- Created directory: a1/.github/workflows\n"+
+				"- Created file     : a1/.github/workflows/ci.yml\n"+
+				"- Created file     : a1/Makefile\n"+
+				"- Created directory: a1/script\n"+
+				"- Created file     : a1/script/bootstrap\n"+
+				"- Created file     : a1/script/build\n"+
+				"- Created file     : a1/script/server\n"+
+				"- Created file     : a1/script/test\n"+
+				"- Created file     : a1/.gitignore\n"+
+				"- Created directory: a1/.gmc\n"+
+				"- Created file     : a1/.gmc/manifest.json\n"+
+				"\n"+
+				"Finished creating Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd a1\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module a1\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{".github", dirPerms, nil, []file{
+					{"workflows", dirPerms, nil, []file{
+						{"ci.yml", filePerms, []byte(githubWorkflowScriptsContents), nil},
+					}},
+				}},
+				{"Makefile", filePerms, []byte(scriptsMakefileContents), nil},
+				{"script", dirPerms, nil, []file{
+					{"bootstrap", executablePerms, []byte(scriptBootstrapContents), nil},
+					{"build", executablePerms, []byte(scriptBuildContents), nil},
+					{"server", executablePerms, []byte(scriptServerContents), nil},
+					{"test", executablePerms, []byte(scriptTestContents), nil},
+				}},
+				{".gitignore", filePerms, []byte("a1"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args: []string{"--scripts", "--powershell", "a1"},
+			expectedOutput: fmt.Sprintf("Creating Go module: a1\n"+
+				"- Created directory: a1\n"+
+				"- Initialized Go module\n"+
+				"- Created file     : a1/main.go\n"+
+				"- Created file     : a1/main_test.go\n"+
+				"- Created directory: a1/script\n"+
+				"- Created file     : a1/script/bootstrap\n"+
+				"- Created file     : a1/script/build\n"+
+				"- Created file     : a1/script/server\n"+
+				"- Created file     : a1/script/test\n"+
+				"- Created file     : a1/script/bootstrap.ps1\n"+
+				"- Created file     : a1/script/build.ps1\n"+
+				"- Created file     : a1/script/server.ps1\n"+
+				"- Created file     : a1/script/test.ps1\n"+
+				"- Created file     : a1/.gitignore\n"+
+				"- Created directory: a1/.gmc\n"+
+				"- Created file     : a1/.gmc/manifest.json\n"+
+				"\n"+
+				"Finished creating Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd a1\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module a1\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{"script", dirPerms, nil, []file{
+					{"bootstrap", executablePerms, []byte(scriptBootstrapContents), nil},
+					{"build", executablePerms, []byte(scriptBuildContents), nil},
+					{"server", executablePerms, []byte(scriptServerContents), nil},
+					{"test", executablePerms, []byte(scriptTestContents), nil},
+					{"bootstrap.ps1", filePerms, []byte(scriptBootstrapPowerShellContents), nil},
+					{"build.ps1", filePerms, []byte(scriptBuildPowerShellContents), nil},
+					{"server.ps1", filePerms, []byte(scriptServerPowerShellContents), nil},
+					{"test.ps1", filePerms, []byte(scriptTestPowerShellContents), nil},
+				}},
+				{".gitignore", filePerms, []byte("a1\na1.exe"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args:                []string{"--powershell", "a1"},
+			expectedOutput:      helpOutput,
+			expectedErrorOutput: "Error: --powershell requires --scripts\n\n",
+			expectedExitCode:    2,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args: []string{"--bootstrap-script", "--go-version", "1.22", "a1"},
+			expectedOutput: fmt.Sprintf("Creating Go module: a1\n"+
+				"- Created directory: a1\n"+
+				"- Initialized Go module\n"+
+				"- Created file     : a1/main.go\n"+
+				"- Created file     : a1/main_test.go\n"+
+				"- Created directory: a1/script\n"+
+				"- Created file     : a1/script/bootstrap\n"+
+				"- Created file     : a1/.gitignore\n"+
+				"- Created directory: a1/.gmc\n"+
+				"- Created file     : a1/.gmc/manifest.json\n"+
+				"\n"+
+				"Finished creating Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd a1\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte("module a1\n\ngo 1.22\n"), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{"script", dirPerms, nil, []file{
+					{"bootstrap", executablePerms, []byte(bootstrapScriptContents), nil},
+				}},
+				{".gitignore", filePerms, []byte("a1"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args: []string{"--taskfile", "a1"},
+			expectedOutput: fmt.Sprintf("Creating Go module: a1\n"+
+				"- Created directory: a1\n"+
+				"- Initialized Go module\n"+
+				"- Created file     : a1/main.go\n"+
+				"- Created file     : a1/main_test.go\n"+
+				"- Created file     : a1/Taskfile.yml\n"+
+				"- Created file     : a1/.gitignore\n"+
+				"- Created directory: a1/.gmc\n"+
+				"- Created file     : a1/.gmc/manifest.json\n"+
+				"\n"+
+				"Finished creating Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd a1\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module a1\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{"Taskfile.yml", filePerms, []byte(taskfileContents), nil},
+				{".gitignore", filePerms, []byte("a1"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args:   []string{"--make=false", "a1"},
+			config: `{"make": true, "taskfile": true}`,
+			expectedOutput: fmt.Sprintf("Creating Go module: a1\n"+
+				"- Created directory: a1\n"+
+				"- Initialized Go module\n"+
+				"- Created file     : a1/main.go\n"+
+				"- Created file     : a1/main_test.go\n"+
+				"- Created file     : a1/Taskfile.yml\n"+
+				"- Created file     : a1/.gitignore\n"+
+				"- Created directory: a1/.gmc\n"+
+				"- Created file     : a1/.gmc/manifest.json\n"+
+				"\n"+
+				"Finished creating Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd a1\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module a1\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{"Taskfile.yml", filePerms, []byte(taskfileContents), nil},
+				{".gitignore", filePerms, []byte("a1"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args: []string{"new", "a1"},
+			expectedOutput: fmt.Sprintf("Creating Go module: a1\n"+
+				"- Created directory: a1\n"+
+				"- Initialized Go module\n"+
+				"- Created file     : a1/main.go\n"+
+				"- Created file     : a1/main_test.go\n"+
+				"- Created file     : a1/.gitignore\n"+
+				"- Created directory: a1/.gmc\n"+
+				"- Created file     : a1/.gmc/manifest.json\n"+
+				"\n"+
+				"Finished creating Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Change into module's directory: $ cd a1\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module a1\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{".gitignore", filePerms, []byte("a1"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args:           []string{"init", "a1"},
+			existingModule: &file{"a1", dirPerms, nil, []file{{"main.go", filePerms, []byte("package main\n"), nil}}},
+			expectedOutput: fmt.Sprintf("Creating Go module: a1\n"+
+				"- Initialized Go module\n"+
+				"- NOTE: Kept existing file: main.go\n"+
+				"- Created file     : .gitignore\n"+
+				"- Created directory: .gmc\n"+
+				"- Created file     : .gmc/manifest.json\n"+
+				"\n"+
+				"Finished creating Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module a1\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte("package main\n"), nil},
+				{".gitignore", filePerms, []byte("a1"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args:                []string{"init", "a1"},
+			existingModule:      &file{"a1", dirPerms, nil, []file{{"go.mod", filePerms, []byte("module a1\n\ngo 1.18\n"), nil}}},
+			expectedOutput:      "",
+			expectedErrorOutput: "Failed to create Go module: a1: Already a Go module (use `gmc add` to add features)\n",
+			expectedExitCode:    3,
+			expectedFiles:       &file{"a1", dirPerms, nil, []file{{"go.mod", filePerms, []byte("module a1\n\ngo 1.18\n"), nil}}},
+			expectedGitRepo:     nil,
+		},
+		{
+			args:                []string{"init", "--color=always", "a1"},
+			existingModule:      &file{"a1", dirPerms, nil, []file{{"go.mod", filePerms, []byte("module a1\n\ngo 1.18\n"), nil}}},
+			expectedOutput:      "",
+			expectedErrorOutput: "\x1b[31mFailed to create Go module: a1: Already a Go module (use `gmc add` to add features)\x1b[0m\n",
+			expectedExitCode:    3,
+			expectedFiles:       &file{"a1", dirPerms, nil, []file{{"go.mod", filePerms, []byte("module a1\n\ngo 1.18\n"), nil}}},
+			expectedGitRepo:     nil,
+		},
+		{
+			args: []string{"--color=always", "a1"},
+			env:  map[string]string{"NO_COLOR": "1"},
+			expectedOutput: fmt.Sprintf("Creating Go module: a1\n"+
+				"- \x1b[32mCreated directory\x1b[0m: a1\n"+
+				"- \x1b[32mInitialized Go module\x1b[0m\n"+
+				"- \x1b[32mCreated file     \x1b[0m: a1/main.go\n"+
+				"- \x1b[32mCreated file     \x1b[0m: a1/main_test.go\n"+
+				"- \x1b[32mCreated file     \x1b[0m: a1/.gitignore\n"+
+				"- \x1b[32mCreated directory\x1b[0m: a1/.gmc\n"+
+				"- \x1b[32mCreated file     \x1b[0m: a1/.gmc/manifest.json\n"+
+				"\n"+
+				"\x1b[1;32mFinished creating Go module: a1\x1b[0m\n"+
+				"\n"+
+				"\x1b[1mNext steps:\x1b[0m\n"+
+				"- Change into module's directory: $ cd a1\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte(fmt.Sprintf("module a1\n\ngo %s\n", goVersion)), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{".gitignore", filePerms, []byte("a1"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args:                []string{"--color=bright", "a1"},
+			expectedOutput:      helpOutput,
+			expectedErrorOutput: "Error: Unsupported color mode: bright (supported: auto, always, never)\n\n",
+			expectedExitCode:    2,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args: []string{"init", "--resume", "a1"},
+			existingModule: &file{"a1", dirPerms, nil, []file{
+				{"go.mod", filePerms, []byte("module a1\n\ngo 1.18\n"), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents[:20]), nil}, // Cut short by the interruption
+			}},
+			expectedOutput: fmt.Sprintf("Creating Go module: a1\n"+
+				"- NOTE: Resuming in ., skipping what was already done\n"+
+				"- Created file     : main_test.go\n"+
+				"- Created file     : .gitignore\n"+
+				"- Created directory: .gmc\n"+
+				"- Created file     : .gmc/manifest.json\n"+
+				"\n"+
+				"Finished creating Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Run module: $ go run .\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{".gmc", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte("module a1\n\ngo 1.18\n"), nil},
+				{"main.go", filePerms, []byte(mainGoContents), nil},
+				{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+				{".gitignore", filePerms, []byte("a1"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args:                []string{"--timeout", "1ns", "a1"},
+			expectedOutput:      "Creating Go module: a1\n",
+			expectedErrorOutput: "Failed to create Go module: a1: Timed out\n",
+			expectedExitCode:    1,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args:                []string{"--resume", "a1"},
+			expectedOutput:      "",
+			expectedErrorOutput: "Failed to create Go module: a1: Nothing to resume: a1 has no go.mod for a1\n",
+			expectedExitCode:    1,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args:           []string{"add", "license", "mit"},
+			existingModule: &file{"a1", dirPerms, nil, []file{{"go.mod", filePerms, []byte("module a1\n\ngo 1.18\n"), nil}}},
+			expectedOutput: fmt.Sprintf("Adding license to Go module: a1\n"+
+				"- Created file     : LICENSE\n"+
+				"\n"+
+				"Finished adding license to Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{"go.mod", filePerms, []byte("module a1\n\ngo 1.18\n"), nil},
+				{"LICENSE", filePerms, []byte(fmt.Sprintf(mitLicenseContents, licenseYear, licenseAuthor)), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args: []string{"add", "make"},
+			existingModule: &file{"a1", dirPerms, nil, []file{
+				{"go.mod", filePerms, []byte("module a1\n\ngo 1.18\n"), nil},
+				{"Makefile", filePerms, []byte("all:\n"), nil},
+			}},
+			expectedOutput: fmt.Sprintf("Adding make to Go module: a1\n"+
+				"- NOTE: Kept existing file: Makefile\n"+
+				"\n"+
+				"Finished adding make to Go module: a1\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Start coding: $ %s .\n",
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"a1", dirPerms, nil, []file{
+				{"go.mod", filePerms, []byte("module a1\n\ngo 1.18\n"), nil},
+				{"Makefile", filePerms, []byte("all:\n"), nil},
+			}},
+			expectedGitRepo: nil,
+		},
+		{
+			args: []string{"add", "git"},
+			existingModule: &file{"bar", dirPerms, nil, []file{
+				{"go.mod", filePerms, []byte("module github.com/foo/bar\n\ngo 1.18\n"), nil},
+			}},
+			expectedOutput: fmt.Sprintf("Adding git to Go module: github.com/foo/bar\n"+
+				"- Initialized Git repository\n"+
+				"- Created file     : README.md\n"+
+				"- Committed all files to Git repository\n"+
+				"- Added remote for Git repository: git@github.com:foo/bar.git\n"+
+				"\n"+
+				"Finished adding git to Go module: github.com/foo/bar\n"+
+				"\n"+
+				"Next steps:\n"+
+				"- Create remote Git repository git@github.com:foo/bar.git: https://github.com/new\n"+
+				"- Push to remote Git repository: $ git push -u origin %s\n"+
+				"- Start coding: $ %s .\n",
+				gitBranchName,
+				editor),
+			expectedErrorOutput: "",
+			expectedExitCode:    0,
+			expectedFiles: &file{"bar", dirPerms, nil, []file{
+				{".git", dirPerms, nil, nil},
+				{"go.mod", filePerms, []byte("module github.com/foo/bar\n\ngo 1.18\n"), nil},
+				{"README.md", filePerms, []byte("# bar\n\n"), nil},
+			}},
+			expectedGitRepo: &gitRepo{
+				dir:            "bar",
+				branchName:     gitBranchName,
+				commitMessages: []string{"Initial commit"},
+				remote:         ptr("git@github.com:foo/bar.git"),
+			},
+		},
+		{
+			args:                []string{"add", "make"},
+			expectedOutput:      "",
+			expectedErrorOutput: "Failed to add make: No go.mod in the current directory (run from the root of a Go module)\n",
+			expectedExitCode:    1,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args:                []string{"add"},
+			expectedOutput:      addHelpOutput,
+			expectedErrorOutput: "Error: Feature is required (supported: git, license, ci, make, taskfile, docker, vscode, goland, nvim)\n\n",
+			expectedExitCode:    2,
+			expectedFiles:       nil,
+			expectedGitRepo:     nil,
+		},
+		{
+			args:                []string{"add", "bogus"},
+			existingModule:      &file{"a1", dirPerms, nil, []file{{"go.mod", filePerms, []byte("module a1\n\ngo 1.18\n"), nil}}},
+			expectedOutput:      addHelpOutput,
+			expectedErrorOutput: "Error: Unsupported feature: bogus (supported: git, license, ci, make, taskfile, docker, vscode, goland, nvim)\n\n",
+			expectedExitCode:    2,
+			expectedFiles:       &file{"a1", dirPerms, nil, []file{{"go.mod", filePerms, []byte("module a1\n\ngo 1.18\n"), nil}}},
+			expectedGitRepo:     nil,
+		},
+	}
+
+	t.Setenv("EDITOR", editor) // Automatically reset
+
+	for _, tc := range tests {
+		testName := strings.Join(tc.args, " ")
+		t.Run(testName, func(t *testing.T) {
+			testRunTestCase(t, tc)
+		})
+	}
+}
+
+func testRunTestCase(t *testing.T, tc testRunTestCaseData) {
+	tempTestDir := t.TempDir() // Automatically cleaned up
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Chdir(tempTestDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		err = os.Chdir(cwd)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	// Never read the config file of the user running the tests
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	if tc.config != "" {
+		err = os.WriteFile(configPath, []byte(tc.config), 0644)
 		if err != nil {
 			t.Fatal(err)
 		}
-	})
+	}
+	t.Setenv("GMC_CONFIG", configPath) // Automatically reset
+	for key, value := range tc.env {
+		t.Setenv(key, value) // Automatically reset
+	}
 
 	var outputBuffer bytes.Buffer
 	var errorOutputBuffer bytes.Buffer
@@ -357,9 +3758,29 @@ func testRunTestCase(t *testing.T, tc testRunTestCaseData) {
 		}
 	}
 
-	app := cli.AppWithCustomEverything(&outputBuffer, &errorOutputBuffer, exitCodeHandler, ptr(gitBranchName))
+	// Run from inside the existing module, if any
+	if tc.existingModule != nil {
+		writeFiles(t, *tc.existingModule, tempTestDir)
+		err = os.Chdir(tc.existingModule.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	app := cli.App(
+		cli.WithOutput(&outputBuffer),
+		cli.WithErrorOutput(&errorOutputBuffer),
+		cli.WithExitHandler(exitCodeHandler),
+		cli.WithGitBranch(gitBranchName),
+		cli.WithClock(func() time.Time { return licenseTime }),
+	)
 	args := append([]string{cli.Name}, tc.args...)
 	_ = app.Run(args)
+
+	err = os.Chdir(tempTestDir)
+	if err != nil {
+		t.Fatal(err)
+	}
 	actualOutput := outputBuffer.String()
 	actualErrorOutput := errorOutputBuffer.String()
 
@@ -408,6 +3829,21 @@ func assertExpectedFilesExist(t *testing.T, expectedFiles *file) {
 	}
 }
 
+func writeFiles(t *testing.T, f file, root string) {
+	walkDir(f, root, func(f file, root string) {
+		filePath := filepath.Join(root, f.name)
+		var err error
+		if f.content != nil {
+			err = os.WriteFile(filePath, f.content, f.perm)
+		} else {
+			err = os.Mkdir(filePath, f.perm.Perm())
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
 func walkDir(f file, root string, fn func(file, string)) {
 	fn(f, root)
 
@@ -541,6 +3977,24 @@ func assertExpectedGitRepoExists(t *testing.T, expectedGitRepo gitRepo) {
 	}
 }
 
+func gitUserName(t *testing.T) string {
+	cmd := exec.Command("git", "config", "--global", "user.name")
+	cmdOutput, err := cmd.Output()
+	if err != nil {
+		t.Fatal("Unable to look up Git user.name", err)
+	}
+	return strings.TrimSpace(string(cmdOutput))
+}
+
+func localGoVersion(t *testing.T) string {
+	cmd := exec.Command("go", "env", "GOVERSION")
+	cmdOutput, err := cmd.Output()
+	if err != nil {
+		t.Fatal("Unable to look up Go version", err)
+	}
+	return regexp.MustCompile(`^go(\d+\.\d+)`).FindStringSubmatch(strings.TrimSpace(string(cmdOutput)))[1]
+}
+
 func testCaseUnexpectedMessage[T any](thing string, expected T, actual T) string {
 	return fmt.Sprintf("Unexpected %s\nExpected: %v\nActual  : %v\n", thing, expected, actual)
 }
@@ -548,3 +4002,603 @@ func testCaseUnexpectedMessage[T any](thing string, expected T, actual T) string
 func ptr[T any](t T) *T {
 	return &t
 }
+
+func TestPlanAndApply(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Chdir(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		err = os.Chdir(cwd)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Setenv("GMC_CONFIG", filepath.Join(t.TempDir(), "config.json")) // Automatically reset
+
+	// Plan
+	var planBuffer bytes.Buffer
+	var errorOutputBuffer bytes.Buffer
+	exitCode := 0
+	app := cli.App(cli.WithOutput(&planBuffer), cli.WithErrorOutput(&errorOutputBuffer), cli.WithExitHandler(func(c int) { exitCode = c }))
+	_ = app.Run([]string{cli.Name, "plan", "--local", "a1"})
+	if exitCode != 0 {
+		t.Fatal(testCaseUnexpectedMessage("error output", "", errorOutputBuffer.String()))
+	}
+	if _, err := os.Stat("a1"); !errors.Is(err, fs.ErrNotExist) {
+		t.Error("Directory was created by plan: a1")
+	}
+	err = os.WriteFile("plan.json", planBuffer.Bytes(), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Apply
+	var outputBuffer bytes.Buffer
+	app = cli.App(cli.WithOutput(&outputBuffer), cli.WithErrorOutput(&errorOutputBuffer), cli.WithExitHandler(func(c int) { exitCode = c }))
+	_ = app.Run([]string{cli.Name, "apply", "-q", "plan.json"})
+	if exitCode != 0 {
+		t.Fatal(testCaseUnexpectedMessage("error output", "", errorOutputBuffer.String()))
+	}
+	assertExpectedFilesExist(t, &file{"a1", dirPerms, nil, []file{
+		{".gmc", dirPerms, nil, nil},
+		{"go.mod", filePerms, []byte(fmt.Sprintf("module a1\n\ngo %s\n", localGoVersion(t))), nil},
+		{"main.go", filePerms, []byte(mainGoContents), nil},
+		{"main_test.go", filePerms, []byte(mainTestGoContents), nil},
+		{".gitignore", filePerms, []byte("a1"), nil},
+	}})
+
+	// Apply a plan with an unknown action
+	err = os.WriteFile("bad.json", []byte(`{"module": "a2", "dir": "a2", "steps": [{"action": "deleteDir", "path": "/"}]}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	errorOutputBuffer.Reset()
+	app = cli.App(cli.WithOutput(&outputBuffer), cli.WithErrorOutput(&errorOutputBuffer), cli.WithExitHandler(func(c int) { exitCode = c }))
+	_ = app.Run([]string{cli.Name, "apply", "bad.json"})
+	expectedErrorOutput := "Failed to read plan: bad.json: Invalid plan: unknown action: deleteDir\n"
+	if errorOutputBuffer.String() != expectedErrorOutput {
+		t.Error(testCaseUnexpectedMessage("error output", expectedErrorOutput, errorOutputBuffer.String()))
+	}
+	if exitCode != 1 {
+		t.Error(testCaseUnexpectedMessage("exit code", 1, exitCode))
+	}
+}
+
+func TestBatchDashboard(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Chdir(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		err = os.Chdir(cwd)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+	configFile := filepath.Join(t.TempDir(), "config.json")
+	t.Setenv("GMC_CONFIG", configFile) // Automatically reset
+
+	// A config file, so that gmc doesn't offer onboarding on the "terminal"
+	err = os.WriteFile(configFile, []byte("{}"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.MkdirAll(filepath.Join("a2", "b"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var outputBuffer bytes.Buffer
+	var errorOutputBuffer bytes.Buffer
+	exitCode := 0
+	app := cli.App(cli.WithOutput(&outputBuffer), cli.WithErrorOutput(&errorOutputBuffer), cli.WithExitHandler(func(c int) { exitCode = c }), cli.WithTerminal(true))
+	_ = app.Run([]string{cli.Name, "--local", "a1", "a2"})
+
+	// Redrawn as each module is created
+	lastDrawn := "\x1b[2Ka1  created   Created file     : a1/.gmc/manifest.json\n" +
+		"\x1b[2Ka2  failed    Failed to create Go module: a2: Directory already exists: a2 (use --force to create the module in it)\n"
+	expectedSummary := "\n" +
+		"MODULE  STATUS   STEPS  ERROR\n" +
+		"a1      created  7      \n" +
+		"a2      failed   0      Failed to create Go module: a2: Directory already exists: a2 (use --force to create the module in it)\n" +
+		"\n" +
+		"Created 1 of 2 Go modules\n"
+	if !strings.HasSuffix(outputBuffer.String(), lastDrawn+expectedSummary) {
+		t.Error(testCaseUnexpectedMessage("output ending", lastDrawn+expectedSummary, outputBuffer.String()))
+	}
+	expectedErrorOutput := "Failed to create Go modules: a2\n"
+	if errorOutputBuffer.String() != expectedErrorOutput {
+		t.Error(testCaseUnexpectedMessage("error output", expectedErrorOutput, errorOutputBuffer.String()))
+	}
+	if exitCode != 1 {
+		t.Error(testCaseUnexpectedMessage("exit code", 1, exitCode))
+	}
+}
+
+func TestHelpCommand(t *testing.T) {
+	for _, topic := range []string{"config", "manifest", "remote", "templates"} {
+		var outputBuffer bytes.Buffer
+		var errorOutputBuffer bytes.Buffer
+		exitCode := 0
+		app := cli.App(cli.WithOutput(&outputBuffer), cli.WithErrorOutput(&errorOutputBuffer), cli.WithExitHandler(func(c int) { exitCode = c }))
+		_ = app.Run([]string{cli.Name, "help", topic})
+
+		if !strings.HasPrefix(outputBuffer.String(), "# ") || strings.Contains(outputBuffer.String(), "```") {
+			t.Error(testCaseUnexpectedMessage("output of help "+topic, "rendered help topic", outputBuffer.String()))
+		}
+		if errorOutputBuffer.String() != "" {
+			t.Error(testCaseUnexpectedMessage("error output", "", errorOutputBuffer.String()))
+		}
+		if exitCode != 0 {
+			t.Error(testCaseUnexpectedMessage("exit code", 0, exitCode))
+		}
+	}
+
+	var outputBuffer bytes.Buffer
+	var errorOutputBuffer bytes.Buffer
+	exitCode := 0
+	app := cli.App(cli.WithOutput(&outputBuffer), cli.WithErrorOutput(&errorOutputBuffer), cli.WithExitHandler(func(c int) { exitCode = c }))
+	_ = app.Run([]string{cli.Name, "help", "nope"})
+
+	expectedErrorOutput := "Error: Unknown help topic: nope (topics: config, manifest, remote, templates)\n\n"
+	if errorOutputBuffer.String() != expectedErrorOutput {
+		t.Error(testCaseUnexpectedMessage("error output", expectedErrorOutput, errorOutputBuffer.String()))
+	}
+	if exitCode != 2 {
+		t.Error(testCaseUnexpectedMessage("exit code", 2, exitCode))
+	}
+}
+
+func TestCompletionCommand(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+		var outputBuffer bytes.Buffer
+		exitCode := 0
+		app := cli.App(cli.WithOutput(&outputBuffer), cli.WithExitHandler(func(c int) { exitCode = c }))
+		_ = app.Run([]string{cli.Name, "completion", shell})
+
+		if !strings.Contains(outputBuffer.String(), cli.Name+" __complete") {
+			t.Error(testCaseUnexpectedMessage("output of completion "+shell, "completion script", outputBuffer.String()))
+		}
+		if exitCode != 0 {
+			t.Error(testCaseUnexpectedMessage("exit code", 0, exitCode))
+		}
+	}
+
+	// Words typed so far, the last being the one completed
+	tests := []struct {
+		words               []string
+		expectedCompletions string
+	}{
+		{[]string{""}, "new\ninit\nadd\nadopt\nplan\napply\nconfig\nserve\ntemplates\naudit\nrelease-prep\ndoctor\nupgrade-self\ncompletion\nman\nhelp\n"},
+		{[]string{"a"}, "add\nadopt\napply\naudit\n"},
+		{[]string{"--ci", ""}, "github\ngitlab\nauto\n"},
+		{[]string{"mymodule", "--ci=g"}, "--ci=github\n--ci=gitlab\n"},
+		{[]string{"--git", "--lic"}, "--license\n"},
+		{[]string{"new", "-C", "out", "--skip", "d"}, "deps\n"},
+		{[]string{"add", ""}, "git\nlicense\nci\nmake\ntaskfile\ndocker\nvscode\ngoland\nnvim\n"},
+		{[]string{"add", "license", "b"}, "bsd-3-clause\n"},
+		{[]string{"help", "t"}, "templates\n"},
+		{[]string{"completion", "p"}, "powershell\n"},
+		{[]string{"--archive", ""}, ""},
+		{[]string{"mymodule", ""}, ""},
+	}
+	for _, tc := range tests {
+		var outputBuffer bytes.Buffer
+		app := cli.App(cli.WithOutput(&outputBuffer), cli.WithExitHandler(func(c int) {}))
+		_ = app.Run(append([]string{cli.Name, "__complete"}, tc.words...))
+
+		if outputBuffer.String() != tc.expectedCompletions {
+			t.Error(testCaseUnexpectedMessage(fmt.Sprintf("completions of %q", tc.words), tc.expectedCompletions, outputBuffer.String()))
+		}
+	}
+}
+
+func TestManCommand(t *testing.T) {
+	var outputBuffer bytes.Buffer
+	exitCode := 0
+	app := cli.App(cli.WithOutput(&outputBuffer), cli.WithExitHandler(func(c int) { exitCode = c }))
+	_ = app.Run([]string{cli.Name, "man"})
+
+	output := outputBuffer.String()
+	for _, expected := range []string{".TH " + cli.Name + " 1\n", "\n.SH GLOBAL OPTIONS\n", "\n.SH completion\n"} {
+		if !strings.Contains(output, expected) {
+			t.Error(testCaseUnexpectedMessage("output of man", expected, output))
+		}
+	}
+	// The hidden command that completion scripts call isn't documented
+	if strings.Contains(output, "__complete") {
+		t.Error(testCaseUnexpectedMessage("output of man", "no __complete", output))
+	}
+	if exitCode != 0 {
+		t.Error(testCaseUnexpectedMessage("exit code", 0, exitCode))
+	}
+}
+
+func TestConfigCommand(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	err := os.WriteFile(configPath, []byte(`{"modulePrefix": "github.com/foo"}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GMC_CONFIG", configPath) // Automatically reset
+
+	var outputBuffer bytes.Buffer
+	var errorOutputBuffer bytes.Buffer
+	exitCode := 0
+	app := cli.App(cli.WithOutput(&outputBuffer), cli.WithErrorOutput(&errorOutputBuffer), cli.WithExitHandler(func(c int) { exitCode = c }))
+	_ = app.Run([]string{cli.Name, "config"})
+
+	expectedOutput := fmt.Sprintf("Config file: %s\n"+
+		"\n"+
+		"{\n"+
+		"  \"modulePrefix\": \"github.com/foo\",\n"+
+		"  \"infer\": false,\n"+
+		"  \"git\": false,\n"+
+		"  \"make\": false,\n"+
+		"  \"taskfile\": false,\n"+
+		"  \"lint\": {\n"+
+		"    \"linters\": [\n"+
+		"      \"errcheck\",\n"+
+		"      \"govet\",\n"+
+		"      \"ineffassign\",\n"+
+		"      \"staticcheck\",\n"+
+		"      \"unused\"\n"+
+		"    ]\n"+
+		"  }\n"+
+		"}\n",
+		configPath)
+	if outputBuffer.String() != expectedOutput {
+		t.Error(testCaseUnexpectedMessage("output", expectedOutput, outputBuffer.String()))
+	}
+	if errorOutputBuffer.String() != "" {
+		t.Error(testCaseUnexpectedMessage("error output", "", errorOutputBuffer.String()))
+	}
+	if exitCode != 0 {
+		t.Error(testCaseUnexpectedMessage("exit code", 0, exitCode))
+	}
+}
+
+func TestOutputDir(t *testing.T) {
+	outputDir := t.TempDir()
+	moduleDir := filepath.Join(outputDir, "a1")
+	t.Setenv("GMC_CONFIG", filepath.Join(t.TempDir(), "config.json")) // Automatically reset
+	t.Setenv("EDITOR", editor)                                        // Automatically reset
+
+	var outputBuffer bytes.Buffer
+	var errorOutputBuffer bytes.Buffer
+	exitCode := 0
+	app := cli.App(cli.WithOutput(&outputBuffer), cli.WithErrorOutput(&errorOutputBuffer), cli.WithExitHandler(func(c int) { exitCode = c }))
+	_ = app.Run([]string{cli.Name, "-C", outputDir, "a1"})
+
+	expectedOutput := fmt.Sprintf("Creating Go module: a1\n"+
+		"- Created directory: %[1]s\n"+
+		"- Initialized Go module\n"+
+		"- Created file     : %[1]s/main.go\n"+
+		"- Created file     : %[1]s/main_test.go\n"+
+		"- Created file     : %[1]s/.gitignore\n"+
+		"- Created directory: %[1]s/.gmc\n"+
+		"- Created file     : %[1]s/.gmc/manifest.json\n"+
+		"\n"+
+		"Finished creating Go module: a1\n"+
+		"\n"+
+		"Next steps:\n"+
+		"- Change into module's directory: $ cd %[1]s\n"+
+		"- Run module: $ go run .\n"+
+		"- Start coding: $ %[2]s .\n",
+		moduleDir, editor)
+	if outputBuffer.String() != expectedOutput {
+		t.Error(testCaseUnexpectedMessage("output", expectedOutput, outputBuffer.String()))
+	}
+	if errorOutputBuffer.String() != "" {
+		t.Error(testCaseUnexpectedMessage("error output", "", errorOutputBuffer.String()))
+	}
+	if exitCode != 0 {
+		t.Error(testCaseUnexpectedMessage("exit code", 0, exitCode))
+	}
+	assertExpectedFileIsAtPath(t, file{"main.go", filePerms, []byte(mainGoContents), nil}, filepath.Join(moduleDir, "main.go"))
+	assertExpectedFileIsAtPath(t, file{"main_test.go", filePerms, []byte(mainTestGoContents), nil}, filepath.Join(moduleDir, "main_test.go"))
+}
+
+func TestFullPath(t *testing.T) {
+	outputDir := t.TempDir()
+	moduleDir := filepath.Join(outputDir, "example.com", "owner", "a1")
+	t.Setenv("GMC_CONFIG", filepath.Join(t.TempDir(), "config.json")) // Automatically reset
+	t.Setenv("EDITOR", editor)                                        // Automatically reset
+
+	var outputBuffer bytes.Buffer
+	var errorOutputBuffer bytes.Buffer
+	exitCode := 0
+	app := cli.App(cli.WithOutput(&outputBuffer), cli.WithErrorOutput(&errorOutputBuffer), cli.WithExitHandler(func(c int) { exitCode = c }))
+	_ = app.Run([]string{cli.Name, "-C", outputDir, "--full-path", "example.com/owner/a1"})
+
+	expectedOutput := fmt.Sprintf("Creating Go module: example.com/owner/a1\n"+
+		"- Created directory: %[1]s/example.com\n"+
+		"- Created directory: %[1]s/example.com/owner\n"+
+		"- Created directory: %[2]s\n"+
+		"- Initialized Go module\n"+
+		"- Created file     : %[2]s/main.go\n"+
+		"- Created file     : %[2]s/main_test.go\n"+
+		"- Created file     : %[2]s/.gitignore\n"+
+		"- Created directory: %[2]s/.gmc\n"+
+		"- Created file     : %[2]s/.gmc/manifest.json\n"+
+		"\n"+
+		"Finished creating Go module: example.com/owner/a1\n"+
+		"\n"+
+		"Next steps:\n"+
+		"- Change into module's directory: $ cd %[2]s\n"+
+		"- Run module: $ go run .\n"+
+		"- Start coding: $ %[3]s .\n",
+		outputDir, moduleDir, editor)
+	if outputBuffer.String() != expectedOutput {
+		t.Error(testCaseUnexpectedMessage("output", expectedOutput, outputBuffer.String()))
+	}
+	if errorOutputBuffer.String() != "" {
+		t.Error(testCaseUnexpectedMessage("error output", "", errorOutputBuffer.String()))
+	}
+	if exitCode != 0 {
+		t.Error(testCaseUnexpectedMessage("exit code", 0, exitCode))
+	}
+	assertExpectedFileIsAtPath(t, file{"main.go", filePerms, []byte(mainGoContents), nil}, filepath.Join(moduleDir, "main.go"))
+	assertExpectedFileIsAtPath(t, file{"main_test.go", filePerms, []byte(mainTestGoContents), nil}, filepath.Join(moduleDir, "main_test.go"))
+}
+
+func TestTemplatesCommand(t *testing.T) {
+	var outputBuffer bytes.Buffer
+	exitCode := 0
+	app := cli.App(cli.WithOutput(&outputBuffer), cli.WithExitHandler(func(c int) { exitCode = c }))
+	_ = app.Run([]string{cli.Name, "templates"})
+
+	lines := strings.Split(outputBuffer.String(), "\n")
+	if expected := "NAME  "; !strings.HasPrefix(lines[0], expected) {
+		t.Error(testCaseUnexpectedMessage("header", expected, lines[0]))
+	}
+	expected := regexp.MustCompile(`(?m)^ci-github +embedded +GitHub Actions CI workflow \(--ci github\)$`)
+	if !expected.MatchString(outputBuffer.String()) {
+		t.Error(testCaseUnexpectedMessage("output of templates", expected.String(), outputBuffer.String()))
+	}
+	if exitCode != 0 {
+		t.Error(testCaseUnexpectedMessage("exit code", 0, exitCode))
+	}
+
+	outputBuffer.Reset()
+	_ = app.Run([]string{cli.Name, "templates", "--json"})
+	var templates []map[string]string
+	err := json.Unmarshal(outputBuffer.Bytes(), &templates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(templates) != len(lines)-2 {
+		t.Error(testCaseUnexpectedMessage("templates listed as JSON", len(lines)-2, len(templates)))
+	}
+}
+
+func TestAuditCommand(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"a/go.mod":             "module example.com/a\n\ngo 1.21\n",
+		"a/.gmc/manifest.json": `{"gmcVersion":"v1.0.0","module":"example.com/a","flags":["--lint"],"files":[]}`,
+		"b/go.mod":             "module example.com/b\n\ngo 1.21\n",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		if err == nil {
+			err = os.WriteFile(path, []byte(content), 0644)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var outputBuffer, errorOutputBuffer bytes.Buffer
+	exitCode := 0
+	app := cli.App(cli.WithOutput(&outputBuffer), cli.WithErrorOutput(&errorOutputBuffer), cli.WithExitHandler(func(c int) { exitCode = c }))
+	_ = app.Run([]string{cli.Name, "audit", "--require", "ci,lint", "--csv", dir})
+	expected := "dir,module,managed,gmcVersion,outdated,missingFeatures,modifiedFiles,error\n" +
+		filepath.Join(dir, "a") + ",example.com/a,true,v1.0.0,false,ci,,\n" +
+		filepath.Join(dir, "b") + ",example.com/b,false,,false,,,\n"
+	if outputBuffer.String() != expected {
+		t.Error(testCaseUnexpectedMessage("output of audit --csv", expected, outputBuffer.String()))
+	}
+	if exitCode != 0 {
+		t.Error(testCaseUnexpectedMessage("exit code", 0, exitCode))
+	}
+
+	outputBuffer.Reset()
+	_ = app.Run([]string{cli.Name, "audit", "--json", dir})
+	var audits []map[string]any
+	err := json.Unmarshal(outputBuffer.Bytes(), &audits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(audits) != 2 || audits[0]["module"] != "example.com/a" || audits[1]["managed"] != false {
+		t.Error(testCaseUnexpectedMessage("audit as JSON", "example.com/a and unmanaged example.com/b", outputBuffer.String()))
+	}
+
+	outputBuffer.Reset()
+	_ = app.Run([]string{cli.Name, "audit", dir})
+	table := regexp.MustCompile(`(?m)^.*a +example\.com/a +v1\.0\.0 +no +0$`)
+	if !table.MatchString(outputBuffer.String()) {
+		t.Error(testCaseUnexpectedMessage("output of audit", table.String(), outputBuffer.String()))
+	}
+
+	outputBuffer.Reset()
+	_ = app.Run([]string{cli.Name, "audit", "--json", "--csv", dir})
+	if expected := "Error: --json and --csv can't be used together\n"; !strings.HasPrefix(errorOutputBuffer.String(), expected) {
+		t.Error(testCaseUnexpectedMessage("error output", expected, errorOutputBuffer.String()))
+	}
+	if exitCode != 2 {
+		t.Error(testCaseUnexpectedMessage("exit code", 2, exitCode))
+	}
+}
+
+func TestReleasePrepCommand(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Chdir(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		err = os.Chdir(cwd)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Setenv("GMC_CONFIG", filepath.Join(t.TempDir(), "config.json")) // Automatically reset
+
+	var outputBuffer, errorOutputBuffer bytes.Buffer
+	exitCode := 0
+	app := cli.App(cli.WithOutput(&outputBuffer), cli.WithErrorOutput(&errorOutputBuffer), cli.WithExitHandler(func(c int) { exitCode = c }), cli.WithGitBranch(gitBranchName))
+	_ = app.Run([]string{cli.Name, "-q", "-g", "a1"})
+	if exitCode != 0 {
+		t.Fatal(testCaseUnexpectedMessage("exit code", 0, exitCode))
+	}
+	err = os.Chdir("a1")
+	if err == nil {
+		err = os.WriteFile("CHANGELOG.md", []byte("# Changelog\n\n## v0.1.0\n\n- First release\n"), 0644)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	output, err := exec.Command("git", "add", "CHANGELOG.md").CombinedOutput()
+	if err == nil {
+		output, err = exec.Command("git", "commit", "--quiet", "-m", "Add changelog").CombinedOutput()
+	}
+	if err != nil {
+		t.Fatalf("%s: %s", err, output)
+	}
+
+	_ = app.Run([]string{cli.Name, "release-prep", "--dry-run", "v0.1.0"})
+	expectedOutput := "Preparing release: a1 v0.1.0\n" +
+		"- OK  : Version\n" +
+		"- OK  : Changelog\n" +
+		"- OK  : go.mod tidy\n" +
+		"- OK  : Tests\n" +
+		"\n" +
+		"Ready to release v0.1.0 (dry run, not tagged)\n"
+	if outputBuffer.String() != expectedOutput {
+		t.Error(testCaseUnexpectedMessage("output", expectedOutput, outputBuffer.String()))
+	}
+	if exitCode != 0 {
+		t.Error(testCaseUnexpectedMessage("exit code", 0, exitCode))
+	}
+
+	outputBuffer.Reset()
+	_ = app.Run([]string{cli.Name, "release-prep", "v0.2.0"})
+	expectedOutput = "Preparing release: a1 v0.2.0\n" +
+		"- OK  : Version\n" +
+		"- FAIL: Changelog: No entry for v0.2.0 in CHANGELOG.md\n" +
+		"- OK  : go.mod tidy\n" +
+		"- OK  : Tests\n"
+	if outputBuffer.String() != expectedOutput {
+		t.Error(testCaseUnexpectedMessage("output", expectedOutput, outputBuffer.String()))
+	}
+	if expected := "Failed to prepare release: a1: Not ready to release v0.2.0 (failed: Changelog)\n"; errorOutputBuffer.String() != expected {
+		t.Error(testCaseUnexpectedMessage("error output", expected, errorOutputBuffer.String()))
+	}
+	if exitCode != 1 {
+		t.Error(testCaseUnexpectedMessage("exit code", 1, exitCode))
+	}
+
+	errorOutputBuffer.Reset()
+	_ = app.Run([]string{cli.Name, "release-prep"})
+	if expected := "Error: Exactly one version is required (e.g. v1.2.0)\n"; !strings.HasPrefix(errorOutputBuffer.String(), expected) {
+		t.Error(testCaseUnexpectedMessage("error output", expected, errorOutputBuffer.String()))
+	}
+	if exitCode != 2 {
+		t.Error(testCaseUnexpectedMessage("exit code", 2, exitCode))
+	}
+}
+
+func TestInfer(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		err = os.Chdir(cwd)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	// The GitHub CLI isn't signed in, so the login comes from Git config
+	binDir := t.TempDir()
+	err = os.WriteFile(filepath.Join(binDir, "gh"), []byte("#!/bin/sh\nexit 1\n"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH")) // Automatically reset
+	gitConfig := filepath.Join(t.TempDir(), "gitconfig")
+	err = os.WriteFile(gitConfig, []byte("[user]\n\tname = Test\n\temail = test@example.com\n[github]\n\tuser = octocat\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", gitConfig) // Automatically reset
+
+	tests := []struct {
+		name           string
+		remote         string
+		config         string
+		args           []string
+		expectedModule string
+	}{
+		{"no remote", "", "", []string{"--infer"}, "github.com/octocat/a1"},
+		{"non-GitHub remote", "git@gitlab.com:acme/workspace.git", "", []string{"--infer"}, "github.com/octocat/a1"},
+		{"config", "", `{"infer": true}`, nil, "github.com/octocat/a1"},
+		{"config overridden", "", `{"infer": true}`, []string{"--infer=false"}, "a1"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Run from a directory that is (or isn't) a Git repository with a remote
+			dir := t.TempDir()
+			err := os.Chdir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.remote != "" {
+				for _, args := range [][]string{{"init", "--quiet"}, {"remote", "add", "origin", tc.remote}} {
+					output, err := exec.Command("git", args...).CombinedOutput()
+					if err != nil {
+						t.Fatalf("%s: %s", err, output)
+					}
+				}
+			}
+			configPath := filepath.Join(t.TempDir(), "config.json")
+			if tc.config != "" {
+				err = os.WriteFile(configPath, []byte(tc.config), 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			t.Setenv("GMC_CONFIG", configPath) // Automatically reset
+
+			var outputBuffer, errorOutputBuffer bytes.Buffer
+			exitCode := 0
+			app := cli.App(cli.WithOutput(&outputBuffer), cli.WithErrorOutput(&errorOutputBuffer), cli.WithExitHandler(func(c int) { exitCode = c }))
+			_ = app.Run(append(append([]string{cli.Name, "--dry-run"}, tc.args...), "a1"))
+
+			expected := fmt.Sprintf("Creating Go module (dry run): %s\n", tc.expectedModule)
+			if !strings.HasPrefix(outputBuffer.String(), expected) {
+				t.Error(testCaseUnexpectedMessage("output", expected, outputBuffer.String()))
+			}
+			if errorOutputBuffer.String() != "" {
+				t.Error(testCaseUnexpectedMessage("error output", "", errorOutputBuffer.String()))
+			}
+			if exitCode != 0 {
+				t.Error(testCaseUnexpectedMessage("exit code", 0, exitCode))
+			}
+		})
+	}
+}
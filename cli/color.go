@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jbrudvik/gmc/create"
+	"github.com/urfave/cli/v2"
+)
+
+var colorModes = []string{"auto", "always", "never"}
+
+// colorEnabled reports whether to color output, as --color sets. By default, output shown on a terminal is colored,
+// unless $NO_COLOR is set (https://no-color.org).
+func colorEnabled(c *cli.Context, terminal bool) (bool, error) {
+	switch strings.ToLower(c.String("color")) {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto", "":
+		return terminal && os.Getenv("NO_COLOR") == "" && os.Getenv("TERM") != "dumb", nil
+	}
+	return false, errors.New(fmt.Sprintf("Error: Unsupported color mode: %s (supported: %s)", c.String("color"), strings.Join(colorModes, ", ")))
+}
+
+// colorOutput returns where to report progress: output (shown on a terminal, or not), wrapped to be written in color if
+// that's enabled
+func colorOutput(c *cli.Context, output io.Writer, terminal bool) (io.Writer, error) {
+	color, err := colorEnabled(c, terminal)
+	if err != nil {
+		c.Set("help", "true")
+		return nil, err
+	}
+	if color {
+		return create.ColorWriter{Writer: output}, nil
+	}
+	return output, nil
+}
+
+// errorText returns the message of err, in red if error output is colored
+func errorText(c *cli.Context, o *appOptions, err error) string {
+	if color, _ := colorEnabled(c, o.errorOutputIsTerminal()); color {
+		return "\x1b[31m" + err.Error() + "\x1b[0m"
+	}
+	return err.Error()
+}
@@ -0,0 +1,280 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jbrudvik/gmc/create"
+	"github.com/urfave/cli/v2"
+)
+
+// Name of the hidden command that completion scripts call to complete a command line
+const completeCommandName string = "__complete"
+
+// Completion scripts by shell, which complete gmc's command lines by calling gmc __complete with the words typed so far
+// (the last being the word at the cursor)
+var completionScripts = map[string]string{
+	"bash": `# bash completion for gmc. Load it with: source <(gmc completion bash)
+_gmc() {
+    local line="${COMP_LINE:0:COMP_POINT}"
+    local -a words
+    read -ra words <<< "$line"
+    if [[ -z "$line" || "$line" == *[[:space:]] ]]; then
+        words+=("")
+    fi
+    local cur="${words[${#words[@]}-1]}"
+    local IFS=$'\n'
+    COMPREPLY=($(gmc __complete "${words[@]:1}"))
+    # Bash replaces only what follows "=" in a --flag=value word
+    if [[ "$cur" == *=* && "$COMP_WORDBREAKS" == *=* ]]; then
+        COMPREPLY=("${COMPREPLY[@]#*=}")
+    fi
+}
+complete -o default -F _gmc gmc
+`,
+	"zsh": `#compdef gmc
+# zsh completion for gmc. Load it with: source <(gmc completion zsh)
+_gmc() {
+    local -a candidates
+    candidates=("${(@f)$(gmc __complete "${(@)words[2,CURRENT]}")}")
+    if [[ -n "${candidates[1]}" ]]; then
+        compadd -- "${candidates[@]}"
+    else
+        _files
+    fi
+}
+compdef _gmc gmc
+`,
+	"fish": `# fish completion for gmc. Load it with: gmc completion fish | source
+function __gmc_complete
+    set -l words (commandline -opc)
+    set -e words[1]
+    gmc __complete $words (commandline -ct)
+end
+complete -c gmc -a '(__gmc_complete)'
+`,
+	"powershell": `# PowerShell completion for gmc. Load it with: gmc completion powershell | Out-String | Invoke-Expression
+Register-ArgumentCompleter -Native -CommandName gmc -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+    $words = @($commandAst.CommandElements | Select-Object -Skip 1 | Where-Object { $_.Extent.EndOffset -le $cursorPosition } | ForEach-Object { $_.ToString() })
+    if ($wordToComplete -eq '') {
+        # An empty argument is dropped when passed to a native command, so it's passed quoted
+        $words += '""'
+    }
+    gmc __complete @words | ForEach-Object {
+        [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)
+    }
+}
+`,
+}
+
+// Shells that completion scripts are printed for, in the order they're listed
+var completionShells = []string{"bash", "zsh", "fish", "powershell"}
+
+// Values that flags can be completed with, by flag name
+var flagValueCompletions = map[string]func() []string{
+	"ci":            create.CIProviders,
+	"fmt-check":     create.Formatters,
+	"license":       create.Licenses,
+	"cloud-dev":     create.CloudDevEnvironments,
+	"feature-flags": create.FeatureFlagsSDKs,
+	"skip":          create.Stages,
+	"only":          create.Stages,
+	"color":         func() []string { return colorModes },
+}
+
+func completionCommand(output io.Writer) *cli.Command {
+	return &cli.Command{
+		Name:      "completion",
+		Usage:     "print a shell completion script: " + strings.Join(completionShells, ", "),
+		ArgsUsage: "[shell]",
+		Description: "Completes commands, flags, and their values (e.g. CI providers, licenses, and features to add).\n" +
+			"\n" +
+			"    $ source <(" + Name + " completion bash)   # in ~/.bashrc\n" +
+			"    $ source <(" + Name + " completion zsh)    # in ~/.zshrc, after compinit\n" +
+			"    $ " + Name + " completion fish | source    # in ~/.config/fish/config.fish\n" +
+			"    $ " + Name + " completion powershell | Out-String | Invoke-Expression   # in $PROFILE",
+		OnUsageError: onCommandUsageError,
+		Action: func(c *cli.Context) error {
+			args := c.Args()
+			if args.Len() != 1 {
+				c.Set("help", "true")
+				return errors.New(fmt.Sprintf("Error: One shell is required (supported: %s)", strings.Join(completionShells, ", ")))
+			}
+			script, ok := completionScripts[strings.ToLower(args.First())]
+			if !ok {
+				c.Set("help", "true")
+				return errors.New(fmt.Sprintf("Error: Unsupported shell: %s (supported: %s)", args.First(), strings.Join(completionShells, ", ")))
+			}
+			_, err := io.WriteString(output, script)
+			return err
+		},
+	}
+}
+
+// completeCommand returns the hidden command that completion scripts call, which prints a completion of each word typed
+// so far (the last being the word at the cursor) per line
+func completeCommand(output io.Writer) *cli.Command {
+	return &cli.Command{
+		Name:            completeCommandName,
+		Hidden:          true,
+		SkipFlagParsing: true,
+		Action: func(c *cli.Context) error {
+			for _, completion := range completeWords(c.App, c.Args().Slice()) {
+				fmt.Fprintln(output, completion)
+			}
+			return nil
+		},
+	}
+}
+
+// completeWords returns the completions of the last of words, which follow gmc on a command line
+func completeWords(app *cli.App, words []string) []string {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	current := words[len(words)-1]
+	if current == `""` {
+		// Passed quoted by PowerShell
+		current = ""
+	}
+
+	// Find the command (if any), the flag whose value is being completed (if any), and the arguments before the cursor
+	var command *cli.Command
+	flags := app.Flags
+	var valueFlag cli.Flag
+	args := []string{}
+	for _, word := range words[:len(words)-1] {
+		if valueFlag != nil {
+			valueFlag = nil
+			continue
+		}
+		if strings.HasPrefix(word, "-") {
+			flag := findFlag(flags, word)
+			if flag != nil && takesValue(flag) && !strings.Contains(word, "=") {
+				valueFlag = flag
+			}
+			continue
+		}
+		if command == nil && len(args) == 0 {
+			if cmd := findCommand(app, word); cmd != nil {
+				command = cmd
+				flags = cmd.Flags
+				continue
+			}
+		}
+		args = append(args, word)
+	}
+
+	// The value of a flag, given after it or after "="
+	if valueFlag != nil {
+		return withPrefix(flagValues(valueFlag), "", current)
+	}
+	if strings.HasPrefix(current, "-") {
+		if i := strings.Index(current, "="); i >= 0 {
+			if flag := findFlag(flags, current[:i]); flag != nil {
+				return withPrefix(flagValues(flag), current[:i+1], current)
+			}
+			return nil
+		}
+		names := []string{}
+		for _, flag := range flags {
+			for _, name := range flag.Names() {
+				if len(name) == 1 {
+					names = append(names, "-"+name)
+				} else {
+					names = append(names, "--"+name)
+				}
+			}
+		}
+		return withPrefix(names, "", current)
+	}
+
+	// Arguments, as each command takes them
+	if command == nil {
+		if len(args) > 0 {
+			return nil
+		}
+		names := []string{}
+		for _, cmd := range app.VisibleCommands() {
+			names = append(names, cmd.Name)
+		}
+		return withPrefix(names, "", current)
+	}
+	switch command.Name {
+	case "add":
+		if len(args) == 0 {
+			return withPrefix(create.AddFeatures(), "", current)
+		} else if len(args) == 1 {
+			switch strings.ToLower(args[0]) {
+			case "license":
+				return withPrefix(create.Licenses(), "", current)
+			case "ci":
+				return withPrefix(create.CIProviders(), "", current)
+			}
+		}
+	case "help":
+		if len(args) == 0 {
+			return withPrefix(helpTopicNames(), "", current)
+		}
+	case "completion":
+		if len(args) == 0 {
+			return withPrefix(completionShells, "", current)
+		}
+	}
+	return nil
+}
+
+// findFlag returns the flag named by arg (e.g. "--ci", "-C", or "--ci=github"), if it's one of flags
+func findFlag(flags []cli.Flag, arg string) cli.Flag {
+	name := strings.TrimLeft(arg, "-")
+	if i := strings.Index(name, "="); i >= 0 {
+		name = name[:i]
+	}
+	for _, flag := range flags {
+		for _, flagName := range flag.Names() {
+			if flagName == name {
+				return flag
+			}
+		}
+	}
+	return nil
+}
+
+func findCommand(app *cli.App, name string) *cli.Command {
+	for _, cmd := range app.VisibleCommands() {
+		if cmd.HasName(name) {
+			return cmd
+		}
+	}
+	return nil
+}
+
+// takesValue reports whether a flag is followed by a value, as flags other than booleans are
+func takesValue(flag cli.Flag) bool {
+	_, isBool := flag.(*cli.BoolFlag)
+	return !isBool
+}
+
+// flagValues returns the values a flag can be completed with. Without any, the shell completes file names.
+func flagValues(flag cli.Flag) []string {
+	for _, name := range flag.Names() {
+		if values, ok := flagValueCompletions[name]; ok {
+			return values()
+		}
+	}
+	return nil
+}
+
+// withPrefix returns the candidates that complete current, each after prefix
+func withPrefix(candidates []string, prefix string, current string) []string {
+	completions := []string{}
+	for _, candidate := range candidates {
+		if strings.HasPrefix(prefix+candidate, current) {
+			completions = append(completions, prefix+candidate)
+		}
+	}
+	return completions
+}
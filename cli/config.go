@@ -0,0 +1,184 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jbrudvik/gmc/create"
+	"github.com/urfave/cli/v2"
+)
+
+const configFileName string = "config.json"
+
+// Environment variable that overrides the location of the config file
+const configEnvVar string = "GMC_CONFIG"
+
+// A config holds a user's (or organization's) defaults for creating modules
+type config struct {
+	// Prefix for module names without a slash (e.g. "github.com/jbrudvik" turns "foo" into "github.com/jbrudvik/foo")
+	ModulePrefix string `json:"modulePrefix"`
+
+	// Always infer the module prefix from the user's GitHub login, as with --infer
+	Infer bool `json:"infer"`
+
+	// Always create a Git repository, as with --git
+	Git bool `json:"git"`
+
+	// Name to attribute licenses to when the Git user.name isn't set
+	Author string `json:"author,omitempty"`
+
+	// Always add a Makefile, as with --make
+	Make bool `json:"make"`
+
+	// Always add a Taskfile.yml, as with --taskfile
+	Taskfile bool `json:"taskfile"`
+
+	// Editor extra added to every module (vscode, goland, or nvim), as with its flag
+	Editor string `json:"editor,omitempty"`
+
+	// Go toolchain pinned in every module (e.g. go1.22.3), as with --toolchain
+	Toolchain string `json:"toolchain,omitempty"`
+
+	// Private module proxy that every module resolves dependencies through exclusively, as with --private-proxy
+	PrivateProxy string `json:"privateProxy,omitempty"`
+
+	// Protocol of every Git remote's URL (ssh or https), as with --remote-protocol
+	RemoteProtocol string `json:"remoteProtocol,omitempty"`
+
+	// File that a timestamped log of every run is appended to, as with --log-file
+	LogFile string `json:"logFile,omitempty"`
+
+	Lint lintConfig `json:"lint"`
+}
+
+type lintConfig struct {
+	// Linters enabled in the generated .golangci.yml
+	Linters []string `json:"linters"`
+}
+
+func defaultConfig() *config {
+	return &config{
+		// Copied, so that decoding a config file can't overwrite the defaults
+		Lint: lintConfig{Linters: create.DefaultLinters()},
+	}
+}
+
+// configPath returns where the config file is read from: $GMC_CONFIG, or gmc/config.json in the user's config directory
+func configPath() (string, error) {
+	if path := os.Getenv(configEnvVar); path != "" {
+		return path, nil
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, Name, configFileName), nil
+}
+
+// writeConfig writes cfg to the config file, creating its directory if needed
+func writeConfig(path string, cfg *config) error {
+	content, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(content, '\n'), 0644)
+}
+
+// loadConfig reads the config file, falling back to defaults for anything it doesn't set
+func loadConfig() (*config, error) {
+	cfg := defaultConfig()
+	path, err := configPath()
+	if err != nil {
+		// Without a config directory, there can be no config file
+		return cfg, nil
+	}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	} else if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(content, cfg)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("Invalid config file: %s", err))
+	}
+	if cfg.Editor != "" && !isEditor(cfg.Editor) {
+		return nil, errors.New(fmt.Sprintf("Invalid config file: unsupported editor: %s (supported: %s)", cfg.Editor, strings.Join(editorNames(), ", ")))
+	}
+	if cfg.RemoteProtocol != "" && !isRemoteProtocol(cfg.RemoteProtocol) {
+		return nil, errors.New(fmt.Sprintf("Invalid config file: unsupported remote protocol: %s (supported: %s)", cfg.RemoteProtocol, strings.Join(create.RemoteProtocols(), ", ")))
+	}
+	if len(cfg.Lint.Linters) == 0 {
+		cfg.Lint.Linters = create.DefaultLinters()
+	}
+	return cfg, nil
+}
+
+// editorNames returns the names of the editors that can be configured
+func editorNames() []string {
+	names := []string{}
+	for _, editor := range create.Editors() {
+		names = append(names, editor.Name)
+	}
+	return names
+}
+
+func isEditor(name string) bool {
+	for _, editorName := range editorNames() {
+		if editorName == name {
+			return true
+		}
+	}
+	return false
+}
+
+func isRemoteProtocol(name string) bool {
+	for _, protocol := range create.RemoteProtocols() {
+		if protocol == name {
+			return true
+		}
+	}
+	return false
+}
+
+func configCommand(output io.Writer) *cli.Command {
+	return &cli.Command{
+		Name:         "config",
+		Usage:        "print where the config file is read from, and the settings in effect",
+		OnUsageError: onCommandUsageError,
+		Action: func(c *cli.Context) error {
+			if c.Args().Present() {
+				c.Set("help", "true")
+				return errors.New("Error: No arguments are allowed")
+			}
+			path, err := configPath()
+			if err != nil {
+				return errors.New(fmt.Sprintf("Failed to find config file: %s", err))
+			}
+			cfg, err := loadConfig()
+			if err != nil {
+				return errors.New(fmt.Sprintf("Failed to load config: %s: %s", path, err))
+			}
+			content, err := json.MarshalIndent(cfg, "", "  ")
+			if err != nil {
+				return err
+			}
+
+			if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+				path += " (not found, using defaults)"
+			}
+			flogf(output, false, "Config file: %s\n\n%s\n", path, content)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// A dashboard shows the progress of creating several modules on a terminal: a line per module, redrawn in place as
+// each step is reported, instead of the output of every module interleaved. A summary table follows once all are done.
+type dashboard struct {
+	output io.Writer
+	rows   []*dashboardRow
+
+	// Lines drawn last, which are drawn over next
+	drawn int
+}
+
+type dashboardRow struct {
+	d      *dashboard
+	module string
+	status string
+	step   string
+	steps  int
+	err    error
+
+	// Set once creation finished, after which next steps are reported, but not shown
+	finished bool
+}
+
+const (
+	dashboardWaiting  string = "waiting"
+	dashboardCreating string = "creating"
+	dashboardCreated  string = "created"
+	dashboardFailed   string = "failed"
+)
+
+func newDashboard(output io.Writer, modules []string) *dashboard {
+	d := &dashboard{output: output}
+	for _, module := range modules {
+		d.rows = append(d.rows, &dashboardRow{d: d, module: module, status: dashboardWaiting})
+	}
+	d.draw()
+	return d
+}
+
+// start marks the module of row i as being created, returning where its progress is reported
+func (d *dashboard) start(i int) io.Writer {
+	d.rows[i].status = dashboardCreating
+	d.draw()
+	return d.rows[i]
+}
+
+// finish marks the module of row i as created, or failed with err
+func (d *dashboard) finish(i int, err error) {
+	row := d.rows[i]
+	row.status = dashboardCreated
+	if err != nil {
+		row.status = dashboardFailed
+		row.err = err
+	}
+	d.draw()
+}
+
+// Write records each step reported in p (e.g. "- Created file     : a1/main.go") as the row's latest
+func (row *dashboardRow) Write(p []byte) (int, error) {
+	steps := row.steps
+	for _, line := range strings.Split(string(p), "\n") {
+		if strings.HasPrefix(line, "Finished") {
+			row.finished = true
+		} else if !row.finished && strings.HasPrefix(line, "- ") {
+			row.step = strings.TrimPrefix(line, "- ")
+			row.steps++
+		}
+	}
+	if row.steps != steps {
+		row.d.draw()
+	}
+	return len(p), nil
+}
+
+func (d *dashboard) draw() {
+	if d.drawn > 0 {
+		fmt.Fprintf(d.output, "\x1b[%dA", d.drawn) // Move up to the first line drawn
+	}
+	width := 0
+	for _, row := range d.rows {
+		if len(row.module) > width {
+			width = len(row.module)
+		}
+	}
+	for _, row := range d.rows {
+		step := row.step
+		if row.status == dashboardFailed {
+			step = row.err.Error()
+		}
+		line := fmt.Sprintf("%-*s  %-8s  %s", width, row.module, row.status, step)
+		fmt.Fprintf(d.output, "\x1b[2K%s\n", strings.TrimRight(line, " ")) // Cleared first, in case it was longer
+	}
+	d.drawn = len(d.rows)
+}
+
+// summarize writes a table of how creating each module went
+func (d *dashboard) summarize() {
+	fmt.Fprintln(d.output)
+	w := tabwriter.NewWriter(d.output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "MODULE\tSTATUS\tSTEPS\tERROR")
+	for _, row := range d.rows {
+		errorMessage := ""
+		if row.err != nil {
+			errorMessage = row.err.Error()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", row.module, row.status, row.steps, errorMessage)
+	}
+	w.Flush()
+	fmt.Fprintln(d.output)
+}
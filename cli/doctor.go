@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/jbrudvik/gmc/create"
+	"github.com/urfave/cli/v2"
+)
+
+// A doctorCheck looks at one thing gmc relies on, returning what it found
+type doctorCheck struct {
+	name string
+	run  func() (string, error)
+	// What the check is needed for, if it's optional
+	neededFor string
+}
+
+func doctorCommand(output io.Writer) *cli.Command {
+	return &cli.Command{
+		Name:  "doctor",
+		Usage: "check that the tools and settings gmc uses are installed and configured",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "git-exec",
+				Usage: "check the git executable, instead of the built-in Git implementation",
+			},
+		},
+		OnUsageError: onCommandUsageError,
+		Action: func(c *cli.Context) error {
+			if c.Args().Present() {
+				c.Set("help", "true")
+				return errors.New("Error: No arguments are allowed")
+			}
+
+			gitExec := c.Bool("git-exec")
+			checks := []doctorCheck{
+				{name: "Go", run: func() (string, error) { return goToolchain(c.Context) }},
+				{name: "Config", run: func() (string, error) {
+					path, err := configPath()
+					if err != nil {
+						return "", err
+					}
+					_, err = loadConfig()
+					return path, err
+				}},
+				{name: "Git user.name", run: gitConfigCheck(c.Context, gitExec, "user.name"), neededFor: "--git and --license"},
+				{name: "Git user.email", run: gitConfigCheck(c.Context, gitExec, "user.email"), neededFor: "--git"},
+				{name: "GitHub login", run: func() (string, error) { return create.GitHubLogin(c.Context, gitExec) }, neededFor: "--infer"},
+			}
+			if gitExec {
+				checks = append(checks, doctorCheck{name: "Git", run: func() (string, error) { return gitExecutableVersion(c.Context) }})
+			}
+
+			problems := 0
+			for _, check := range checks {
+				found, err := check.run()
+				if err == nil {
+					flogf(output, false, "- OK  : %s: %s\n", check.name, found)
+				} else if check.neededFor != "" {
+					flogf(output, false, "- WARN: %s: %s (needed for %s)\n", check.name, err, check.neededFor)
+				} else {
+					flogf(output, false, "- FAIL: %s: %s\n", check.name, err)
+					problems++
+				}
+			}
+
+			if problems > 0 {
+				return errors.New(fmt.Sprintf("\nFound %d problem(s)", problems))
+			}
+			flogln(output, false, "\nNo problems found")
+			return nil
+		},
+	}
+}
+
+func goToolchain(ctx context.Context) (string, error) {
+	cmdOutput, err := exec.CommandContext(ctx, "go", "env", "GOVERSION").Output()
+	if err != nil {
+		return "", errors.New("Unable to run go (install it from https://go.dev/dl/)")
+	}
+	return strings.TrimSpace(string(cmdOutput)), nil
+}
+
+func gitExecutableVersion(ctx context.Context) (string, error) {
+	cmdOutput, err := exec.CommandContext(ctx, "git", "--version").Output()
+	if err != nil {
+		return "", errors.New("Unable to run git")
+	}
+	return strings.TrimSpace(string(cmdOutput)), nil
+}
+
+func gitConfigCheck(ctx context.Context, gitExec bool, key string) func() (string, error) {
+	return func() (string, error) {
+		value, err := create.GitGlobalConfig(ctx, gitExec, key)
+		if err != nil {
+			return "", errors.New(fmt.Sprintf("Failed to look up Git %s", key))
+		}
+		if value == "" {
+			return "", errors.New(fmt.Sprintf("`git config --global %s` is not set", key))
+		}
+		return value, nil
+	}
+}
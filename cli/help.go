@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+//go:embed help
+var helpTopics embed.FS
+
+const helpTopicsDir string = "help"
+const helpTopicFileExtension string = ".md"
+
+// Pager used when $PAGER isn't set
+const defaultPager string = "less"
+
+func helpTopicNames() []string {
+	names := []string{}
+	entries, _ := fs.ReadDir(helpTopics, helpTopicsDir)
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), helpTopicFileExtension))
+	}
+	return names
+}
+
+func helpCommand(output io.Writer) *cli.Command {
+	return &cli.Command{
+		Name:         "help",
+		Usage:        "show help, or a help topic: " + strings.Join(helpTopicNames(), ", "),
+		ArgsUsage:    "[topic]",
+		OnUsageError: onCommandUsageError,
+		Action: func(c *cli.Context) error {
+			args := c.Args()
+			if args.Len() < 1 {
+				return cli.ShowAppHelp(c)
+			} else if args.Len() > 1 {
+				c.Set("help", "true")
+				return errors.New("Error: Only one help topic is allowed")
+			}
+			topic := strings.ToLower(args.First())
+			content, err := helpTopics.ReadFile(path.Join(helpTopicsDir, topic+helpTopicFileExtension))
+			if err != nil {
+				c.Set("help", "true")
+				return errors.New(fmt.Sprintf("Error: Unknown help topic: %s (topics: %s)", topic, strings.Join(helpTopicNames(), ", ")))
+			}
+			return page(c.Context, output, renderHelpTopic(content))
+		},
+	}
+}
+
+// renderHelpTopic turns a Markdown help topic into plain text: code blocks are indented, instead of fenced
+func renderHelpTopic(content []byte) string {
+	var b strings.Builder
+	inCode := false
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.HasPrefix(line, "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode && line != "" {
+			b.WriteString("    ")
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// page writes text through $PAGER when output is a terminal, and directly otherwise
+func page(ctx context.Context, output io.Writer, text string) error {
+	if f, ok := output.(*os.File); ok && isTerminal(f) {
+		pager := os.Getenv("PAGER")
+		if pager == "" {
+			pager = defaultPager
+		}
+		cmd := exec.CommandContext(ctx, "sh", "-c", pager)
+		cmd.Stdin = bytes.NewBufferString(text)
+		cmd.Stdout = f
+		cmd.Stderr = os.Stderr
+		if cmd.Run() == nil {
+			return nil
+		}
+		// Without a working pager, fall back to writing directly
+	}
+	_, err := io.WriteString(output, text)
+	return err
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
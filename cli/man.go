@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"errors"
+	"io"
+
+	"github.com/urfave/cli/v2"
+)
+
+// Section of the manual that gmc's man page is in: user commands
+const manSection int = 1
+
+func manCommand(output io.Writer) *cli.Command {
+	return &cli.Command{
+		Name:  "man",
+		Usage: "print the man page, generated from this help",
+		Description: "Prints the man page in roff, for man to show.\n" +
+			"\n" +
+			"    $ " + Name + " man > /usr/local/share/man/man1/" + Name + ".1\n" +
+			"    $ man " + Name,
+		OnUsageError: onCommandUsageError,
+		Action: func(c *cli.Context) error {
+			if c.Args().Present() {
+				c.Set("help", "true")
+				return errors.New("Error: No arguments are allowed")
+			}
+			page, err := c.App.ToManWithSection(manSection)
+			if err != nil {
+				return err
+			}
+			_, err = io.WriteString(output, page)
+			return err
+		},
+	}
+}
@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/jbrudvik/gmc/create"
+	"github.com/urfave/cli/v2"
+)
+
+// shouldOnboard reports whether to offer onboarding: only on first run (no config file yet), only
+// when someone is at the terminal to answer, and not on a dry run, which writes nothing (the config file included)
+func shouldOnboard(c *cli.Context, o *appOptions) bool {
+	if !canAsk(c, o) || c.Bool("dry-run") {
+		return false
+	}
+	configFile, err := configPath()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(configFile)
+	return errors.Is(err, fs.ErrNotExist)
+}
+
+// onboard asks for the defaults most worth setting, and writes them to a new config file.
+// Declining writes the defaults as they are, so that onboarding is only offered once.
+func onboard(ctx context.Context, input io.Reader, output io.Writer, gitExec bool) error {
+	configFile, err := configPath()
+	if err != nil {
+		return err
+	}
+	cfg := defaultConfig()
+	in := bufio.NewReader(input)
+
+	flogf(output, false, "Welcome to %s! No config file was found at %s\n", Name, configFile)
+	if strings.ToLower(ask(in, output, "Set up defaults for new modules now?", "Y/n")) != "n" {
+		// Git identity
+		name, _ := create.GitGlobalConfig(ctx, gitExec, "user.name")
+		email, _ := create.GitGlobalConfig(ctx, gitExec, "user.email")
+		if name != "" && email != "" {
+			flogf(output, false, "- Git identity: %s <%s>\n", name, email)
+		} else {
+			flogln(output, false, "- Git identity: not set (Git repositories need `git config --global user.name` and `user.email`)")
+		}
+
+		// Module prefix
+		suggestedPrefix := ""
+		if login, err := create.GitHubLogin(ctx, gitExec); err == nil {
+			suggestedPrefix = path.Join("github.com", login)
+		}
+		cfg.ModulePrefix = ask(in, output, "Module prefix for names without a slash (e.g. github.com/<login>, or none)", suggestedPrefix)
+		if cfg.ModulePrefix == "none" {
+			cfg.ModulePrefix = ""
+		}
+
+		// Editor
+		for {
+			editor := strings.ToLower(ask(in, output, "Editor to configure in new modules ("+strings.Join(editorNames(), ", ")+", or none)", "none"))
+			if isEditor(editor) {
+				cfg.Editor = editor
+				break
+			} else if editor == "none" {
+				break
+			}
+			flogf(output, false, "- Unsupported editor: %s\n", editor)
+		}
+
+		// Git
+		cfg.Git = strings.ToLower(ask(in, output, "Create new modules as Git repositories?", "y/N")) == "y"
+	}
+
+	err = writeConfig(configFile, cfg)
+	if err != nil {
+		return errors.New(fmt.Sprintf("Failed to write config file: %s: %s", configFile, err))
+	}
+	flogf(output, false, "Wrote config file: %s (see `%s help config` to change it)\n\n", configFile, Name)
+	return nil
+}
+
+// canAsk reports whether someone is at the terminal to answer prompts, and output isn't meant for a script
+func canAsk(c *cli.Context, o *appOptions) bool {
+	if isQuiet(c) || c.Bool("json") || c.String("batch") == "-" || c.String("archive") == "-" {
+		return false
+	}
+	input, ok := c.App.Reader.(*os.File)
+	if !ok || !isTerminal(input) {
+		return false
+	}
+	return o.outputIsTerminal()
+}
+
+// ask prompts for an answer, returning defaultAnswer if the answer is empty or can't be read. For yes/no
+// prompts (e.g. "Y/n"), the default is the capitalized choice.
+func ask(in *bufio.Reader, output io.Writer, prompt string, defaultAnswer string) string {
+	if defaultAnswer == "" {
+		flogf(output, false, "%s: ", prompt)
+	} else {
+		flogf(output, false, "%s [%s]: ", prompt, defaultAnswer)
+	}
+	answer, _ := in.ReadString('\n')
+	answer = strings.TrimSpace(answer)
+	if answer != "" {
+		return answer
+	}
+	switch defaultAnswer {
+	case "Y/n":
+		return "y"
+	case "y/N":
+		return "n"
+	}
+	return defaultAnswer
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jbrudvik/gmc/create"
+	"github.com/urfave/cli/v2"
+)
+
+func planCommand(o *appOptions) *cli.Command {
+	return &cli.Command{
+		Name:      "plan",
+		Usage:     "print what creating a Go module would do as JSON, to review, and then carry out with `" + Name + " apply`",
+		ArgsUsage: "[module name]",
+		Flags: concatFlags(moduleFlags(), []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "init",
+				Usage: "plan to create the module in the current directory, as `" + Name + " init` does",
+			},
+		}),
+		OnUsageError: onCommandUsageError,
+		Action: func(c *cli.Context) error {
+			if c.Args().Len() != 1 {
+				c.Set("help", "true")
+				return errors.New("Error: One module name is required")
+			}
+			module := c.Args().First()
+			err := create.CheckModulePath(module)
+			if err != nil {
+				c.Set("help", "true")
+				return err
+			}
+
+			// The plan is written to output, so any download is offered on error output
+			planOptions := *o
+			planOptions.goToolchain, err = provisionGo(c, o, c.App.ErrWriter)
+			if err != nil {
+				return err
+			}
+			opts, err := moduleOptions(c.Context, c, &planOptions, module, c.Bool("init"))
+			if err != nil {
+				return err
+			}
+			content, err := create.Plan(c.Context, opts)
+			var usage create.UsageError
+			if errors.As(err, &usage) {
+				c.Set("help", "true")
+				return err
+			} else if err != nil {
+				return err
+			}
+			flogf(o.output, false, "%s\n", content)
+			return nil
+		},
+	}
+}
+
+func applyCommand(o *appOptions) *cli.Command {
+	return &cli.Command{
+		Name:         "apply",
+		Usage:        "carry out a plan saved from `" + Name + " plan` (- for standard input)",
+		ArgsUsage:    "[plan file]",
+		Flags:        outputFlags(),
+		OnUsageError: onCommandUsageError,
+		Action: func(c *cli.Context) error {
+			if c.Args().Len() != 1 {
+				c.Set("help", "true")
+				return errors.New("Error: One plan file is required")
+			}
+			name := c.Args().First()
+
+			var content []byte
+			var err error
+			if name == "-" {
+				content, err = io.ReadAll(c.App.Reader)
+			} else {
+				content, err = os.ReadFile(name)
+			}
+			if err != nil {
+				return errors.New(fmt.Sprintf("Failed to read plan: %s: %s", name, err))
+			}
+
+			ctx, stop := interruptible(c)
+			defer stop()
+			ctx = withVerbose(ctx, c)
+			ctx, closeLog, err := withLogFile(ctx, c)
+			if err != nil {
+				return err
+			}
+			defer closeLog()
+			ctx, cancel := withTimeout(ctx, c)
+			defer cancel()
+
+			output, err := colorOutput(c, o.output, o.outputIsTerminal())
+			if err != nil {
+				return err
+			}
+			_, err = create.Apply(ctx, content, runOptionsFromFlags(c, output, o.outputIsTerminal()))
+			if errors.Is(err, create.ErrInvalidPlan) {
+				return fmt.Errorf("Failed to read plan: %s: %w", name, err)
+			} else if errors.Is(err, create.ErrInterrupted) {
+				return cli.Exit(fmt.Sprintf("Failed to apply plan: %s: %s", name, err), interruptedExitCode)
+			} else if err != nil {
+				return fmt.Errorf("Failed to apply plan: %s: %w", name, err)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"errors"
+	"io"
+
+	"github.com/jbrudvik/gmc/create"
+	"github.com/urfave/cli/v2"
+)
+
+func releasePrepCommand(output io.Writer) *cli.Command {
+	return &cli.Command{
+		Name:      "release-prep",
+		Usage:     "check that the Go module in the current directory is ready to release, then tag the release",
+		ArgsUsage: "[version]",
+		Description: "Checks that the version follows the module's major version and existing tags, that CHANGELOG.md has an\n" +
+			"entry for it, that go.mod is tidy, and that the tests pass. If they all do, the commit checked out is tagged\n" +
+			"with the version, ready to push.\n" +
+			"\n" +
+			"    $ " + Name + " release-prep v1.2.0",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "run the checks without tagging",
+			},
+			&cli.BoolFlag{
+				Name:  "git-exec",
+				Usage: "run the git executable for Git actions, instead of the built-in implementation",
+			},
+		},
+		OnUsageError: onCommandUsageError,
+		Action: func(c *cli.Context) error {
+			if c.Args().Len() != 1 {
+				c.Set("help", "true")
+				return errors.New("Error: Exactly one version is required (e.g. v1.2.0)")
+			}
+			version := c.Args().First()
+
+			ctx, stop := interruptible(c)
+			defer stop()
+			r, err := create.ReleasePrep(ctx, create.ReleasePrepOptions{
+				Version: version,
+				DryRun:  c.Bool("dry-run"),
+				GitExec: c.Bool("git-exec"),
+			})
+			var usage create.UsageError
+			if errors.As(err, &usage) {
+				c.Set("help", "true")
+				return err
+			} else if errors.Is(err, create.ErrInterrupted) {
+				return cli.Exit(err.Error(), interruptedExitCode)
+			}
+			if r != nil {
+				flogf(output, false, "Preparing release: %s %s\n", r.Module, version)
+				for _, check := range r.Checks {
+					if check.Error == "" {
+						flogf(output, false, "- OK  : %s\n", check.Name)
+					} else {
+						flogf(output, false, "- FAIL: %s: %s\n", check.Name, check.Error)
+					}
+				}
+			}
+			if err != nil {
+				return err
+			}
+
+			if r.Tag == "" {
+				flogf(output, false, "\nReady to release %s (dry run, not tagged)\n", version)
+				return nil
+			}
+			flogf(output, false, "\nTagged release: %s\n\nNext steps:\n- Push tag: $ git push origin %s\n", r.Tag, r.Tag)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/jbrudvik/gmc/serve"
+	"github.com/urfave/cli/v2"
+)
+
+func serveCommand(output io.Writer) *cli.Command {
+	return &cli.Command{
+		Name:  "serve",
+		Usage: "create Go modules on request, over HTTP",
+		Description: "POST a JSON request to create a module, which is returned as a gzipped tarball:\n" +
+			"\n" +
+			"    $ curl -d '{\"module\": \"github.com/jbrudvik/mymodule\", \"ci\": \"github\"}' http://localhost:8080/modules > mymodule.tar.gz\n" +
+			"\n" +
+			"With \"remote\", the module is pushed there instead, with this machine's Git credentials.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "addr",
+				Usage: "address to listen on",
+				Value: "localhost:8080",
+			},
+		},
+		OnUsageError: onCommandUsageError,
+		Action: func(c *cli.Context) error {
+			if c.Args().Present() {
+				c.Set("help", "true")
+				return errors.New("Error: No arguments are allowed")
+			}
+			mux := http.NewServeMux()
+			mux.Handle("/modules", serve.Handler())
+			server := &http.Server{Addr: c.String("addr"), Handler: mux}
+
+			ctx, stop := interruptible(c)
+			defer stop()
+			go func() {
+				<-ctx.Done()
+				server.Close()
+			}()
+
+			flogf(output, false, "Serving on http://%s/modules\n", c.String("addr"))
+			err := server.ListenAndServe()
+			if errors.Is(err, http.ErrServerClosed) {
+				return nil
+			}
+			return err
+		},
+	}
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/jbrudvik/gmc/create"
+	"github.com/urfave/cli/v2"
+)
+
+func templatesCommand(output io.Writer) *cli.Command {
+	return &cli.Command{
+		Name:  "templates",
+		Usage: "list the templates modules can be created from, with where each comes from and what it adds",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "print the templates as JSON",
+			},
+		},
+		OnUsageError: onCommandUsageError,
+		Action: func(c *cli.Context) error {
+			if c.Args().Present() {
+				c.Set("help", "true")
+				return errors.New("Error: No arguments are allowed")
+			}
+
+			templates := create.Templates()
+			if c.Bool("json") {
+				content, err := json.MarshalIndent(templates, "", "  ")
+				if err != nil {
+					return err
+				}
+				_, err = fmt.Fprintf(output, "%s\n", content)
+				return err
+			}
+			w := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tSOURCE\tDESCRIPTION")
+			for _, template := range templates {
+				fmt.Fprintf(w, "%s\t%s\t%s\n", template.Name, template.Source, template.Description)
+			}
+			return w.Flush()
+		},
+	}
+}
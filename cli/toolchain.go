@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+
+	"github.com/jbrudvik/gmc/create"
+	"github.com/urfave/cli/v2"
+)
+
+// provisionGo returns a go executable to create modules with when go isn't installed: the official toolchain,
+// downloaded into gmc's cache with --provision-go, or if someone at the terminal agrees to it. Without one, modules are
+// created as before, failing only at steps that need Go (e.g. adding dependencies). A dry run runs no Go, so downloads
+// nothing, only noting what --provision-go would download.
+func provisionGo(c *cli.Context, o *appOptions, output io.Writer) (string, error) {
+	if c.Bool("in-container") || create.GoInstalled() {
+		return "", nil
+	}
+	// A pinned toolchain is the one to create the module with
+	goVersion := c.String("go-version")
+	if toolchain := c.String("toolchain"); toolchain != "" {
+		goVersion = strings.TrimPrefix(toolchain, "go")
+	}
+	release := "the latest Go"
+	if goVersion != "" {
+		release = "Go " + goVersion
+	}
+	if c.Bool("dry-run") {
+		if c.Bool("provision-go") {
+			flogf(output, isQuiet(c) || c.Bool("json"), "Go isn't installed: %s would be downloaded into %s's cache\n", release, Name)
+		}
+		return "", nil
+	}
+	if !c.Bool("provision-go") {
+		if !canAsk(c, o) {
+			return "", nil
+		}
+		prompt := fmt.Sprintf("Go isn't installed. Download %s (%s/%s) into %s's cache to create modules with?", release, runtime.GOOS, runtime.GOARCH, Name)
+		if strings.ToLower(ask(bufio.NewReader(c.App.Reader), output, prompt, "y/N")) != "y" {
+			return "", nil
+		}
+	}
+	flogf(output, isQuiet(c), "Downloading %s...\n", release)
+	return create.ProvisionGo(c.Context, goVersion, "")
+}
@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/jbrudvik/gmc/create"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/mod/semver"
+)
+
+func upgradeSelfCommand(output io.Writer) *cli.Command {
+	return &cli.Command{
+		Name:  "upgrade-self",
+		Usage: "replace gmc with its latest release",
+		Description: "Downloads the latest release's binary for this platform from GitHub, verifies it against the release's checksums,\n" +
+			"and replaces the running " + Name + " executable with it. Installed with `go install`, " + Name + " can instead be upgraded with:\n" +
+			"\n" +
+			"    $ go install github.com/jbrudvik/gmc@latest",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "force",
+				Usage: "replace a development build, or reinstall the latest release",
+			},
+		},
+		OnUsageError: onCommandUsageError,
+		Action: func(c *cli.Context) error {
+			if c.Args().Present() {
+				c.Set("help", "true")
+				return errors.New("Error: No arguments are allowed")
+			}
+			quiet := isQuiet(c)
+			force := c.Bool("force")
+
+			release, err := create.LatestRelease(c.Context, "")
+			if err != nil {
+				return err
+			}
+			if !semver.IsValid(Version) && !force {
+				return errors.New(fmt.Sprintf("%s %s is a development build: use --force to replace it with %s", Name, Version, release.Version))
+			}
+			if !release.NewerThan(Version) && !force {
+				flogf(output, quiet, "%s %s is the latest release\n", Name, Version)
+				return nil
+			}
+
+			executable, err := os.Executable()
+			if err == nil {
+				executable, err = filepath.EvalSymlinks(executable)
+			}
+			if err != nil {
+				return errors.New(fmt.Sprintf("Failed to upgrade %s: %s", Name, err))
+			}
+			flogf(output, quiet, "Upgrading %s from %s to %s...\n", Name, Version, release.Version)
+			err = release.Install(c.Context, executable)
+			if err != nil {
+				return err
+			}
+			flogf(output, quiet, "Upgraded %s to %s: %s\n", Name, release.Version, executable)
+			return nil
+		},
+	}
+}
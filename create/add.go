@@ -0,0 +1,199 @@
+package create
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/modfile"
+)
+
+// Features that can be added to an existing module. Besides these, each editor extra can be added.
+var addFeatureNames = []string{"git", "license", "ci", "make", "taskfile", "docker"}
+
+// AddFeatures returns the features that Add can add
+func AddFeatures() []string {
+	return append(append([]string{}, addFeatureNames...), editorExtraNames...)
+}
+
+// AddOptions determine what Add adds
+type AddOptions struct {
+	// Feature to add (see AddFeatures)
+	Feature string
+
+	// Argument of the feature: a license (required for license), or a CI provider (auto if empty)
+	Arg string
+
+	// Run the git executable for Git actions, instead of the built-in implementation
+	GitExec bool
+
+	// Initial branch of the Git repository. If empty, Git's configured default is used.
+	GitInitialBranch string
+
+	// Name to attribute a license to when the Git user.name isn't set
+	Author string
+
+	// Returns the current time, for the year of a license. If nil, the system clock is used.
+	Clock func() time.Time
+
+	RunOptions
+}
+
+// Add adds a feature to the Go module in the current directory, as `gmc add` does. Existing files are kept as they are.
+func Add(ctx context.Context, opts AddOptions) (*Result, error) {
+	feature := strings.ToLower(opts.Feature)
+	if feature == "" {
+		return nil, UsageError{errors.New(fmt.Sprintf("Error: Feature is required (supported: %s)", strings.Join(AddFeatures(), ", ")))}
+	}
+
+	// Find the module to add to
+	module, err := readModule(".")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to add %s: %w", feature, err)
+	}
+
+	// Parse feature
+	git := newGitClient(ctx, opts.GitExec)
+	planOpts := planOptions{flags: []string{"--" + feature}}
+	switch feature {
+	case "git":
+		planOpts.repo = &gitRepo{client: git}
+		if opts.GitInitialBranch != "" {
+			planOpts.repo.initialBranch = &opts.GitInitialBranch
+		}
+	case "license":
+		if opts.Arg == "" {
+			return nil, UsageError{errors.New(fmt.Sprintf("Error: License is required (supported: %s)", strings.Join(licenseIds, ", ")))}
+		}
+		licenseId, err := parseLicenseId(opts.Arg)
+		if err != nil {
+			return nil, UsageError{err}
+		}
+		author, err := licenseAuthor(git, opts.Author, opts.DryRun)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to add %s: %w", feature, err)
+		}
+		planOpts.flags = []string{"--license=" + licenseId}
+		planOpts.license = &license{
+			id:     licenseId,
+			author: author,
+			year:   now(opts.Clock).Year(),
+		}
+	case "ci":
+		arg := opts.Arg
+		if arg == "" {
+			arg = ciProviderAuto
+		}
+		planOpts.ci, err = selectCiProvider(arg, module.path)
+		if err != nil {
+			return nil, UsageError{err}
+		}
+		planOpts.flags = []string{"--ci=" + planOpts.ci.name()}
+	case "make", "taskfile", "docker":
+		planOpts.extraDirs = []string{feature}
+		planOpts.docker = feature == "docker"
+	default:
+		editor, ok := editorExtras[feature]
+		if !ok {
+			return nil, UsageError{errors.New(fmt.Sprintf("Error: Unsupported feature: %s (supported: %s)", feature, strings.Join(AddFeatures(), ", ")))}
+		}
+		planOpts.extraDirs = []string{feature}
+		planOpts.editor = editor.command
+	}
+
+	// Plan additions
+	p, err := newAddPlan(module, feature, planOpts)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to add %s: %w", feature, err)
+	}
+
+	// Add to module
+	r, err := runPlan(ctx, p, opts.RunOptions)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to add %s: %w", feature, err)
+	}
+	return r, nil
+}
+
+// An existing module, as declared by its go.mod
+type existingModule struct {
+	path      string
+	goVersion string
+}
+
+func readModule(dir string) (*existingModule, error) {
+	goModPath := filepath.Join(dir, goModFileName)
+	content, err := os.ReadFile(goModPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, wrap(ErrNoModule, err, "No go.mod in the current directory (run from the root of a Go module)")
+	} else if err != nil {
+		return nil, err
+	}
+	f, err := modfile.ParseLax(goModPath, content, nil)
+	if err != nil {
+		return nil, err
+	}
+	if f.Module == nil {
+		return nil, wrap(ErrNoModule, nil, "No module declared in %s", goModPath)
+	}
+	m := &existingModule{path: f.Module.Mod.Path, goVersion: defaultGoVersion}
+	if f.Go != nil {
+		m.goVersion = f.Go.Version
+	}
+	return m, nil
+}
+
+// newAddPlan plans adding a feature to an existing module in the current directory
+func newAddPlan(m *existingModule, feature string, opts planOptions) (*plan, error) {
+	p := &plan{
+		task:         fmt.Sprintf("adding %s to Go module", feature),
+		existing:     true,
+		module:       m.path,
+		moduleBase:   filepath.Base(m.path),
+		dir:          ".",
+		repo:         opts.repo,
+		license:      opts.license,
+		docker:       opts.docker,
+		editor:       opts.editor,
+		goVersion:    m.goVersion,
+		flags:        opts.flags,
+		assetSources: map[string]string{},
+		wsl:          runningInWSL(),
+	}
+
+	var err error
+	if p.repo != nil {
+		p.addGitRepo()
+	}
+	if p.license != nil {
+		err = p.addLicense()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if opts.ci != nil {
+		err = opts.ci.configure(p)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, extraDir := range opts.extraDirs {
+		err = p.addEmbeddedFS(assets, extraDir)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	p.keepExisting()
+
+	// Record what was added
+	err = p.updateManifest()
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
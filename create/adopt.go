@@ -0,0 +1,161 @@
+package create
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// AdoptOptions determine how Adopt runs
+type AdoptOptions struct {
+	RunOptions
+}
+
+// A feature that Adopt recognizes in a module by its files, as it's recorded in the manifest
+type adoptFeature struct {
+	// Flag the module would have been created with (e.g. "--ci=github")
+	flag string
+
+	// Asset set that provides the feature's files, if any
+	template string
+
+	// Patterns (as filepath.Glob matches them) of the files, any of which means the module has the feature
+	patterns []string
+}
+
+var adoptFeatures = []adoptFeature{
+	{flag: "--git", patterns: []string{".git"}},
+	{flag: "--ci=github", template: "ci-github", patterns: []string{".github/workflows/*.yml", ".github/workflows/*.yaml"}},
+	{flag: "--ci=gitlab", template: "ci-gitlab", patterns: []string{".gitlab-ci.yml"}},
+	{flag: "--lint", template: "lint", patterns: []string{".golangci.yml", ".golangci.yaml", ".golangci.toml", ".golangci.json"}},
+	{flag: "--goreleaser", template: "goreleaser", patterns: []string{".goreleaser.yml", ".goreleaser.yaml"}},
+	{flag: "--docker", template: "docker", patterns: []string{"Dockerfile"}},
+	{flag: "--make", template: "make", patterns: []string{"Makefile"}},
+	{flag: "--taskfile", template: "taskfile", patterns: []string{"Taskfile.yml", "Taskfile.yaml"}},
+}
+
+// Files every module is expected to have, and how to add each that's missing, if gmc can
+var adoptStandardFiles = []struct {
+	path string
+	add  string
+}{
+	{gitignoreFileName, ""},
+	{readmeFileName, ""},
+	{licenseFileName, "gmc add license <license>"},
+}
+
+// Adopt brings the Go module in the current directory, which gmc didn't create, under gmc's management, as `gmc adopt`
+// does: it infers which of gmc's features the module has from its files, writes a manifest that records them, and notes
+// which standard files are missing. No other file is changed.
+func Adopt(ctx context.Context, opts AdoptOptions) (*Result, error) {
+	module, err := readModule(".")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to adopt Go module: %w", err)
+	}
+	manifestPath := filepath.Join(manifestDirName, manifestFileName)
+	if _, err := os.Stat(manifestPath); err == nil {
+		return nil, errors.New(fmt.Sprintf("Failed to adopt Go module: %s: Already managed by gmc (%s exists)", module.path, filepath.ToSlash(manifestPath)))
+	}
+
+	p, err := newAdoptPlan(module)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to adopt Go module: %s: %w", module.path, err)
+	}
+	r, err := runPlan(ctx, p, opts.RunOptions)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to adopt Go module: %s: %w", module.path, err)
+	}
+	return r, nil
+}
+
+// newAdoptPlan plans adopting an existing module in the current directory: notes of what was found and what's missing,
+// and the manifest
+func newAdoptPlan(m *existingModule) (*plan, error) {
+	p := &plan{
+		task:       "adopting Go module",
+		existing:   true,
+		module:     m.path,
+		moduleBase: filepath.Base(m.path),
+		dir:        ".",
+		goVersion:  m.goVersion,
+		wsl:        runningInWSL(),
+	}
+	adopted := manifest{
+		GmcVersion: Version,
+		Module:     m.path,
+		GoVersion:  m.goVersion,
+		Flags:      []string{},
+		Templates:  []string{},
+		Files:      []manifestFile{},
+		Adopted:    true,
+	}
+	recorded := map[string]bool{}
+	record := func(path string) error {
+		if info, err := os.Stat(path); err != nil || !info.Mode().IsRegular() || recorded[path] {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		recorded[path] = true
+		checksum := sha256.Sum256(content)
+		adopted.Files = append(adopted.Files, manifestFile{Path: filepath.ToSlash(path), SHA256: hex.EncodeToString(checksum[:])})
+		return nil
+	}
+
+	// Infer features from their files
+	found := map[string]bool{}
+	for _, feature := range adoptFeatures {
+		matches := []string{}
+		for _, pattern := range feature.patterns {
+			globbed, _ := filepath.Glob(filepath.FromSlash(pattern))
+			matches = append(matches, globbed...)
+		}
+		if len(matches) == 0 {
+			continue
+		}
+		p.add(step{action: actionNote, arg: fmt.Sprintf("Found %s: %s", feature.flag, filepath.ToSlash(matches[0]))})
+		adopted.Flags = append(adopted.Flags, feature.flag)
+		if feature.template != "" {
+			adopted.Templates = append(adopted.Templates, feature.template)
+		}
+		found[feature.flag] = true
+		for _, match := range matches {
+			if err := record(match); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Report what's missing, with how to add it
+	if !found["--git"] {
+		p.add(step{action: actionNote, arg: "Missing Git repository (add it with: gmc add git)"})
+	}
+	if !found["--ci=github"] && !found["--ci=gitlab"] {
+		p.add(step{action: actionNote, arg: "Missing CI (add it with: gmc add ci)"})
+	}
+	for _, file := range adoptStandardFiles {
+		if _, err := os.Stat(file.path); err == nil {
+			if err := record(file.path); err != nil {
+				return nil, err
+			}
+		} else if file.add != "" {
+			p.add(step{action: actionNote, arg: fmt.Sprintf("Missing file: %s (add it with: %s)", file.path, file.add)})
+		} else {
+			p.add(step{action: actionNote, arg: fmt.Sprintf("Missing file: %s", file.path)})
+		}
+	}
+
+	sort.Slice(adopted.Files, func(i, j int) bool { return adopted.Files[i].Path < adopted.Files[j].Path })
+	err := p.addManifestSteps(adopted)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
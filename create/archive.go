@@ -0,0 +1,250 @@
+package create
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Archive formats, by file name suffix, and how each is extracted
+var archiveFormats = []struct {
+	suffix  string
+	write   func(w io.Writer, dir string, name string) error
+	extract string
+}{
+	{".tar.gz", WriteTarball, "tar -xzf"},
+	{".tgz", WriteTarball, "tar -xzf"},
+	{".tar", WriteTar, "tar -xf"},
+	{".zip", WriteZip, "unzip"},
+}
+
+// Archive name for writing an archive to Options.ArchiveOutput, as a tarball
+const streamedArchive = "-"
+
+// createArchive creates a module (as Create does) in a temporary directory, and writes it to opts.Archive instead
+func createArchive(ctx context.Context, opts Options) (*Result, error) {
+	archive := opts.Archive
+	streamed := archive == streamedArchive
+	name := strings.ToLower(archive)
+	if streamed {
+		// A gzipped tarball can't be read from a pipe by `tar -x` alone
+		name = ".tar"
+	}
+	format := -1
+	suffixes := []string{}
+	for i, f := range archiveFormats {
+		if strings.HasSuffix(name, f.suffix) {
+			format = i
+		}
+		suffixes = append(suffixes, f.suffix)
+	}
+	if format < 0 {
+		return nil, UsageError{errors.New(fmt.Sprintf("Error: Unsupported archive format: %s (supported: %s)", archive, strings.Join(suffixes, ", ")))}
+	}
+	conflicts := []struct {
+		flag string
+		set  bool
+	}{
+		{"--git", opts.Git},
+		{"--bundle", opts.Bundle || opts.BundleOnly},
+		{"--output-dir", opts.OutputDir != ""},
+		{"--full-path", opts.FullPath},
+		{"--force", opts.Force},
+		{"--resume", opts.Resume},
+		{"init", opts.InPlace},
+	}
+	for _, c := range conflicts {
+		if c.set {
+			return nil, UsageError{errors.New(fmt.Sprintf("Error: --archive can't be used with %s", c.flag))}
+		}
+	}
+	if _, err := os.Lstat(archive); err == nil && !streamed {
+		return nil, wrap(ErrArchiveExists, nil, "Failed to create Go module: %s: Archive already exists: %s", opts.Module, archive)
+	}
+
+	tempDir, err := os.MkdirTemp("", Name+"-archive-")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create Go module: %s: %w", opts.Module, err)
+	}
+	defer os.RemoveAll(tempDir)
+	opts.OutputDir = tempDir
+	p, err := planModule(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// The module's own progress would show the temporary directory, so only the archive is reported
+	run := opts.RunOptions
+	r, err := runPlan(ctx, p, RunOptions{DryRun: run.DryRun})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create Go module: %s: %w", opts.Module, err)
+	}
+	for i, path := range r.CreatedDirectories {
+		r.CreatedDirectories[i] = withoutFilepathPrefix(path, tempDir)
+	}
+	for i, path := range r.CreatedFiles {
+		r.CreatedFiles[i] = withoutFilepathPrefix(path, tempDir)
+	}
+	r.Archive = archive
+	nextSteps := []string{fmt.Sprintf("Change into module's directory: $ cd %s", p.moduleBase)}
+	if !streamed {
+		nextSteps = append([]string{fmt.Sprintf("Extract module: $ %s %s", archiveFormats[format].extract, archive)}, nextSteps...)
+	}
+	r.NextSteps = append(nextSteps, r.NextSteps[1:]...)
+
+	if !run.DryRun {
+		write := archiveFormats[format].write
+		if streamed {
+			archiveOutput := opts.ArchiveOutput
+			if archiveOutput == nil {
+				archiveOutput = os.Stdout
+			}
+			err = write(archiveOutput, tempDir, p.moduleBase)
+			if err != nil {
+				err = wrap(nil, err, "Failed to write archive: %s", err)
+			}
+		} else {
+			err = writeArchiveFile(archive, tempDir, p.moduleBase, write)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create Go module: %s: %w", opts.Module, err)
+		}
+	}
+
+	output := run.Output
+	if output == nil {
+		return r, nil
+	} else if run.JSON {
+		return r, r.write(output)
+	}
+	header, color, verb := "%s: %s\n", colorGreen, "Created"
+	if run.DryRun {
+		header, color, verb = "%s (dry run): %s\n", "", "Would create"
+	}
+	flogf(output, false, header, capitalize(p.task), p.module)
+	for _, note := range r.Notes {
+		reportNote(output, false, note)
+	}
+	if streamed {
+		archive = "standard output"
+	}
+	reportAtPath(output, false, color, verb, "archive", archive)
+	reportFinished(output, false, run.DryRun, p.task, p.module)
+	reportNextSteps(output, false, r.NextSteps)
+	return r, nil
+}
+
+// writeArchiveFile writes the directory dir/name to a new archive file with write, removing the file if that fails
+func writeArchiveFile(archive string, dir string, name string, write func(w io.Writer, dir string, name string) error) error {
+	f, err := os.OpenFile(archive, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if errors.Is(err, fs.ErrExist) {
+		return wrap(ErrArchiveExists, err, "Archive already exists: %s", archive)
+	} else if err != nil {
+		return err
+	}
+	err = write(f, dir, name)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(archive)
+		return wrap(nil, err, "Failed to write archive: %s: %s", archive, err)
+	}
+	return nil
+}
+
+// WriteTarball writes the directory dir/name to w as a gzipped tarball, of paths starting with name
+func WriteTarball(w io.Writer, dir string, name string) error {
+	gz := gzip.NewWriter(w)
+	err := WriteTar(gz, dir, name)
+	if err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// WriteTar writes the directory dir/name to w as a tarball, of paths starting with name
+func WriteTar(w io.Writer, dir string, name string) error {
+	tw := tar.NewWriter(w)
+	err := walkArchive(dir, name, func(archivePath string, info fs.FileInfo, content []byte) error {
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = archivePath
+		err = tw.WriteHeader(header)
+		if err != nil {
+			return err
+		}
+		_, err = tw.Write(content)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// WriteZip writes the directory dir/name to w as a zip file, of paths starting with name
+func WriteZip(w io.Writer, dir string, name string) error {
+	zw := zip.NewWriter(w)
+	err := walkArchive(dir, name, func(archivePath string, info fs.FileInfo, content []byte) error {
+		header, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		header.Name = archivePath
+		if !info.IsDir() {
+			header.Method = zip.Deflate
+		}
+		fw, err := zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		_, err = fw.Write(content)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// walkArchive calls add with the archive path (ending in / for a directory), info, and content of each directory and
+// regular file in dir/name. Other files (e.g. symlinks) aren't archived.
+func walkArchive(dir string, name string, add func(archivePath string, info fs.FileInfo, content []byte) error) error {
+	return filepath.WalkDir(filepath.Join(dir, name), func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(dir, filePath)
+		if err != nil {
+			return err
+		}
+		archivePath := filepath.ToSlash(relPath)
+		var content []byte
+		if d.IsDir() {
+			archivePath += "/"
+		} else if info.Mode().IsRegular() {
+			content, err = os.ReadFile(filePath)
+			if err != nil {
+				return err
+			}
+		} else {
+			return nil
+		}
+		return add(archivePath, info, content)
+	})
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// TestExecutable builds the executable, runs it with each test case's arguments, and checks what it prints and how it
+// exits
+func TestExecutable(t *testing.T) {
+	tests := []struct {
+		args             []string
+		expectedExitCode int
+		expectedStdout   string
+		expectedStderr   string
+	}{
+		{
+			args:             nil,
+			expectedExitCode: 0,
+			expectedStdout:   "hello, world!\n",
+			expectedStderr:   "",
+		},
+	}
+
+	// Build executable into a temporary directory (automatically cleaned up)
+	executablePath := filepath.Join(t.TempDir(), "main")
+	if runtime.GOOS == "windows" {
+		executablePath += ".exe"
+	}
+	buildOutput, err := exec.Command("go", "build", "-o", executablePath, ".").CombinedOutput()
+	if err != nil {
+		t.Fatalf("Unable to build executable: %s\n%s", err, buildOutput)
+	}
+
+	for _, tc := range tests {
+		// Run executable and test outputs
+		cmd := exec.Command(executablePath, tc.args...)
+		var stdout bytes.Buffer
+		var stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		err := cmd.Run()
+		exitCode := 0
+		var exitError *exec.ExitError
+		if errors.As(err, &exitError) {
+			exitCode = exitError.ExitCode()
+		} else if err != nil {
+			t.Fatalf("Unable to run executable: %s", err)
+		}
+		if exitCode != tc.expectedExitCode {
+			t.Errorf("%v: exit code = %d, want %d", tc.args, exitCode, tc.expectedExitCode)
+		}
+		if stdout.String() != tc.expectedStdout {
+			t.Errorf("%v: stdout = %q, want %q", tc.args, stdout.String(), tc.expectedStdout)
+		}
+		if stderr.String() != tc.expectedStderr {
+			t.Errorf("%v: stderr = %q, want %q", tc.args, stderr.String(), tc.expectedStderr)
+		}
+	}
+}
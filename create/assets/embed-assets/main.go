@@ -0,0 +1,19 @@
+package main
+
+import (
+	"embed"
+	"fmt"
+)
+
+// Everything in the assets directory is embedded into the binary at build time
+//
+//go:embed assets
+var assets embed.FS
+
+func main() {
+	greeting, err := assets.ReadFile("assets/hello.txt")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Print(string(greeting))
+}
@@ -0,0 +1,30 @@
+-- Project-local Neovim configuration. Loaded when 'exrc' is set (see :help exrc).
+
+-- gopls settings, for nvim-lspconfig
+local ok, lspconfig = pcall(require, "lspconfig")
+if ok then
+  lspconfig.gopls.setup({
+    settings = {
+      gopls = {
+        gofumpt = false,
+        staticcheck = true,
+        usePlaceholders = true,
+        analyses = {
+          unusedparams = true,
+        },
+      },
+    },
+  })
+end
+
+-- Debug configuration, for nvim-dap-go
+local ok_dap, dap = pcall(require, "dap")
+if ok_dap then
+  dap.configurations.go = dap.configurations.go or {}
+  table.insert(dap.configurations.go, {
+    type = "go",
+    name = "Debug module",
+    request = "launch",
+    program = "${workspaceFolder}",
+  })
+end
@@ -0,0 +1,8 @@
+# Vet and run the module's tests. Arguments are passed to go test (e.g. -run TestName).
+$ErrorActionPreference = "Stop"
+Set-Location (Join-Path $PSScriptRoot "..")
+
+go vet ./...
+if ($LASTEXITCODE -ne 0) { exit $LASTEXITCODE }
+go test @args ./...
+exit $LASTEXITCODE
@@ -0,0 +1,162 @@
+package create
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// AuditOptions determine what Audit audits, and against what
+type AuditOptions struct {
+	// Directories of modules, or of repositories that each hold one (e.g. a checkout of every repository in an
+	// organization), which are audited one level deep
+	Paths []string
+
+	// Features every module must have, named as their flags are without "--" (e.g. "ci", "lint", or "license")
+	Require []string
+
+	// gmc version that modules created by an earlier version are outdated against. If empty, Version is used.
+	Version string
+}
+
+// A ModuleAudit reports how a module compares to what's current, as recorded in its manifest
+type ModuleAudit struct {
+	Dir    string `json:"dir"`
+	Module string `json:"module"`
+
+	// Whether the module has a manifest. A module without one can be given one with `gmc adopt`.
+	Managed bool `json:"managed"`
+
+	// gmc version the module was created (or adopted) with
+	GmcVersion string `json:"gmcVersion"`
+
+	// Whether the module was created by an earlier version of gmc, whose templates may have changed since
+	Outdated bool `json:"outdated"`
+
+	// Required features that the module wasn't created with, and doesn't have the files of
+	MissingFeatures []string `json:"missingFeatures"`
+
+	// Files gmc generated (or recorded when adopting) that have changed since, or been removed
+	ModifiedFiles []string `json:"modifiedFiles"`
+
+	// Why the module couldn't be audited, if it couldn't (e.g. an invalid manifest)
+	Error string `json:"error,omitempty"`
+}
+
+// Audit reports, for each module in opts.Paths, whether it's outdated, which required features it's missing, and which
+// of its generated files have changed, as `gmc audit` does. Directories that are neither modules nor hold any are
+// skipped.
+func Audit(opts AuditOptions) ([]ModuleAudit, error) {
+	version := opts.Version
+	if version == "" {
+		version = Version
+	}
+	audits := []ModuleAudit{}
+	for _, path := range opts.Paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("Failed to audit: %s", err))
+		}
+		if !info.IsDir() {
+			return nil, errors.New(fmt.Sprintf("Failed to audit: %s: Not a directory", path))
+		}
+		if isAuditable(path) {
+			audits = append(audits, auditModule(path, opts.Require, version))
+			continue
+		}
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("Failed to audit: %s", err))
+		}
+		for _, entry := range entries {
+			dir := filepath.Join(path, entry.Name())
+			if entry.IsDir() && isAuditable(dir) {
+				audits = append(audits, auditModule(dir, opts.Require, version))
+			}
+		}
+	}
+	return audits, nil
+}
+
+// isAuditable reports whether dir holds a module, or a manifest of one
+func isAuditable(dir string) bool {
+	for _, name := range []string{goModFileName, filepath.Join(manifestDirName, manifestFileName)} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func auditModule(dir string, require []string, version string) ModuleAudit {
+	a := ModuleAudit{Dir: dir, MissingFeatures: []string{}, ModifiedFiles: []string{}}
+	content, err := os.ReadFile(filepath.Join(dir, manifestDirName, manifestFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		if m, err := readModule(dir); err == nil {
+			a.Module = m.path
+		}
+		return a
+	}
+	a.Managed = true
+	var m manifest
+	if err == nil {
+		err = json.Unmarshal(content, &m)
+	}
+	if err != nil {
+		a.Error = fmt.Sprintf("Invalid manifest: %s", err)
+		return a
+	}
+	a.Module = m.Module
+	a.GmcVersion = m.GmcVersion
+
+	// Development builds have no version to compare
+	a.Outdated = semver.IsValid(m.GmcVersion) && semver.IsValid(version) && semver.Compare(m.GmcVersion, version) < 0
+
+	for _, feature := range require {
+		if !hasFlag(m.Flags, feature) && !hasFeatureFiles(dir, feature) {
+			a.MissingFeatures = append(a.MissingFeatures, feature)
+		}
+	}
+	for _, f := range m.Files {
+		content, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(f.Path)))
+		checksum := sha256.Sum256(content)
+		if err != nil || hex.EncodeToString(checksum[:]) != f.SHA256 {
+			a.ModifiedFiles = append(a.ModifiedFiles, f.Path)
+		}
+	}
+	return a
+}
+
+// hasFeatureFiles reports whether the module in dir has the files of the feature named name (e.g. "ci"), as Adopt
+// recognizes them, so that a feature added after the module was created counts even if its manifest doesn't record it
+func hasFeatureFiles(dir string, name string) bool {
+	for _, feature := range adoptFeatures {
+		if !hasFlag([]string{feature.flag}, name) {
+			continue
+		}
+		for _, pattern := range feature.patterns {
+			if matches, _ := filepath.Glob(filepath.Join(dir, filepath.FromSlash(pattern))); len(matches) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasFlag reports whether flags (as recorded in a manifest, e.g. "--ci=github") include the flag named name (e.g. "ci")
+func hasFlag(flags []string, name string) bool {
+	flag := "--" + strings.TrimPrefix(name, "--")
+	for _, f := range flags {
+		if f == flag || (strings.HasPrefix(f, flag+"=") && f != flag+"=false") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package create
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// A ciProvider generates the CI configuration for a module
+type ciProvider interface {
+	name() string
+
+	// configure adds the provider's CI configuration files to the plan
+	configure(p *plan) error
+}
+
+type githubActions struct{}
+
+func (githubActions) name() string {
+	return "github"
+}
+
+func (githubActions) configure(p *plan) error {
+	return p.addEmbeddedFS(assets, "ci-github")
+}
+
+type gitlabCI struct{}
+
+func (gitlabCI) name() string {
+	return "gitlab"
+}
+
+func (gitlabCI) configure(p *plan) error {
+	return p.addEmbeddedFS(assets, "ci-gitlab")
+}
+
+var ciProviderList = []ciProvider{githubActions{}, gitlabCI{}}
+
+// ciProviderAuto selects a CI provider based on where the module is hosted
+const ciProviderAuto string = "auto"
+
+// CIProviders returns the CI providers that a workflow can be added for, including auto
+func CIProviders() []string {
+	names := []string{}
+	for _, provider := range ciProviderList {
+		names = append(names, provider.name())
+	}
+	return append(names, ciProviderAuto)
+}
+
+func selectCiProvider(name string, module string) (ciProvider, error) {
+	name = strings.ToLower(name)
+	if name == ciProviderAuto {
+		if strings.HasPrefix(module, "gitlab.com/") {
+			return gitlabCI{}, nil
+		}
+		return githubActions{}, nil
+	}
+	for _, provider := range ciProviderList {
+		if provider.name() == name {
+			return provider, nil
+		}
+	}
+	return nil, errors.New(fmt.Sprintf("Error: Unsupported CI provider: %s (supported: %s)", name, strings.Join(CIProviders(), ", ")))
+}
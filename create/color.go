@@ -0,0 +1,48 @@
+package create
+
+import (
+	"fmt"
+	"io"
+)
+
+// ANSI escape codes of the colors progress is written in
+const (
+	colorGreen     = "\x1b[32m"
+	colorYellow    = "\x1b[33m"
+	colorBold      = "\x1b[1m"
+	colorBoldGreen = "\x1b[1;32m"
+	colorReset     = "\x1b[0m"
+)
+
+// A ColorWriter wraps a writer that progress (e.g. RunOptions.Output) is written to in color: green for what was
+// created, yellow for notes and what was removed, and bold for the success banner and next steps. Progress written to
+// any other writer is plain text.
+type ColorWriter struct {
+	io.Writer
+}
+
+// colored returns s in color (if not ""), if it's written to a ColorWriter
+func colored(output io.Writer, color string, s string) string {
+	if _, ok := output.(ColorWriter); !ok || color == "" {
+		return s
+	}
+	return color + s + colorReset
+}
+
+// reportDone reports a step that was carried out
+func reportDone(output io.Writer, quiet bool, format string, a ...any) {
+	flogf(output, quiet, "- %s\n", colored(output, colorGreen, fmt.Sprintf(format, a...)))
+}
+
+func reportNote(output io.Writer, quiet bool, note string) {
+	flogf(output, quiet, "- %s\n", colored(output, colorYellow, "NOTE: "+note))
+}
+
+// reportFinished reports that the task is finished, in bold green if the task was carried out (not a dry run)
+func reportFinished(output io.Writer, quiet bool, dryRun bool, task string, module string) {
+	if dryRun {
+		flogf(output, quiet, "\n%s\n", colored(output, colorBold, fmt.Sprintf("Finished dry run of %s: %s", task, module)))
+	} else {
+		flogf(output, quiet, "\n%s\n", colored(output, colorBoldGreen, fmt.Sprintf("Finished %s: %s", task, module)))
+	}
+}
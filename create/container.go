@@ -0,0 +1,73 @@
+package create
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+)
+
+// Container engines that Options.InContainer runs Go commands with, in order of preference
+var containerEngines = []string{"docker", "podman"}
+
+// Image of the Go toolchain, run with Options.InContainer. Without a version, it's the latest.
+func goContainerImage(goVersion string) string {
+	if goVersion == "" {
+		return "golang"
+	}
+	return "golang:" + goVersion
+}
+
+// containerEngine returns the first container engine installed
+func containerEngine() (string, error) {
+	for _, engine := range containerEngines {
+		if _, err := exec.LookPath(engine); err == nil {
+			return engine, nil
+		}
+	}
+	return "", wrap(ErrGoToolchainMissing, exec.ErrNotFound, "No container engine found to run Go in (install Docker or Podman)")
+}
+
+// goCommand runs go with args in dir (if not ""), with the plan's toolchain (by default, the installed one), or in its
+// container
+func (p *plan) goCommand(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	if p.container == "" {
+		goExecutable := p.goToolchain
+		if goExecutable == "" {
+			goExecutable = "go"
+		}
+		return runCommandWithEnv(ctx, dir, p.goEnv(), goExecutable, args...)
+	}
+	engine, err := containerEngine()
+	if err != nil {
+		return nil, err
+	}
+
+	// Caches go in /tmp, which any user can write to, so that files are created as the user running gmc
+	runArgs := []string{"run", "--rm", "-e", "GOCACHE=/tmp/go-cache", "-e", "GOPATH=/tmp/go"}
+	// Where modules are fetched from is passed through, since the container has none of the user's go env
+	for _, name := range goEnvSettings {
+		if _, ok := os.LookupEnv(name); ok {
+			runArgs = append(runArgs, "-e", name)
+		}
+	}
+	for _, setting := range p.goEnv() {
+		runArgs = append(runArgs, "-e", setting)
+	}
+	if engine == "podman" {
+		runArgs = append(runArgs, "--userns=keep-id")
+	} else if runtime.GOOS == "linux" {
+		runArgs = append(runArgs, "--user", fmt.Sprintf("%d:%d", os.Getuid(), os.Getgid()))
+	}
+	if dir != "" {
+		absDir, err := filepath.Abs(dir)
+		if err != nil {
+			return nil, err
+		}
+		runArgs = append(runArgs, "-v", absDir+":/src", "-w", "/src")
+	}
+	runArgs = append(runArgs, p.container, "go")
+	return runCommand(ctx, "", engine, append(runArgs, args...)...)
+}
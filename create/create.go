@@ -0,0 +1,799 @@
+package create
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/module"
+)
+
+const Name string = "gmc"
+
+var Version string = getVersion()
+
+//go:embed all:assets
+var assets embed.FS
+
+const assetsDir string = "assets"
+const assetsDefaultDir string = "default"
+
+// Assets with this extension are rendered as templates, then written without it
+const templateFileExtension string = ".tmpl"
+
+// Go version written to the go directive of go.mod when no Go toolchain is installed
+const defaultGoVersion string = "1.18"
+
+// Matches Go versions that can be pinned with --go-version
+var goVersionRegexp = regexp.MustCompile(`^1\.\d+(\.\d+)?$`)
+
+// Matches toolchains that can be pinned with --toolchain (e.g. go1.22.3 or go1.23rc1), capturing the language version
+var toolchainRegexp = regexp.MustCompile(`^go(1\.\d+)(\.\d+|rc\d+)?$`)
+
+type gitRepo struct {
+	initialBranch *string
+	client        gitClient
+}
+
+// Feature flag SDKs, and the module each depends on
+var featureFlagsDependencies = map[string]string{
+	"openfeature": "github.com/open-feature/go-sdk",
+}
+
+// FeatureFlagsSDKs returns the feature flag SDKs that can be added
+func FeatureFlagsSDKs() []string {
+	sdks := []string{}
+	for sdk := range featureFlagsDependencies {
+		sdks = append(sdks, sdk)
+	}
+	sort.Strings(sdks)
+	return sdks
+}
+
+// Cloud development environments that can be configured with --cloud-dev
+var cloudDevEnvironments = []string{"gitpod", "codespaces"}
+
+// CloudDevEnvironments returns the cloud development environments that can be configured
+func CloudDevEnvironments() []string {
+	return append([]string{}, cloudDevEnvironments...)
+}
+
+// Formatters that CI can check formatting with (Options.FmtCheck), and the command that lists unformatted files
+var formatterCommands = map[string]string{
+	"gofmt":   "gofmt",
+	"gofumpt": "go run mvdan.cc/gofumpt@latest",
+}
+
+// Formatters returns the formatters that CI can check formatting with
+func Formatters() []string {
+	return []string{"gofmt", "gofumpt"}
+}
+
+// Asset set of each package layout
+var layoutAssetSets = map[string]string{
+	"apiv1": "layout-apiv1",
+}
+
+// Layouts returns the package layouts that can be added
+func Layouts() []string {
+	return []string{"apiv1"}
+}
+
+const goModFileName string = "go.mod"
+const gitignoreFileName string = ".gitignore"
+const readmeFileName string = "README.md"
+
+// Directory of a library's example programs, which are a module of their own
+const examplesDirName string = "examples"
+
+// Run to check the dependencies a module is created with for known vulnerabilities
+const govulncheckPackage string = "golang.org/x/vuln/cmd/govulncheck@latest"
+
+// Run to generate NOTICE, the attributions of a module's third-party dependencies, from the template in noticeTemplateFileName
+const goLicensesPackage string = "github.com/google/go-licenses@latest"
+const noticeFileName string = "NOTICE"
+const noticeTemplateFileName string = "NOTICE.tpl"
+
+// Directory of a module's own Git hooks, which its repository runs hooks from
+const gitHooksDirName string = ".githooks"
+
+var defaultLinters = []string{"errcheck", "govet", "ineffassign", "staticcheck", "unused"}
+
+// DefaultLinters returns the linters enabled by Options.Lint when Options.Linters is empty
+func DefaultLinters() []string {
+	return append([]string{}, defaultLinters...)
+}
+
+// Options determine what Create creates. Most correspond to a flag of `gmc new`, and the zero value (besides Module)
+// creates what `gmc new` does without flags. Unlike gmc itself, Create doesn't read the config file.
+type Options struct {
+	// Module path, e.g. github.com/jbrudvik/mymodule. No prefix is added to a name without a slash.
+	Module string
+
+	// Directory to create the module's directory in, instead of the current one
+	OutputDir string
+
+	// Create the module's directory at its full module path (e.g. github.com/jbrudvik/mymodule, as in a GOPATH), instead
+	// of at its last element (mymodule), creating any parent directories that don't exist. Can't be used with InPlace.
+	FullPath bool
+
+	// Archive file (.tar.gz, .tgz, .tar, or .zip) to write the module to, instead of a directory, or "-" to write it to
+	// ArchiveOutput as a tarball. Can't be used with Git, OutputDir, FullPath, InPlace, Force, or Resume.
+	Archive string
+
+	// Where an Archive of "-" is written. If nil, it's standard output.
+	ArchiveOutput io.Writer
+
+	// Create the module in OutputDir (or the current directory) itself, as `gmc init` does
+	InPlace bool
+
+	// Create the module in its directory even if the directory exists, keeping any files already there
+	Force bool
+
+	// Finish creating a module whose creation was interrupted, skipping what was already done
+	Resume bool
+
+	// Create as Git repository
+	Git bool
+
+	// Run the git executable for Git actions, instead of the built-in implementation
+	GitExec bool
+
+	// Initial branch of the Git repository. If empty, Git's configured default is used.
+	GitInitialBranch string
+
+	// Create the remote repository on GitHub, which must be named by the module path (github.com/<owner>/<name>),
+	// with the GitHub API, authenticated with $GITHUB_TOKEN or the GitHub CLI's token. Requires Git.
+	CreateRemote bool
+
+	// Push the initial commit to the remote (git push -u origin <branch>), once it's created (with CreateRemote), or if
+	// it already exists. A failed push is reported, and left as a next step, without undoing what was created. Requires
+	// Git, and a module path that names a remote.
+	Push bool
+
+	// Make the remote repository that's created private (the default), or public. They can't both be set.
+	PrivateRemote bool
+	PublicRemote  bool
+
+	// Protocol of the Git remote's URL (see RemoteProtocols): ssh (git@host:owner/repo.git), the default, or https
+	// (https://host/owner/repo.git)
+	RemoteProtocol string
+
+	// Bundle the Git repository (with git bundle) into <name>.bundle, next to the module's directory. Implies Git.
+	Bundle bool
+
+	// Bundle the Git repository as Bundle does, and then remove the module's directory, which the bundle can be cloned
+	// into. Can't be used with InPlace, Force, or Resume.
+	BundleOnly bool
+
+	// CI workflow to add: github, gitlab, or auto
+	CI string
+
+	// Go version for go.mod, CI, and the Dockerfile (e.g. 1.21). If empty, the installed version is used.
+	GoVersion string
+
+	// Go toolchain to pin the module to (e.g. go1.22.3), with a toolchain directive in go.mod, and GOTOOLCHAIN in an
+	// .envrc and CI, so that it's built with that toolchain even where an older Go is installed. Needs Go 1.21 or later.
+	// If GoVersion is empty, the toolchain's version is used for the go directive.
+	Toolchain string
+
+	// License to add (e.g. mit), attributed to the Git user.name
+	License string
+
+	// Name to attribute the license to when the Git user.name isn't set
+	Author string
+
+	// Returns the current time, for the year of the license. If nil, the system clock is used.
+	Clock func() time.Time
+
+	// Build and verify a fully static binary (CGO_ENABLED=0, with the netgo and osusergo tags) in CI, the Makefile, and
+	// the Taskfile
+	Static bool
+
+	// Build reproducible binaries (CGO_ENABLED=0, with -trimpath, and with GoReleaser's timestamps taken from
+	// SOURCE_DATE_EPOCH or the commit), and add a CI job that checks two builds are identical
+	Reproducible bool
+
+	// Add an assets directory embedded into the binary with go:embed
+	EmbedAssets bool
+
+	// Add translated messages with golang.org/x/text, generated with go generate
+	I18n bool
+
+	// Feature flags SDK to add, with an environment variable provider: openfeature
+	FeatureFlags string
+
+	// Package layout to add: apiv1, for a public API in api/v1 that a v2 can be added beside
+	Layout string
+
+	// Add a golden file test helper and an example test
+	Golden bool
+
+	// Add a Gremlins mutation testing configuration, with a CI job and a Makefile or Taskfile target
+	Mutation bool
+
+	// Generate NOTICE, attributing the dependencies that I18n or FeatureFlags add, with go-licenses, and add a Makefile
+	// or Taskfile target that regenerates it
+	Notice bool
+
+	// Add a golangci-lint configuration, and run golangci-lint in CI and the Makefile or Taskfile lint target
+	Lint bool
+
+	// Linters enabled by Lint. If empty, the defaults (see DefaultLinters) are.
+	Linters []string
+
+	// Add a default.pgo profile for profile-guided optimization, which builds use
+	PGO bool
+
+	// Add a GoReleaser configuration and release workflow
+	GoReleaser bool
+
+	// Add a Makefile with build, test, lint, fmt, run, and clean targets
+	Make bool
+
+	// Add a Taskfile.yml with the same tasks as Make adds
+	Taskfile bool
+
+	// Platforms to build binaries for (e.g. "linux/amd64", "windows/amd64"), each with cgo disabled, into
+	// bin/dist/<os>_<arch>/ with the Makefile's and Taskfile's dist target (kept apart from GoReleaser's dist/), and with
+	// GoReleaser. Requires Make, Taskfile, or GoReleaser.
+	Targets []string
+
+	// Add script/bootstrap, script/build, script/test, and script/server, which the Makefile, Taskfile, and CI use
+	Scripts bool
+
+	// Add PowerShell equivalents of the Scripts scripts, and also run CI on Windows. Requires Scripts.
+	PowerShell bool
+
+	// Add a script/bootstrap that installs the Go toolchain and tools on a fresh Linux machine
+	BootstrapScript bool
+
+	// Add a Dockerfile and .dockerignore
+	Docker bool
+
+	// Editors to configure (see Editors): vscode, goland, nvim
+	Editors []string
+
+	// Prebuilt cloud development environment to add (see CloudDevEnvironments): gitpod, codespaces
+	CloudDev string
+
+	// Formatter that CI checks formatting with: gofmt or gofumpt. CI also checks that files follow the .editorconfig
+	// that's added. Requires CI.
+	FmtCheck string
+
+	// Configure gitleaks: a config file, CI job, and pre-commit hook, which a Git repository runs from its first commit
+	// (with GitExec, as the built-in implementation runs no hooks)
+	SecretScan bool
+
+	// Add an OpenSSF Scorecard workflow, with its badge in README.md, and a checklist for the OpenSSF Best Practices
+	// badge. Requires a module under github.com.
+	Scorecard bool
+
+	// Create the module as a library, with its command in a separate module in cmd/<name>, which requires the library
+	// through a replace directive, so that the command's dependencies stay out of the library's go.mod. Can't be used
+	// with options that build a binary from the module's root (e.g. Static, Docker, or Make).
+	SplitCmd bool
+
+	// Add example programs that use the library, in a separate module in examples/ that requires it through a replace
+	// directive, and that CI builds. Requires SplitCmd.
+	Examples bool
+
+	// Add an example property-based test of the library, with rapid. Requires SplitCmd.
+	PropTests bool
+
+	// Fail if any code imports packages outside the standard library
+	NoDeps bool
+
+	// Fail if govulncheck finds known vulnerabilities in the dependencies added (or can't check for them), instead of
+	// noting them
+	FailOnVuln bool
+
+	// Run Go commands (e.g. to add dependencies) in a golang container of the Go version, with Docker or Podman,
+	// instead of with the installed toolchain
+	InContainer bool
+
+	// go executable to run Go commands with, instead of the one on PATH (e.g. one returned by ProvisionGo)
+	GoToolchain string
+
+	// Module proxy (GOPROXY) to fetch dependencies from, instead of the one in the user's go env (e.g. a private Athens or
+	// Artifactory proxy)
+	GoProxy string
+
+	// URL of a private module proxy (e.g. Athens or Artifactory) that the module resolves dependencies through
+	// exclusively. Its setup is documented in PRIVATE_PROXY.md, with GOPROXY set in an .envrc and CI, and CI given
+	// placeholders for its credentials. Unless GoProxy is set, dependencies are added through it too.
+	PrivateProxy string
+
+	// Flags the module is created with (e.g. "--ci=github"), recorded in its manifest (.gmc/manifest.json)
+	Flags []string
+
+	// Stages to skip, or to run alone: files, deps, git. At most one can be set.
+	Skip []string
+	Only []string
+
+	// Name without a slash that Module was expanded from (e.g. by adding a configured prefix), which is then noted
+	ExpandedFrom string
+
+	RunOptions
+}
+
+// RunOptions determine how creation is run and reported
+type RunOptions struct {
+	// Describe what would be created, without creating anything or running git or go. Unless GoVersion or Toolchain is
+	// set, the installed Go's version isn't looked up, so the default (1.18) is described.
+	DryRun bool
+
+	// Keep what was created when creation fails partway, instead of removing it
+	KeepPartial bool
+
+	// Where progress is reported. If nil, it isn't.
+	Output io.Writer
+
+	// Report JSON instead of progress
+	JSON bool
+
+	// Output is shown on a terminal, where a spinner shows while a slow step (e.g. adding a dependency) runs
+	Terminal bool
+}
+
+// A UsageError is an invalid option, which gmc reports along with help
+type UsageError struct {
+	Err error
+}
+
+func (e UsageError) Error() string {
+	return e.Err.Error()
+}
+
+func (e UsageError) Unwrap() error {
+	return e.Err
+}
+
+// Create creates a Go module as gmc does. Canceling ctx (e.g. with a timeout) stops creation, kills any command being
+// run, and removes what was created, unless opts.KeepPartial is set.
+func Create(ctx context.Context, opts Options) (*Result, error) {
+	if opts.Archive != "" {
+		return createArchive(ctx, opts)
+	}
+	p, err := planModule(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := runPlan(ctx, p, opts.RunOptions)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create Go module: %s: %w", opts.Module, err)
+	}
+	return r, nil
+}
+
+// CreateModule creates a Go module as Create does, for callers that don't need its Result
+func CreateModule(ctx context.Context, opts Options) error {
+	_, err := Create(ctx, opts)
+	return err
+}
+
+// Plan plans creating a Go module, without creating anything, and returns the plan as JSON to be carried out by Apply
+func Plan(ctx context.Context, opts Options) ([]byte, error) {
+	// The plan is applied later, so it's made as for creating the module now
+	opts.DryRun = false
+	p, err := planModule(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(newPlanFile(p), "", "  ")
+}
+
+// planModule checks opts, and plans creating the module
+func planModule(ctx context.Context, opts Options) (*plan, error) {
+	module := opts.Module
+	err := CheckModulePath(module)
+	if err != nil {
+		return nil, UsageError{err}
+	}
+
+	git := newGitClient(ctx, opts.GitExec)
+	if opts.BundleOnly && (opts.InPlace || opts.Force || opts.Resume) {
+		return nil, UsageError{errors.New("Error: --bundle-only can't be used with init, --force, or --resume")}
+	}
+	if opts.InContainer {
+		if _, err := containerEngine(); err != nil {
+			return nil, err
+		}
+	}
+	var repo *gitRepo
+	if opts.Git || opts.Bundle || opts.BundleOnly {
+		repo = &gitRepo{client: git}
+		if opts.GitInitialBranch != "" {
+			repo.initialBranch = &opts.GitInitialBranch
+		}
+	}
+	if opts.PrivateRemote && opts.PublicRemote {
+		return nil, UsageError{errors.New("Error: --private and --public can't be used together")}
+	}
+	if opts.CreateRemote {
+		if repo == nil {
+			return nil, UsageError{errors.New("Error: --create-remote requires --git")}
+		}
+		if githubRepoForModule(module) == nil {
+			return nil, UsageError{errors.New("Error: --create-remote requires a module under github.com (e.g. github.com/owner/mymodule)")}
+		}
+		// Checked first, so that nothing is created without a way to create the remote. A dry run creates nothing, and
+		// runs no gh to find a token with.
+		if !opts.DryRun {
+			if _, err := githubToken(ctx); err != nil {
+				return nil, wrap(ErrGit, err, "%s", err)
+			}
+		}
+	} else if opts.PrivateRemote || opts.PublicRemote {
+		return nil, UsageError{errors.New("Error: --private and --public require --create-remote")}
+	}
+	if opts.Push {
+		if repo == nil {
+			return nil, UsageError{errors.New("Error: --push requires --git")}
+		}
+		if url, _ := gitRemoteForModule(module, ""); url == "" {
+			return nil, UsageError{errors.New("Error: --push requires a module path that names a remote (e.g. github.com/owner/mymodule)")}
+		}
+	}
+	remoteProtocol := strings.ToLower(opts.RemoteProtocol)
+	if remoteProtocol == "" {
+		remoteProtocol = remoteProtocolSSH
+	} else if remoteProtocol != remoteProtocolSSH && remoteProtocol != remoteProtocolHTTPS {
+		return nil, UsageError{errors.New(fmt.Sprintf("Error: Unsupported remote protocol: %s (supported: %s)", opts.RemoteProtocol, strings.Join(RemoteProtocols(), ", ")))}
+	}
+	// git runs the secret scan's pre-commit hook on the initial commit, which fails without gitleaks
+	if opts.SecretScan && repo != nil && opts.GitExec {
+		if _, err := exec.LookPath("gitleaks"); err != nil {
+			return nil, wrap(ErrGit, err, "gitleaks not found to scan the initial commit for secrets (install gitleaks: https://github.com/gitleaks/gitleaks#installing)")
+		}
+	}
+	var extraDirs []string
+	var editor string
+	for _, name := range opts.Editors {
+		extra, ok := editorExtras[strings.ToLower(name)]
+		if !ok {
+			return nil, UsageError{errors.New(fmt.Sprintf("Error: Unsupported editor: %s (supported: %s)", name, strings.Join(editorExtraNames, ", ")))}
+		}
+		extraDirs = append(extraDirs, strings.ToLower(name))
+		editor = extra.command
+	}
+	featureFlags := strings.ToLower(opts.FeatureFlags)
+	if _, ok := featureFlagsDependencies[featureFlags]; featureFlags != "" && !ok {
+		return nil, UsageError{errors.New(fmt.Sprintf("Error: Unsupported feature flags SDK: %s (supported: openfeature)", featureFlags))}
+	}
+	cloudDev := strings.ToLower(opts.CloudDev)
+	if cloudDev != "" {
+		supported := false
+		for _, name := range cloudDevEnvironments {
+			if name == cloudDev {
+				supported = true
+			}
+		}
+		if !supported {
+			return nil, UsageError{errors.New(fmt.Sprintf("Error: Unsupported cloud development environment: %s (supported: %s)", cloudDev, strings.Join(cloudDevEnvironments, ", ")))}
+		}
+		extraDirs = append(extraDirs, "cloud-dev-"+cloudDev)
+	}
+	if opts.PowerShell && !opts.Scripts {
+		return nil, UsageError{errors.New("Error: --powershell requires --scripts")}
+	}
+	if opts.FullPath && opts.InPlace {
+		return nil, UsageError{errors.New("Error: --full-path can't be used with init")}
+	}
+	fmtCheck := strings.ToLower(opts.FmtCheck)
+	if _, ok := formatterCommands[fmtCheck]; fmtCheck != "" && !ok {
+		return nil, UsageError{errors.New(fmt.Sprintf("Error: Unsupported formatter: %s (supported: %s)", opts.FmtCheck, strings.Join(Formatters(), ", ")))}
+	}
+	if fmtCheck != "" && opts.CI == "" {
+		return nil, UsageError{errors.New("Error: --fmt-check requires --ci")}
+	}
+	layout := strings.ToLower(opts.Layout)
+	if _, ok := layoutAssetSets[layout]; layout != "" && !ok {
+		return nil, UsageError{errors.New(fmt.Sprintf("Error: Unsupported layout: %s (supported: %s)", opts.Layout, strings.Join(Layouts(), ", ")))}
+	}
+	if opts.Notice && !opts.I18n && opts.FeatureFlags == "" {
+		return nil, UsageError{errors.New("Error: --notice requires an option that adds dependencies (--i18n or --feature-flags)")}
+	}
+	if opts.Examples && !opts.SplitCmd {
+		return nil, UsageError{errors.New("Error: --examples requires --split-cmd")}
+	}
+	if opts.PropTests && !opts.SplitCmd {
+		return nil, UsageError{errors.New("Error: --prop-tests requires --split-cmd")}
+	}
+	if opts.SplitCmd {
+		for _, conflict := range []struct {
+			set  bool
+			flag string
+		}{
+			{opts.Static, "--static"},
+			{opts.Reproducible, "--reproducible"},
+			{len(opts.Targets) > 0, "--targets"},
+			{opts.PGO, "--pgo"},
+			{opts.Docker, "--docker"},
+			{opts.GoReleaser, "--goreleaser"},
+			{opts.Make, "--make"},
+			{opts.Taskfile, "--taskfile"},
+			{opts.Scripts, "--scripts"},
+			{opts.EmbedAssets, "--embed-assets"},
+			{opts.I18n, "--i18n"},
+			{opts.FeatureFlags != "", "--feature-flags"},
+			{opts.Golden, "--golden"},
+		} {
+			if conflict.set {
+				return nil, UsageError{errors.New(fmt.Sprintf("Error: --split-cmd can't be used with %s, which builds the module's root package", conflict.flag))}
+			}
+		}
+	}
+	if opts.Scorecard && githubRepoForModule(module) == nil {
+		return nil, UsageError{errors.New("Error: --scorecard requires a module under github.com (e.g. github.com/owner/mymodule)")}
+	}
+	if len(opts.Skip) > 0 && len(opts.Only) > 0 {
+		return nil, UsageError{errors.New("Error: --skip and --only can't be used together")}
+	}
+	for _, name := range append(append([]string{}, opts.Skip...), opts.Only...) {
+		known := false
+		for _, stageName := range stageNames {
+			if stageName == name {
+				known = true
+			}
+		}
+		if !known {
+			return nil, UsageError{errors.New(fmt.Sprintf("Error: Unknown stage: %s (stages: %s)", name, strings.Join(stageNames, ", ")))}
+		}
+	}
+	if opts.GoVersion != "" && !goVersionRegexp.MatchString(opts.GoVersion) {
+		return nil, UsageError{errors.New(fmt.Sprintf("Error: Invalid Go version: %s (e.g. 1.21 or 1.21.3)", opts.GoVersion))}
+	}
+	toolchain, err := parseToolchain(opts.Toolchain, opts.GoVersion)
+	if err != nil {
+		return nil, UsageError{err}
+	}
+	targets, err := parseTargets(opts.Targets)
+	if err != nil {
+		return nil, UsageError{err}
+	}
+	if len(targets) > 0 && !opts.Make && !opts.Taskfile && !opts.GoReleaser {
+		return nil, UsageError{errors.New("Error: --targets requires --make, --taskfile, or --goreleaser")}
+	}
+	var privateProxy *url.URL
+	if opts.PrivateProxy != "" {
+		privateProxy, err = url.Parse(opts.PrivateProxy)
+		if err != nil || (privateProxy.Scheme != "https" && privateProxy.Scheme != "http") || privateProxy.Host == "" {
+			return nil, UsageError{errors.New(fmt.Sprintf("Error: Invalid private proxy: %s (e.g. https://athens.example.com)", opts.PrivateProxy))}
+		}
+	}
+	var ci ciProvider
+	if opts.CI != "" {
+		ci, err = selectCiProvider(opts.CI, module)
+		if err != nil {
+			return nil, UsageError{err}
+		}
+	}
+	var moduleLicense *license
+	if opts.License != "" {
+		licenseId, err := parseLicenseId(opts.License)
+		if err != nil {
+			return nil, UsageError{err}
+		}
+		author, err := licenseAuthor(git, opts.Author, opts.DryRun)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create Go module: %s: %w", module, err)
+		}
+		moduleLicense = &license{
+			id:     licenseId,
+			author: author,
+			year:   now(opts.Clock).Year(),
+		}
+	}
+	var linters []string
+	if opts.Lint {
+		linters = opts.Linters
+		if len(linters) == 0 {
+			linters = defaultLinters
+		}
+	}
+
+	// Resolve where the module goes
+	outputDir := ""
+	if opts.OutputDir != "" {
+		outputDir, err = resolveOutputDir(opts.OutputDir)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create Go module: %s: %w", module, err)
+		}
+	}
+	dir := ""
+	if opts.InPlace {
+		dir = "."
+		if outputDir != "" {
+			dir = outputDir
+		}
+	}
+
+	// Plan module
+	p, err := newPlan(ctx, module, planOptions{
+		shortName:      opts.ExpandedFrom,
+		dir:            dir,
+		outputDir:      outputDir,
+		fullPath:       opts.FullPath,
+		force:          opts.Force,
+		resume:         opts.Resume,
+		dryRun:         opts.DryRun,
+		repo:           repo,
+		createRemote:   opts.CreateRemote,
+		push:           opts.Push,
+		publicRemote:   opts.PublicRemote,
+		remoteProtocol: remoteProtocol,
+		bundle:         opts.Bundle,
+		bundleOnly:     opts.BundleOnly,
+		inContainer:    opts.InContainer,
+		goToolchain:    opts.GoToolchain,
+		goProxy:        opts.GoProxy,
+		flags:          opts.Flags,
+		extraDirs:      extraDirs,
+		ci:             ci,
+		license:        moduleLicense,
+		static:         opts.Static,
+		reproducible:   opts.Reproducible,
+		pgo:            opts.PGO,
+		goreleaser:     opts.GoReleaser,
+		targets:        targets,
+		docker:         opts.Docker,
+		embedAssets:    opts.EmbedAssets,
+		i18n:           opts.I18n,
+		featureFlags:   featureFlags,
+		layout:         layout,
+		golden:         opts.Golden,
+		mutation:       opts.Mutation,
+		notice:         opts.Notice,
+		secretScan:     opts.SecretScan,
+		scorecard:      opts.Scorecard,
+		splitCmd:       opts.SplitCmd,
+		examples:       opts.Examples,
+		propTests:      opts.PropTests,
+		fmtCheck:       fmtCheck,
+		make:           opts.Make,
+		taskfile:       opts.Taskfile,
+		scripts:        opts.Scripts,
+		powershell:     opts.PowerShell,
+		bootstrap:      opts.BootstrapScript,
+		goVersion:      opts.GoVersion,
+		toolchain:      toolchain,
+		privateProxy:   privateProxy,
+		linters:        linters,
+		editor:         editor,
+		failOnVuln:     opts.FailOnVuln,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create Go module: %s: %w", module, err)
+	}
+	if opts.NoDeps {
+		err = p.checkStdlibOnly()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create Go module: %s: %w", module, err)
+		}
+	}
+
+	p.selectStages(opts.Skip, opts.Only)
+	return p, nil
+}
+
+// runPlan executes (or with DryRun, describes) a plan, reporting progress or JSON as opts request
+func runPlan(ctx context.Context, p *plan, opts RunOptions) (*Result, error) {
+	output := opts.Output
+	if output == nil {
+		output = io.Discard
+	}
+	quiet := opts.Output == nil || opts.JSON
+
+	var r *Result
+	if opts.DryRun {
+		r = p.describe(output, quiet)
+	} else {
+		var err error
+		// Traced commands would be written over the spinner
+		spinning := opts.Terminal && !quiet && ctx.Value(traceKey{}) == nil
+		r, err = p.execute(ctx, output, quiet, opts.KeepPartial, spinning)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.JSON && opts.Output != nil {
+		return r, r.write(output)
+	}
+	return r, nil
+}
+
+// resolveOutputDir returns the absolute path of an existing directory to create modules in
+func resolveOutputDir(dir string) (string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(absDir)
+	if err != nil || !info.IsDir() {
+		return "", errors.New(fmt.Sprintf("Output directory not found: %s", absDir))
+	}
+	return absDir, nil
+}
+
+// now returns the current time from clock, or the system clock if clock is nil
+func now(clock func() time.Time) time.Time {
+	if clock == nil {
+		return time.Now()
+	}
+	return clock()
+}
+
+func flogf(output io.Writer, quiet bool, format string, a ...any) {
+	if !quiet {
+		fmt.Fprintf(output, format, a...)
+	}
+}
+
+func flogln(output io.Writer, quiet bool, a ...any) {
+	if !quiet {
+		fmt.Fprintln(output, a...)
+	}
+}
+
+func reportAtPath(output io.Writer, quiet bool, color string, verb string, fileType string, filePath string) {
+	flogf(output, quiet, "- %s: %s\n", colored(output, color, fmt.Sprintf("%s %-9s", verb, fileType)), filePath)
+}
+
+func reportCreatedDir(output io.Writer, quiet bool, filePath string) {
+	reportAtPath(output, quiet, colorGreen, "Created", "directory", filePath)
+}
+
+func reportCreatedFile(output io.Writer, quiet bool, filePath string) {
+	reportAtPath(output, quiet, colorGreen, "Created", "file", filePath)
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func withoutFilepathPrefix(filePath string, filePathPrefix string) string {
+	filePathPrefixWithSeparator := filePathPrefix + string(filepath.Separator)
+	return strings.TrimPrefix(filePath, filePathPrefixWithSeparator)
+}
+
+// CheckModulePath returns an error explaining why a module name can't be used as a module path
+func CheckModulePath(modulePath string) error {
+	check := module.CheckImportPath
+	if strings.Contains(strings.Split(modulePath, "/")[0], ".") {
+		// Paths that name a host (e.g. github.com/...) must also be fetchable with `go get`
+		check = module.CheckPath
+	}
+	err := check(modulePath)
+	var pathErr *module.InvalidPathError
+	if errors.As(err, &pathErr) {
+		return wrap(ErrInvalidModulePath, err, "Error: Invalid module name: %s: %s", modulePath, pathErr.Err)
+	}
+	return err
+}
+
+func getVersion() string {
+	info, _ := debug.ReadBuildInfo()
+	version := info.Main.Version
+	if version == "" {
+		version = "(devel)"
+	}
+	return version
+}
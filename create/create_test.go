@@ -0,0 +1,1785 @@
+package create_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/jbrudvik/gmc/create"
+)
+
+// TestMain gives Git a global config with a test user, so that the tests don't depend on the config of the machine
+// they run on (which may set no user at all)
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "gmc-test")
+	if err != nil {
+		panic(err)
+	}
+	gitConfig := filepath.Join(dir, "gitconfig")
+	err = os.WriteFile(gitConfig, []byte("[user]\n\tname = Test Gopher\n\temail = gopher@example.com\n"), 0644)
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("GIT_CONFIG_GLOBAL", gitConfig)
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func TestCreate(t *testing.T) {
+	chdirTemp(t)
+
+	r, err := create.Create(context.Background(), create.Options{Module: "a1", Git: true, GitInitialBranch: "main"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedFiles := []string{"a1/main.go", "a1/main_test.go", "a1/.gitignore", "a1/README.md", "a1/.gmc/manifest.json"}
+	if strings.Join(r.CreatedFiles, " ") != strings.Join(expectedFiles, " ") {
+		t.Error(unexpectedMessage("created files", expectedFiles, r.CreatedFiles))
+	}
+	goMod, err := os.ReadFile(filepath.Join("a1", "go.mod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedGoMod := fmt.Sprintf("module a1\n\ngo %s\n", localGoVersion(t))
+	if string(goMod) != expectedGoMod {
+		t.Error(unexpectedMessage("go.mod", expectedGoMod, string(goMod)))
+	}
+	if _, err := os.Stat(filepath.Join("a1", ".git")); err != nil {
+		t.Error("Git repository was not created: a1/.git")
+	}
+
+	// Canceled before anything is created
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = create.Create(ctx, create.Options{Module: "a2"})
+	expectedError := "Failed to create Go module: a2: Interrupted"
+	if fmt.Sprint(err) != expectedError {
+		t.Error(unexpectedMessage("error", expectedError, fmt.Sprint(err)))
+	}
+	if !errors.Is(err, create.ErrInterrupted) {
+		t.Error("Error is not create.ErrInterrupted")
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Error("Error is not context.Canceled")
+	}
+	if _, err := os.Stat("a2"); !errors.Is(err, fs.ErrNotExist) {
+		t.Error("Directory was created when none was expected: a2")
+	}
+
+	// Timed out before anything is created
+	ctx, cancel = context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	_, err = create.Create(ctx, create.Options{Module: "a2"})
+	if !errors.Is(err, create.ErrTimedOut) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Error(unexpectedMessage("error", "create.ErrTimedOut, from context.DeadlineExceeded", fmt.Sprint(err)))
+	}
+
+	// Without a Result
+	err = create.CreateModule(context.Background(), create.Options{Module: "a4"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join("a4", "go.mod")); err != nil {
+		t.Error("Module was not created: a4")
+	}
+
+	// Invalid options
+	_, err = create.Create(context.Background(), create.Options{Module: "a3", PowerShell: true})
+	var usage create.UsageError
+	if !errors.As(err, &usage) {
+		t.Error(unexpectedMessage("error", "create.UsageError", fmt.Sprintf("%T", err)))
+	}
+}
+
+func TestTrace(t *testing.T) {
+	chdirTemp(t)
+	setGoEnv(t)
+
+	var trace strings.Builder
+	ctx := create.WithTrace(context.Background(), &trace)
+	_, err := create.Create(ctx, create.Options{Module: "a1", Git: true, GitInitialBranch: "main"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	actualTrace := regexp.MustCompile(`, \d+(\.\d+)?m?s\)`).ReplaceAllString(trace.String(), ", 1ms)")
+	expectedTrace := "+ go env GOVERSION (in ., 1ms)\n" +
+		"+ go env GOFLAGS GOPROXY GOPRIVATE GONOPROXY GONOSUMDB GOSUMDB GOINSECURE (in ., 1ms)\n" +
+		"    GOFLAGS=-mod=mod\n" +
+		"    GOPROXY=https://proxy.golang.org,direct\n" +
+		"    GOPRIVATE=example.com/private\n" +
+		"    GONOPROXY=example.com/private\n" +
+		"    GONOSUMDB=example.com/private\n" +
+		"    GOSUMDB=sum.golang.org\n" +
+		"    GOINSECURE=\n" +
+		"+ (built-in) git init --initial-branch main (in a1, 1ms)\n" +
+		"+ (built-in) git commit -a -m \"Initial commit\" (in a1, 1ms)\n"
+	if actualTrace != expectedTrace {
+		t.Error(unexpectedMessage("trace", expectedTrace, actualTrace))
+	}
+}
+
+func TestGoProxy(t *testing.T) {
+	chdirTemp(t)
+	setGoEnv(t)
+
+	var trace strings.Builder
+	ctx := create.WithTrace(context.Background(), &trace)
+	_, err := create.Create(ctx, create.Options{Module: "a1", GoProxy: "off", I18n: true})
+	if err == nil {
+		t.Fatal("Dependency was added with GOPROXY=off")
+	}
+	for _, expected := range []string{
+		"+ GOPROXY=off go env GOFLAGS",
+		"    GOPROXY=off\n",
+		"+ GOPROXY=off go get golang.org/x/text",
+	} {
+		if !strings.Contains(trace.String(), expected) {
+			t.Error(unexpectedMessage("trace line", expected, trace.String()))
+		}
+	}
+}
+
+func TestCheckVulns(t *testing.T) {
+	chdirTemp(t)
+	if runtime.GOOS == "windows" {
+		t.Skip("go is stubbed with a shell script")
+	}
+
+	// A go that adds dependencies without doing anything, and whose govulncheck finds a vulnerability the module
+	// calls into, and one it doesn't
+	goExecutable, err := filepath.Abs("go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	script := "#!/bin/sh\n" +
+		"[ \"$1\" = run ] || exit 0\n" +
+		"[ -e fail ] && exit 1\n" +
+		"echo '{\"config\": {\"protocol_version\": \"v1.0.0\"}}'\n" +
+		"echo '{\"finding\": {\"osv\": \"GO-2099-0001\", \"trace\": [{\"module\": \"golang.org/x/text\", \"function\": \"Parse\"}]}}'\n" +
+		"echo '{\"finding\": {\"osv\": \"GO-2099-0002\", \"trace\": [{\"module\": \"golang.org/x/text\"}]}}'\n"
+	err = os.WriteFile(goExecutable, []byte(script), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Noted by default
+	r, err := create.Create(context.Background(), create.Options{Module: "a1", GoVersion: "1.22", GoToolchain: goExecutable, I18n: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "Dependencies have known vulnerabilities: GO-2099-0001 (see `govulncheck ./...`)"; strings.Join(r.Notes, "\n") != expected {
+		t.Error(unexpectedMessage("notes", expected, strings.Join(r.Notes, "\n")))
+	}
+
+	// Fatal with FailOnVuln, leaving nothing behind
+	_, err = create.Create(context.Background(), create.Options{Module: "a2", GoVersion: "1.22", GoToolchain: goExecutable, I18n: true, FailOnVuln: true})
+	if err == nil || !strings.Contains(err.Error(), "GO-2099-0001") || strings.Contains(err.Error(), "GO-2099-0002") {
+		t.Error(unexpectedMessage("error", "Dependencies have known vulnerabilities: GO-2099-0001", fmt.Sprint(err)))
+	}
+	if _, err := os.Stat("a2"); !errors.Is(err, fs.ErrNotExist) {
+		t.Error(unexpectedMessage("a2", "removed", fmt.Sprint(err)))
+	}
+
+	// A check that can't run is noted, unless FailOnVuln
+	for _, name := range []string{"a3/fail", "a4/fail"} {
+		err = os.MkdirAll(name, 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	r, err = create.Create(context.Background(), create.Options{Module: "a3", GoVersion: "1.22", GoToolchain: goExecutable, I18n: true, Force: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "Unable to check dependencies for known vulnerabilities"; len(r.Notes) != 1 || !strings.HasPrefix(r.Notes[0], expected) {
+		t.Error(unexpectedMessage("notes", expected, strings.Join(r.Notes, "\n")))
+	}
+	_, err = create.Create(context.Background(), create.Options{Module: "a4", GoVersion: "1.22", GoToolchain: goExecutable, I18n: true, Force: true, FailOnVuln: true})
+	if err == nil || !strings.Contains(err.Error(), "Failed to check dependencies for known vulnerabilities") {
+		t.Error(unexpectedMessage("error", "Failed to check dependencies for known vulnerabilities", fmt.Sprint(err)))
+	}
+}
+
+func TestNotice(t *testing.T) {
+	chdirTemp(t)
+	if runtime.GOOS == "windows" {
+		t.Skip("go is stubbed with a shell script")
+	}
+
+	// A go that adds dependencies without doing anything, and whose go-licenses reports how it was run
+	goExecutable, err := filepath.Abs("go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	script := "#!/bin/sh\n" +
+		"[ \"$2\" = github.com/google/go-licenses@latest ] || exit 0\n" +
+		"echo \"$@\"\n"
+	err = os.WriteFile(goExecutable, []byte(script), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := create.Create(context.Background(), create.Options{Module: "example.com/a1", GoVersion: "1.22", GoToolchain: goExecutable, I18n: true, Notice: true, Make: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "run github.com/google/go-licenses@latest report ./... --template NOTICE.tpl --ignore example.com/a1\n"
+	if actual, err := os.ReadFile(filepath.Join("a1", "NOTICE")); string(actual) != expected {
+		t.Error(unexpectedMessage("NOTICE", expected, fmt.Sprint(string(actual), err)))
+	}
+	for _, name := range []string{filepath.Join("a1", "NOTICE.tpl"), filepath.Join("a1", "NOTICE")} {
+		if !strings.Contains(strings.Join(r.CreatedFiles, "\n"), name) {
+			t.Error(unexpectedMessage("created files", name, strings.Join(r.CreatedFiles, "\n")))
+		}
+	}
+	makefile, err := os.ReadFile(filepath.Join("a1", "Makefile"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = "licenses:\n\tgo run github.com/google/go-licenses@latest report ./... --template NOTICE.tpl --ignore example.com/a1 > NOTICE\n"
+	if !strings.Contains(string(makefile), expected) {
+		t.Error(unexpectedMessage("Makefile", expected, string(makefile)))
+	}
+
+	// There's nothing to attribute without dependencies
+	_, err = create.Create(context.Background(), create.Options{Module: "a2", GoVersion: "1.22", GoToolchain: goExecutable, Notice: true})
+	expected = "Error: --notice requires an option that adds dependencies (--i18n or --feature-flags)"
+	if err == nil || err.Error() != expected {
+		t.Error(unexpectedMessage("error", expected, fmt.Sprint(err)))
+	}
+}
+
+// setGoEnv sets the go env settings that are traced to known values
+func setGoEnv(t *testing.T) {
+	t.Setenv("GOENV", "off")
+	t.Setenv("GOFLAGS", "-mod=mod")
+	t.Setenv("GOPROXY", "https://proxy.golang.org,direct")
+	t.Setenv("GOPRIVATE", "example.com/private")
+	t.Setenv("GONOPROXY", "")
+	t.Setenv("GONOSUMDB", "")
+	t.Setenv("GOSUMDB", "sum.golang.org")
+	t.Setenv("GOINSECURE", "")
+}
+
+func TestLog(t *testing.T) {
+	chdirTemp(t)
+
+	var log strings.Builder
+	ctx := create.WithLog(context.Background(), &log)
+	_, err := create.Create(ctx, create.Options{Module: "a1", Git: true, GitInitialBranch: "main"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Each line starts with a timestamp
+	timestamp := regexp.MustCompile(`(?m)^\d{4}-\d\d-\d\dT\d\d:\d\d:\d\d\.\d{3}(Z|[+-]\d\d:\d\d) `)
+	if lines := strings.Count(log.String(), "\n"); len(timestamp.FindAllString(log.String(), -1)) != lines {
+		t.Error(unexpectedMessage("timestamped lines", fmt.Sprint(lines), log.String()))
+	}
+	actualLog := timestamp.ReplaceAllString(log.String(), "")
+	actualLog = regexp.MustCompile(`, \d+(\.\d+)?m?s\)`).ReplaceAllString(actualLog, ", 1ms)")
+	for _, expected := range []string{
+		"+ go env GOVERSION (in ., 1ms)\n",
+		"Creating Go module: a1 (in a1)\n",
+		"Step done: createFile a1/main.go\n",
+		"+ (built-in) git init --initial-branch main (in a1, 1ms)\n",
+		"Step done: initGitRepo\n",
+		"Finished creating Go module: a1\n",
+	} {
+		if !strings.Contains(actualLog, expected) {
+			t.Error(unexpectedMessage("log line", expected, actualLog))
+		}
+	}
+}
+
+func TestSpinner(t *testing.T) {
+	chdirTemp(t)
+
+	var output strings.Builder
+	_, err := create.Create(context.Background(), create.Options{
+		Module:     "a1",
+		Git:        true,
+		RunOptions: create.RunOptions{Output: &output, Terminal: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedSpinner := "\r⠋ Committing all files to Git repository"
+	if !strings.Contains(output.String(), expectedSpinner) {
+		t.Error(unexpectedMessage("output", "containing "+expectedSpinner, output.String()))
+	}
+	expectedLine := "\r\x1b[2K- Committed all files to Git repository\n"
+	if !strings.Contains(output.String(), expectedLine) {
+		t.Error(unexpectedMessage("output", "containing "+expectedLine, output.String()))
+	}
+
+	// Plain lines when output isn't a terminal
+	output.Reset()
+	_, err = create.Create(context.Background(), create.Options{Module: "a2", Git: true, RunOptions: create.RunOptions{Output: &output}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(output.String(), "\r") {
+		t.Error(unexpectedMessage("output", "without a spinner", output.String()))
+	}
+}
+
+func TestArchive(t *testing.T) {
+	chdirTemp(t)
+
+	r, err := create.Create(context.Background(), create.Options{Module: "a1", Archive: "a1.zip"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedFiles := []string{"a1/main.go", "a1/main_test.go", "a1/.gitignore", "a1/.gmc/manifest.json"}
+	if strings.Join(r.CreatedFiles, " ") != strings.Join(expectedFiles, " ") {
+		t.Error(unexpectedMessage("created files", expectedFiles, r.CreatedFiles))
+	}
+	zr, err := zip.OpenReader("a1.zip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+	names := []string{}
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	expectedNames := []string{"a1/", "a1/.gitignore", "a1/.gmc/", "a1/.gmc/manifest.json", "a1/go.mod", "a1/main.go", "a1/main_test.go"}
+	if strings.Join(names, " ") != strings.Join(expectedNames, " ") {
+		t.Error(unexpectedMessage("archived files", expectedNames, names))
+	}
+	if _, err := os.Stat("a1"); !errors.Is(err, fs.ErrNotExist) {
+		t.Error("Directory was created when none was expected: a1")
+	}
+
+	// Archive already exists
+	_, err = create.Create(context.Background(), create.Options{Module: "a1", Archive: "a1.zip"})
+	if !errors.Is(err, create.ErrArchiveExists) {
+		t.Error(unexpectedMessage("error", create.ErrArchiveExists, err))
+	}
+
+	// Streamed as a tarball
+	var stream bytes.Buffer
+	_, err = create.Create(context.Background(), create.Options{Module: "a2", Archive: "-", ArchiveOutput: &stream})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(&stream)
+	names = []string{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, header.Name)
+	}
+	expectedNames = []string{"a2/", "a2/.gitignore", "a2/.gmc/", "a2/.gmc/manifest.json", "a2/go.mod", "a2/main.go", "a2/main_test.go"}
+	if strings.Join(names, " ") != strings.Join(expectedNames, " ") {
+		t.Error(unexpectedMessage("streamed files", expectedNames, names))
+	}
+
+	// No Git repository in an archive
+	_, err = create.Create(context.Background(), create.Options{Module: "a2", Archive: "a2.tar.gz", Git: true})
+	var usage create.UsageError
+	if !errors.As(err, &usage) {
+		t.Error(unexpectedMessage("error", "create.UsageError", fmt.Sprintf("%T", err)))
+	}
+}
+
+func TestManifest(t *testing.T) {
+	chdirTemp(t)
+
+	_, err := create.Create(context.Background(), create.Options{Module: "a1", Make: true, Flags: []string{"--make"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(filepath.Join("a1", ".gmc", "manifest.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var manifest struct {
+		GmcVersion string   `json:"gmcVersion"`
+		Module     string   `json:"module"`
+		Flags      []string `json:"flags"`
+		Templates  []string `json:"templates"`
+		Files      []struct {
+			Path     string `json:"path"`
+			SHA256   string `json:"sha256"`
+			Template string `json:"template"`
+		} `json:"files"`
+	}
+	err = json.Unmarshal(content, &manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifest.GmcVersion != create.Version || manifest.Module != "a1" {
+		t.Error(unexpectedMessage("version and module", create.Version+" a1", manifest.GmcVersion+" "+manifest.Module))
+	}
+	if strings.Join(manifest.Flags, " ") != "--make" || strings.Join(manifest.Templates, " ") != "default make" {
+		t.Error(unexpectedMessage("flags and templates", "[--make] [default make]", fmt.Sprint(manifest.Flags, manifest.Templates)))
+	}
+
+	// Every file but go.mod is recorded, with the checksum of what was written
+	paths := []string{}
+	for _, f := range manifest.Files {
+		paths = append(paths, f.Path+"@"+f.Template)
+		fileContent, err := os.ReadFile(filepath.Join("a1", f.Path))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if checksum := sha256.Sum256(fileContent); hex.EncodeToString(checksum[:]) != f.SHA256 {
+			t.Error(unexpectedMessage("checksum of "+f.Path, hex.EncodeToString(checksum[:]), f.SHA256))
+		}
+	}
+	expectedPaths := "main.go@default/main.go main_test.go@default/main_test.go Makefile@make/Makefile.tmpl .gitignore@"
+	if strings.Join(paths, " ") != expectedPaths {
+		t.Error(unexpectedMessage("files", expectedPaths, strings.Join(paths, " ")))
+	}
+}
+
+func TestExecutableTest(t *testing.T) {
+	chdirTemp(t)
+	setGoEnv(t)
+
+	// The generated main_test.go builds and runs the generated main.go
+	_, err := create.Create(context.Background(), create.Options{Module: "a1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command("go", "test", "-run", "TestExecutable", ".")
+	cmd.Dir = "a1"
+	cmd.Env = append(os.Environ(), "GOFLAGS=-buildvcs=false")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Errorf("Generated executable test failed: %s\n%s", err, output)
+	}
+
+	// An existing main.go is kept, and isn't tested as if it were generated
+	err = os.Mkdir("a2", 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join("a2", "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = create.Create(context.Background(), create.Options{Module: "a2", Force: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join("a2", "main_test.go")); !errors.Is(err, fs.ErrNotExist) {
+		t.Error("File was created when none was expected: a2/main_test.go")
+	}
+}
+
+func TestRerun(t *testing.T) {
+	chdirTemp(t)
+
+	// Re-creating a module with the same options does nothing, whether or not it's a Git repository
+	for _, opts := range []create.Options{{Module: "a1"}, {Module: "a2", Git: true}} {
+		_, err := create.Create(context.Background(), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r, err := create.Create(context.Background(), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := fmt.Sprintf("Already created with these options: %s (nothing to do)", opts.Module)
+		if notes := strings.Join(r.Notes, "\n"); notes != expected || len(r.GitActions) != 0 {
+			t.Error(unexpectedMessage("notes for "+opts.Module, expected, notes))
+		}
+	}
+
+	// With other options, the directory is in the way
+	_, err := create.Create(context.Background(), create.Options{Module: "a2"})
+	if !errors.Is(err, create.ErrDirExists) {
+		t.Error(unexpectedMessage("error", create.ErrDirExists, err))
+	}
+}
+
+func TestBundle(t *testing.T) {
+	chdirTemp(t)
+
+	r, err := create.Create(context.Background(), create.Options{Module: "a1", GitInitialBranch: "main", BundleOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Bundle != "a1.bundle" {
+		t.Error(unexpectedMessage("bundle", "a1.bundle", r.Bundle))
+	}
+	if _, err := os.Stat("a1"); !errors.Is(err, fs.ErrNotExist) {
+		t.Error("Directory was kept when it was expected to be removed: a1")
+	}
+	bundle, err := os.ReadFile("a1.bundle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := regexp.MustCompile(`^# v2 git bundle\n[0-9a-f]{40} HEAD\n[0-9a-f]{40} refs/heads/main\n\nPACK`)
+	if !header.Match(bundle) {
+		t.Error(unexpectedMessage("bundle header", header.String(), strings.SplitN(string(bundle), "PACK", 2)[0]))
+	}
+
+	// Cloneable
+	cmd := exec.Command("git", "clone", "--quiet", "a1.bundle", "a2")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Unable to clone bundle: %s: %s", err, output)
+	}
+	if _, err := os.Stat(filepath.Join("a2", "main.go")); err != nil {
+		t.Error("Clone of bundle is missing main.go")
+	}
+
+	_, err = create.Create(context.Background(), create.Options{Module: "a3", InPlace: true, BundleOnly: true})
+	var usage create.UsageError
+	if !errors.As(err, &usage) {
+		t.Error(unexpectedMessage("error", "create.UsageError", fmt.Sprintf("%T", err)))
+	}
+}
+
+func TestFullPath(t *testing.T) {
+	chdirTemp(t)
+
+	// Parent directories that already exist (e.g. in a GOPATH) are kept, and those that don't are created
+	err := os.Mkdir("example.com", 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := create.Create(context.Background(), create.Options{Module: "example.com/owner/mymodule", FullPath: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedDirs := []string{"example.com/owner", "example.com/owner/mymodule", "example.com/owner/mymodule/.gmc"}
+	if strings.Join(r.CreatedDirectories, " ") != strings.Join(expectedDirs, " ") {
+		t.Error(unexpectedMessage("created directories", expectedDirs, r.CreatedDirectories))
+	}
+	if _, err := os.Stat(filepath.Join("example.com", "owner", "mymodule", "go.mod")); err != nil {
+		t.Error(err)
+	}
+
+	// In the output directory
+	err = os.Mkdir("out", 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = create.Create(context.Background(), create.Options{Module: "example.com/owner/other", FullPath: true, OutputDir: "out"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join("out", "example.com", "owner", "other", "go.mod")); err != nil {
+		t.Error(err)
+	}
+
+	_, err = create.Create(context.Background(), create.Options{Module: "example.com/owner/mymodule", FullPath: true, InPlace: true})
+	var usage create.UsageError
+	if !errors.As(err, &usage) {
+		t.Error(unexpectedMessage("error", "create.UsageError", fmt.Sprintf("%T", err)))
+	}
+}
+
+func TestCreateErrors(t *testing.T) {
+	chdirTemp(t)
+	err := os.Mkdir("a1", 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join("a1", "notes.txt"), []byte("notes"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		opts     create.Options
+		expected []error
+	}{
+		{create.Options{Module: "a1"}, []error{create.ErrDirExists}},
+		{create.Options{Module: "a2/"}, []error{create.ErrInvalidModulePath}},
+		{create.Options{Module: "a3", InPlace: true, OutputDir: "a1", Resume: true}, []error{create.ErrNoModule}},
+		{create.Options{Module: "a4", Git: true}, []error{create.ErrGit, create.ErrGitNotConfigured}},
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull) // Automatically reset
+	for _, tc := range tests {
+		_, err := create.Create(context.Background(), tc.opts)
+		for _, expected := range tc.expected {
+			if !errors.Is(err, expected) {
+				t.Error(unexpectedMessage("error for "+tc.opts.Module, fmt.Sprintf("%q", expected), fmt.Sprintf("%q", err)))
+			}
+		}
+	}
+}
+
+func TestInContainerWithoutEngine(t *testing.T) {
+	chdirTemp(t)
+	t.Setenv("PATH", t.TempDir()) // Neither Docker nor Podman
+
+	_, err := create.Create(context.Background(), create.Options{Module: "a1", InContainer: true})
+	if !errors.Is(err, create.ErrGoToolchainMissing) {
+		t.Error(unexpectedMessage("error", create.ErrGoToolchainMissing, err))
+	}
+	if _, err := os.Stat("a1"); !errors.Is(err, fs.ErrNotExist) {
+		t.Error("Directory was created without a container engine: a1")
+	}
+}
+
+func TestToolchain(t *testing.T) {
+	chdirTemp(t)
+
+	_, err := create.Create(context.Background(), create.Options{Module: "a1", Toolchain: "go1.21.4", CI: "github", Docker: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The go directive follows the toolchain, which every place that builds the module is pinned to
+	expectedContents := map[string]string{
+		"go.mod":                   "go 1.21\n\ntoolchain go1.21.4\n",
+		".envrc":                   "export GOTOOLCHAIN=go1.21.4\n",
+		".github/workflows/ci.yml": "env:\n  GOTOOLCHAIN: go1.21.4\n",
+		"Dockerfile":               "FROM golang:1.21.4 AS build\n",
+	}
+	for name, expected := range expectedContents {
+		content, err := os.ReadFile(filepath.Join("a1", filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(content), expected) {
+			t.Error(unexpectedMessage(name, expected, string(content)))
+		}
+	}
+}
+
+func TestPrivateProxy(t *testing.T) {
+	chdirTemp(t)
+	setGoEnv(t)
+
+	var trace strings.Builder
+	ctx := create.WithTrace(context.Background(), &trace)
+	_, err := create.Create(ctx, create.Options{Module: "a1", PrivateProxy: "https://athens.example.com", CI: "gitlab"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Go commands resolve modules through the proxy, as the module does everywhere it's built
+	if expected := "    GOPROXY=https://athens.example.com\n"; !strings.Contains(trace.String(), expected) {
+		t.Error(unexpectedMessage("trace line", expected, trace.String()))
+	}
+	expectedContents := map[string]string{
+		".envrc":           "export GOPROXY=https://athens.example.com\n",
+		".gitlab-ci.yml":   "  GOPROXY: https://athens.example.com\n",
+		"PRIVATE_PROXY.md": "machine athens.example.com\n",
+	}
+	for name, expected := range expectedContents {
+		content, err := os.ReadFile(filepath.Join("a1", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(content), expected) {
+			t.Error(unexpectedMessage(name, expected, string(content)))
+		}
+	}
+}
+
+func TestSecretScan(t *testing.T) {
+	chdirTemp(t)
+
+	_, err := create.Create(context.Background(), create.Options{Module: "a1", SecretScan: true, Git: true, GitInitialBranch: "main"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(filepath.Join("a1", ".githooks", "pre-commit"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" && info.Mode()&0100 == 0 {
+		t.Error(unexpectedMessage("pre-commit hook mode", "-rwxr-xr-x", info.Mode().String()))
+	}
+	gitConfig, err := os.ReadFile(filepath.Join("a1", ".git", "config"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(gitConfig), "hooksPath = .githooks") {
+		t.Error(unexpectedMessage("Git config", "hooksPath = .githooks", string(gitConfig)))
+	}
+
+	// git would run the hook on the initial commit, which can't scan without gitleaks
+	t.Setenv("PATH", t.TempDir())
+	_, err = create.Create(context.Background(), create.Options{Module: "a2", SecretScan: true, Git: true, GitExec: true})
+	if !errors.Is(err, create.ErrGit) {
+		t.Error(unexpectedMessage("error", create.ErrGit, err))
+	}
+	if _, err := os.Stat("a2"); !errors.Is(err, fs.ErrNotExist) {
+		t.Error("Directory was created without gitleaks: a2")
+	}
+}
+
+func TestSplitCmd(t *testing.T) {
+	chdirTemp(t)
+
+	_, err := create.Create(context.Background(), create.Options{Module: "example.com/go-widget", CI: "github", SplitCmd: true, NoDeps: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join("go-widget", "main.go")); !errors.Is(err, fs.ErrNotExist) {
+		t.Error(unexpectedMessage("main.go", "no library main.go", fmt.Sprint(err)))
+	}
+	expectedContents := map[string]string{
+		"hello.go":                 "package gowidget\n",
+		"cmd/go-widget/go.mod":     "module example.com/go-widget/cmd/go-widget\n",
+		"cmd/go-widget/main.go":    "\tgowidget \"example.com/go-widget\"\n",
+		".gitignore":               "cmd/go-widget/go-widget",
+		".github/workflows/ci.yml": "      - name: Test command\n        working-directory: cmd/go-widget\n        run: go test ./...\n",
+	}
+	for name, expected := range expectedContents {
+		content, err := os.ReadFile(filepath.Join("go-widget", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(content), expected) {
+			t.Error(unexpectedMessage(name, expected, string(content)))
+		}
+	}
+
+	// The command builds from the library, through the replace directive
+	cmd := exec.Command("go", "run", ".")
+	cmd.Dir = filepath.Join("go-widget", "cmd", "go-widget")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatal(err, string(output))
+	}
+	if expected := "hello, world!\n"; string(output) != expected {
+		t.Error(unexpectedMessage("output", expected, string(output)))
+	}
+
+	_, err = create.Create(context.Background(), create.Options{Module: "a1", SplitCmd: true, Docker: true})
+	var usageError create.UsageError
+	if !errors.As(err, &usageError) {
+		t.Error(unexpectedMessage("error", "--split-cmd can't be used with --docker", fmt.Sprint(err)))
+	}
+}
+
+func TestAPICheck(t *testing.T) {
+	chdirTemp(t)
+
+	gorelease := "go run golang.org/x/exp/cmd/gorelease@latest -base="
+	for _, opts := range []create.Options{
+		{Module: "a1", CI: "github", SplitCmd: true},
+		{Module: "a2", CI: "gitlab", Layout: "apiv1", Make: true, Taskfile: true},
+		{Module: "a3", CI: "github", Make: true},
+	} {
+		opts.NoDeps = true
+		_, err := create.Create(context.Background(), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Only libraries have an API to keep compatible
+		library := opts.SplitCmd || opts.Layout != ""
+		for _, name := range []string{".github/workflows/ci.yml", ".gitlab-ci.yml", "Makefile", "Taskfile.yml"} {
+			content, err := os.ReadFile(filepath.Join(opts.Module, name))
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(string(content), gorelease) != library {
+				t.Error(unexpectedMessage(opts.Module+"/"+name, fmt.Sprintf("checks API with gorelease: %t", library), string(content)))
+			}
+		}
+	}
+}
+
+func TestPropTests(t *testing.T) {
+	chdirTemp(t)
+
+	r, err := create.Create(context.Background(), create.Options{Module: "example.com/go-widget", SplitCmd: true, PropTests: true, RunOptions: create.RunOptions{DryRun: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := filepath.Join("go-widget", "hello_prop_test.go"); !strings.Contains(strings.Join(r.CreatedFiles, "\n"), expected) {
+		t.Error(unexpectedMessage("created files", expected, strings.Join(r.CreatedFiles, "\n")))
+	}
+	if expected := "pgregory.net/rapid"; strings.Join(r.Dependencies, "\n") != expected {
+		t.Error(unexpectedMessage("dependencies", expected, strings.Join(r.Dependencies, "\n")))
+	}
+
+	_, err = create.Create(context.Background(), create.Options{Module: "a1", PropTests: true})
+	var usageError create.UsageError
+	if !errors.As(err, &usageError) {
+		t.Error(unexpectedMessage("error", "--prop-tests requires --split-cmd", fmt.Sprint(err)))
+	}
+}
+
+func TestExamples(t *testing.T) {
+	chdirTemp(t)
+
+	_, err := create.Create(context.Background(), create.Options{Module: "example.com/go-widget", CI: "gitlab", SplitCmd: true, Examples: true, NoDeps: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// What CI runs to build the examples, which writes no binaries (a binary named greeting can't be written beside
+	// the greeting directory)
+	ciBuildExamples := "cd examples && go build -o /dev/null ./..."
+	expectedContents := map[string]string{
+		"examples/go.mod":           "module example.com/go-widget/examples\n",
+		"examples/greeting/main.go": "\tgowidget \"example.com/go-widget\"\n",
+		".gitlab-ci.yml":            "    - (" + ciBuildExamples + ")\n",
+	}
+	for name, expected := range expectedContents {
+		content, err := os.ReadFile(filepath.Join("go-widget", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(content), expected) {
+			t.Error(unexpectedMessage(name, expected, string(content)))
+		}
+	}
+
+	// CI's build of the examples passes
+	if runtime.GOOS != "windows" {
+		cmd := exec.Command("sh", "-c", ciBuildExamples)
+		cmd.Dir = "go-widget"
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatal(err, string(output))
+		}
+	}
+
+	// The example builds from the library, through the replace directive
+	cmd := exec.Command("go", "run", "./greeting")
+	cmd.Dir = filepath.Join("go-widget", "examples")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatal(err, string(output))
+	}
+	if expected := "hello, gopher!\n"; string(output) != expected {
+		t.Error(unexpectedMessage("output", expected, string(output)))
+	}
+
+	_, err = create.Create(context.Background(), create.Options{Module: "a2", Examples: true})
+	var usageError create.UsageError
+	if !errors.As(err, &usageError) {
+		t.Error(unexpectedMessage("error", "--examples requires --split-cmd", fmt.Sprint(err)))
+	}
+}
+
+func TestCreateRemote(t *testing.T) {
+	chdirTemp(t)
+
+	// A GitHub API that knows the user acme, and creates (and deletes) repositories for them and their organizations.
+	// Its token can't delete acme/keeper.
+	repos := map[string]bool{"acme/taken": true}
+	created := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, `{"message": "Bad credentials"}`, http.StatusUnauthorized)
+			return
+		}
+		var body struct {
+			Name    string `json:"name"`
+			Private bool   `json:"private"`
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/user":
+			io.WriteString(w, `{"login": "acme"}`)
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/repos/"):
+			if !repos[strings.TrimPrefix(r.URL.Path, "/repos/")] {
+				http.NotFound(w, r)
+			}
+		case r.Method == http.MethodPost && (r.URL.Path == "/user/repos" || strings.HasPrefix(r.URL.Path, "/orgs/")):
+			json.NewDecoder(r.Body).Decode(&body)
+			owner := "acme"
+			if r.URL.Path != "/user/repos" {
+				owner = strings.Split(r.URL.Path, "/")[2]
+			}
+			if repos[owner+"/"+body.Name] {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				io.WriteString(w, `{"message": "Repository creation failed.", "errors": [{"message": "name already exists on this account"}]}`)
+				return
+			}
+			repos[owner+"/"+body.Name] = true
+			created = append(created, fmt.Sprintf("%s/%s private=%t", owner, body.Name, body.Private))
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/repos/"):
+			repo := strings.TrimPrefix(r.URL.Path, "/repos/")
+			if repo == "acme/keeper" {
+				http.Error(w, `{"message": "Must have admin rights to Repository."}`, http.StatusForbidden)
+				return
+			}
+			delete(repos, repo)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	t.Setenv("GITHUB_API_URL", server.URL)
+	t.Setenv("GITHUB_TOKEN", "secret")
+
+	r, err := create.Create(context.Background(), create.Options{Module: "github.com/acme/widget", Git: true, CreateRemote: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "commit,addRemote,createRemote"; strings.Join(r.GitActions, ",") != "init,"+expected {
+		t.Error(unexpectedMessage("Git actions", "init,"+expected, strings.Join(r.GitActions, ",")))
+	}
+	for _, nextStep := range r.NextSteps {
+		if strings.Contains(nextStep, "https://github.com/new") {
+			t.Error(unexpectedMessage("next steps", "no step to create the remote", nextStep))
+		}
+	}
+	_, err = create.Create(context.Background(), create.Options{Module: "github.com/acme-org/gadget", Git: true, CreateRemote: true, PublicRemote: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "acme/widget private=true,acme-org/gadget private=false"; strings.Join(created, ",") != expected {
+		t.Error(unexpectedMessage("created repositories", expected, strings.Join(created, ",")))
+	}
+
+	// A repository that can't be created fails as Git does, leaving nothing behind
+	_, err = create.Create(context.Background(), create.Options{Module: "github.com/acme/taken", Git: true, CreateRemote: true})
+	if !errors.Is(err, create.ErrGit) || !strings.Contains(err.Error(), "name already exists on this account") {
+		t.Error(unexpectedMessage("error", "name already exists on this account", fmt.Sprint(err)))
+	}
+	if _, err := os.Stat("taken"); !errors.Is(err, fs.ErrNotExist) {
+		t.Error(unexpectedMessage("taken", "removed", fmt.Sprint(err)))
+	}
+
+	// A repository that was created is deleted when a later step fails (here, bundling, onto a directory), unless the
+	// token can't, which is reported
+	for _, name := range []string{"gizmo", "keeper"} {
+		err = os.MkdirAll(filepath.Join(name+".bundle", "x"), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = create.Create(context.Background(), create.Options{Module: "github.com/acme/" + name, Git: true, CreateRemote: true, Bundle: true})
+		if err == nil {
+			t.Fatal(unexpectedMessage(name+" error", "Failed to bundle Git repository", "nil"))
+		}
+		leftBehind := strings.Contains(err.Error(), "which was left behind: https://github.com/acme/"+name)
+		if name == "gizmo" && (repos["acme/gizmo"] || leftBehind) {
+			t.Error(unexpectedMessage("acme/gizmo", "deleted", err.Error()))
+		} else if name == "keeper" && (!repos["acme/keeper"] || !leftBehind) {
+			t.Error(unexpectedMessage("acme/keeper", "left behind, and reported", err.Error()))
+		}
+		if _, err := os.Stat(name); !errors.Is(err, fs.ErrNotExist) {
+			t.Error(unexpectedMessage(name, "removed", fmt.Sprint(err)))
+		}
+	}
+
+	_, err = create.Create(context.Background(), create.Options{Module: "github.com/acme/a1", CreateRemote: true})
+	var usageError create.UsageError
+	if !errors.As(err, &usageError) {
+		t.Error(unexpectedMessage("error", "--create-remote requires --git", fmt.Sprint(err)))
+	}
+}
+
+func TestLayout(t *testing.T) {
+	chdirTemp(t)
+
+	_, err := create.Create(context.Background(), create.Options{Module: "example.com/widget", Layout: "apiv1", NoDeps: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(filepath.Join("widget", "api", "README.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "import v2 \"example.com/widget/api/v2\""; !strings.Contains(string(content), expected) {
+		t.Error(unexpectedMessage("api/README.md", expected, string(content)))
+	}
+
+	// The API's example runs as a test
+	cmd := exec.Command("go", "test", "-run", "Example", "./api/...")
+	cmd.Dir = "widget"
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatal(err, string(output))
+	}
+	if expected := "example.com/widget/api/v1"; !strings.Contains(string(output), expected) {
+		t.Error(unexpectedMessage("output", expected, string(output)))
+	}
+
+	_, err = create.Create(context.Background(), create.Options{Module: "a1", Layout: "flat"})
+	var usageError create.UsageError
+	if !errors.As(err, &usageError) {
+		t.Error(unexpectedMessage("error", "Unsupported layout: flat", fmt.Sprint(err)))
+	}
+}
+
+func TestGitRemote(t *testing.T) {
+	chdirTemp(t)
+
+	for module, expected := range map[string]struct{ remote, newRepoURL string }{
+		"github.com/acme/widget/v2":    {"git@github.com:acme/widget.git", "https://github.com/new"},
+		"gitlab.com/acme/tools/widget": {"git@gitlab.com:acme/tools/widget.git", "https://gitlab.com/projects/new"},
+		"bitbucket.org/acme/widget":    {"git@bitbucket.org:acme/widget.git", "https://bitbucket.org/repo/create"},
+		"codeberg.org/acme/widget":     {"git@codeberg.org:acme/widget.git", "https://codeberg.org/repo/create"},
+		"git.sr.ht/~acme/widget":       {"git@git.sr.ht:~acme/widget", "https://git.sr.ht/create"},
+		"git.example.com/acme/widget":  {"git@git.example.com:acme/widget.git", ""},
+	} {
+		r, err := create.Create(context.Background(), create.Options{Module: module, Git: true, RunOptions: create.RunOptions{DryRun: true}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.GitRemote != expected.remote {
+			t.Error(unexpectedMessage(module+" remote", expected.remote, r.GitRemote))
+		}
+		nextStep := "Create remote Git repository " + expected.remote
+		if expected.newRepoURL != "" {
+			nextStep += ": " + expected.newRepoURL
+		}
+		if !strings.Contains(strings.Join(r.NextSteps, "\n")+"\n", nextStep+"\n") {
+			t.Error(unexpectedMessage(module+" next steps", nextStep, strings.Join(r.NextSteps, "\n")))
+		}
+	}
+
+	// With HTTPS, where Git's credential helper authenticates instead of an SSH key
+	for module, expected := range map[string]string{
+		"github.com/acme/widget/v2":   "https://github.com/acme/widget.git",
+		"git.sr.ht/~acme/widget":      "https://git.sr.ht/~acme/widget",
+		"git.example.com/acme/widget": "https://git.example.com/acme/widget.git",
+	} {
+		r, err := create.Create(context.Background(), create.Options{Module: module, Git: true, RemoteProtocol: "https", RunOptions: create.RunOptions{DryRun: true}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.GitRemote != expected {
+			t.Error(unexpectedMessage(module+" remote", expected, r.GitRemote))
+		}
+	}
+}
+
+func TestDryRunRunsNothing(t *testing.T) {
+	chdirTemp(t)
+	if runtime.GOOS == "windows" {
+		t.Skip("go, git, docker, ssh-add, and gh are stubbed with shell scripts")
+	}
+
+	// Each command records that it ran
+	binDir, err := filepath.Abs("bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Mkdir(binDir, 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ranPath := filepath.Join(binDir, "ran")
+	for _, name := range []string{"go", "git", "docker", "ssh-add", "gh"} {
+		script := fmt.Sprintf("#!/bin/sh\necho %s >> %s\n", name, ranPath)
+		err = os.WriteFile(filepath.Join(binDir, name), []byte(script), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", binDir) // Automatically reset
+	t.Setenv("GITHUB_TOKEN", "")
+
+	for _, opts := range []create.Options{
+		{Module: "a1", Git: true, GitExec: true},
+		{Module: "a2", InContainer: true, I18n: true},
+		{Module: "a3", GitExec: true, License: "mit"},
+		{Module: "github.com/a/a5", Git: true, GitExec: true},
+		{Module: "github.com/a/a6", Git: true, GitExec: true, CreateRemote: true},
+	} {
+		// Even traced, when the go env that commands would run with is otherwise looked up
+		var trace strings.Builder
+		ctx := create.WithTrace(context.Background(), &trace)
+		opts.DryRun = true
+		r, err := create.Create(ctx, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := "1.18"; r.GoVersion != expected {
+			t.Error(unexpectedMessage(opts.Module+" Go version", expected, r.GoVersion))
+		}
+		if trace.String() != "" {
+			t.Error(unexpectedMessage(opts.Module+" trace", "", trace.String()))
+		}
+	}
+	err = os.WriteFile("go.mod", []byte("module a4\n\ngo 1.18\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = create.Add(context.Background(), create.AddOptions{Feature: "license", Arg: "mit", GitExec: true, RunOptions: create.RunOptions{DryRun: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ran, err := os.ReadFile(ranPath); err == nil {
+		t.Error(unexpectedMessage("commands run", "", string(ran)))
+	}
+}
+
+func TestPush(t *testing.T) {
+	chdirTemp(t)
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	// Remotes on example.com are bare repositories in remotes/, as git rewrites their URLs
+	remotes, err := filepath.Abs("remotes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := fmt.Sprintf("[user]\n\tname = Test\n\temail = test@example.com\n[url \"%s/\"]\n\tinsteadOf = git@example.com:\n", filepath.ToSlash(remotes))
+	configPath := filepath.Join(remotes, "gitconfig")
+	err = os.MkdirAll(filepath.Join(remotes, "acme"), 0755)
+	if err == nil {
+		err = os.WriteFile(configPath, []byte(config), 0644)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", configPath)
+	output, err := exec.Command("git", "init", "--bare", filepath.Join(remotes, "acme", "widget.git")).CombinedOutput()
+	if err != nil {
+		t.Fatal(err, string(output))
+	}
+
+	r, err := create.Create(context.Background(), create.Options{Module: "example.com/acme/widget", Git: true, GitExec: true, GitInitialBranch: "main", Push: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "init,commit,addRemote,push"; strings.Join(r.GitActions, ",") != expected {
+		t.Error(unexpectedMessage("Git actions", expected, strings.Join(r.GitActions, ",")))
+	}
+	for _, nextStep := range r.NextSteps {
+		if strings.HasPrefix(nextStep, "Push to remote") || strings.HasPrefix(nextStep, "Create remote") {
+			t.Error(unexpectedMessage("next steps", "no step to create or push to the remote", nextStep))
+		}
+	}
+	output, err = exec.Command("git", "-C", filepath.Join(remotes, "acme", "widget.git"), "log", "--format=%s", "main").CombinedOutput()
+	if err != nil || string(output) != "Initial commit\n" {
+		t.Error(unexpectedMessage("pushed commits", "Initial commit\n", string(output)))
+	}
+
+	// A remote that doesn't exist fails the push, but not the module
+	r, err = create.Create(context.Background(), create.Options{Module: "example.com/acme/gadget", Git: true, GitExec: true, GitInitialBranch: "main", Push: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Notes) == 0 || !strings.HasPrefix(r.Notes[len(r.Notes)-1], "Failed to push to remote Git repository: ") {
+		t.Error(unexpectedMessage("notes", "Failed to push to remote Git repository: ...", strings.Join(r.Notes, "\n")))
+	}
+	if expected := "Push to remote Git repository: $ git push -u origin main"; !strings.Contains(strings.Join(r.NextSteps, "\n"), expected) {
+		t.Error(unexpectedMessage("next steps", expected, strings.Join(r.NextSteps, "\n")))
+	}
+	if _, err := os.Stat("gadget"); err != nil {
+		t.Error(unexpectedMessage("gadget", "kept", fmt.Sprint(err)))
+	}
+
+	_, err = create.Create(context.Background(), create.Options{Module: "example.com/acme/gizmo", Push: true})
+	var usageError create.UsageError
+	if !errors.As(err, &usageError) {
+		t.Error(unexpectedMessage("error", "--push requires --git", fmt.Sprint(err)))
+	}
+}
+
+func TestScorecard(t *testing.T) {
+	chdirTemp(t)
+
+	_, err := create.Create(context.Background(), create.Options{Module: "github.com/acme/widget", Git: true, Scorecard: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	badge := "[![OpenSSF Scorecard](https://api.scorecard.dev/projects/github.com/acme/widget/badge)](https://scorecard.dev/viewer/?uri=github.com/acme/widget)\n"
+	expectedContents := map[string]string{
+		"README.md":                       "# widget\n\n" + badge + "\n",
+		"BEST_PRACTICES.md":               badge,
+		".github/workflows/scorecard.yml": "uses: ossf/scorecard-action@",
+	}
+	for name, expected := range expectedContents {
+		content, err := os.ReadFile(filepath.Join("widget", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(content), expected) {
+			t.Error(unexpectedMessage(name, expected, string(content)))
+		}
+	}
+
+	_, err = create.Create(context.Background(), create.Options{Module: "widget", Scorecard: true})
+	var usageError create.UsageError
+	if !errors.As(err, &usageError) {
+		t.Error(unexpectedMessage("error", "--scorecard requires a module under github.com", fmt.Sprint(err)))
+	}
+}
+
+func TestFmtCheck(t *testing.T) {
+	chdirTemp(t)
+
+	expectedChecks := map[string]string{
+		"github": "run: test -z \"$(gofmt -l .)\" || (gofmt -d . && exit 1)\n",
+		"gitlab": "    - test -z \"$(go run mvdan.cc/gofumpt@latest -l .)\" || (go run mvdan.cc/gofumpt@latest -d . && exit 1)\n",
+	}
+	for ci, formatter := range map[string]string{"github": "gofmt", "gitlab": "gofumpt"} {
+		module := "a-" + ci
+		r, err := create.Create(context.Background(), create.Options{Module: module, CI: ci, FmtCheck: formatter})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(filepath.Join(module, ".editorconfig")); err != nil {
+			t.Error(err)
+		}
+		// The CI workflow is the last file created before .gitignore
+		content, err := os.ReadFile(r.CreatedFiles[len(r.CreatedFiles)-3])
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, expected := range []string{expectedChecks[ci], "editorconfig-checker@latest\n"} {
+			if !strings.Contains(string(content), expected) {
+				t.Error(unexpectedMessage("CI workflow", expected, string(content)))
+			}
+		}
+	}
+
+	_, err := create.Create(context.Background(), create.Options{Module: "a1", FmtCheck: "gofmt"})
+	var usageError create.UsageError
+	if !errors.As(err, &usageError) {
+		t.Error(unexpectedMessage("error", "--fmt-check requires --ci", fmt.Sprint(err)))
+	}
+}
+
+func TestProvisionGo(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("The fake toolchain is a shell script")
+	}
+	chdirTemp(t)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir()) // Automatically reset
+	t.Setenv("HOME", t.TempDir())
+
+	// A toolchain whose go only reports its version
+	toolchainDir := t.TempDir()
+	err := os.MkdirAll(filepath.Join(toolchainDir, "go", "bin"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(toolchainDir, "go", "bin", "go"), []byte("#!/bin/sh\necho go1.99.1\n"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tarball bytes.Buffer
+	err = create.WriteTarball(&tarball, toolchainDir, "go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checksum := sha256.Sum256(tarball.Bytes())
+	filename := fmt.Sprintf("go1.99.1.%s-%s.tar.gz", runtime.GOOS, runtime.GOARCH)
+	releases := fmt.Sprintf(`[
+		{"version": "go1.100rc1", "stable": false, "files": []},
+		{"version": "go1.99.1", "stable": true, "files": [
+			{"filename": %q, "os": %q, "arch": %q, "kind": "archive", "sha256": %q}
+		]}
+	]`, filename, runtime.GOOS, runtime.GOARCH, hex.EncodeToString(checksum[:]))
+	downloads := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/dl/":
+			io.WriteString(w, releases)
+		case "/dl/" + filename:
+			downloads++
+			w.Write(tarball.Bytes())
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	for i := 0; i < 2; i++ {
+		goExecutable, err := create.ProvisionGo(context.Background(), "1.99", server.URL+"/dl/")
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = create.Create(context.Background(), create.Options{Module: fmt.Sprintf("a%d", i+1), GoToolchain: goExecutable})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Downloaded once, then found in the cache
+	if downloads != 1 {
+		t.Error(unexpectedMessage("downloads", 1, downloads))
+	}
+	goMod, err := os.ReadFile(filepath.Join("a2", "go.mod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "module a2\n\ngo 1.99\n"; string(goMod) != expected {
+		t.Error(unexpectedMessage("go.mod", expected, string(goMod)))
+	}
+
+	// A release not listed, or whose download doesn't match its checksum, isn't used
+	_, err = create.ProvisionGo(context.Background(), "1.98", server.URL+"/dl/")
+	if !errors.Is(err, create.ErrGoToolchainMissing) {
+		t.Error(unexpectedMessage("error", create.ErrGoToolchainMissing, err))
+	}
+	releases = strings.Replace(releases, hex.EncodeToString(checksum[:]), strings.Repeat("0", 64), 1)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	_, err = create.ProvisionGo(context.Background(), "1.99", server.URL+"/dl/")
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Error(unexpectedMessage("error", "checksum mismatch", fmt.Sprint(err)))
+	}
+}
+
+func TestUpgradeSelf(t *testing.T) {
+	executable := filepath.Join(t.TempDir(), "gmc")
+	err := os.WriteFile(executable, []byte("old"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	binaryName := fmt.Sprintf("gmc_%s_%s", runtime.GOOS, runtime.GOARCH)
+	if runtime.GOOS == "windows" {
+		binaryName += ".exe"
+	}
+	binary := []byte("new")
+	checksum := sha256.Sum256(binary)
+	checksums := fmt.Sprintf("%s  gmc_plan9_arm\n%s  %s\n", strings.Repeat("0", 64), hex.EncodeToString(checksum[:]), binaryName)
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/releases/latest":
+			fmt.Fprintf(w, `{"tag_name": "v1.2.3", "assets": [
+				{"name": %q, "browser_download_url": %q},
+				{"name": "checksums.txt", "browser_download_url": %q}
+			]}`, binaryName, server.URL+"/download/"+binaryName, server.URL+"/download/checksums.txt")
+		case "/download/" + binaryName:
+			w.Write(binary)
+		case "/download/checksums.txt":
+			io.WriteString(w, checksums)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	release, err := create.LatestRelease(context.Background(), server.URL+"/releases/latest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for version, expected := range map[string]bool{"v1.2.2": true, "v1.2.3": false, "v1.10.0": false, "(devel)": true} {
+		if newer := release.NewerThan(version); newer != expected {
+			t.Error(unexpectedMessage(fmt.Sprintf("newer than %s", version), expected, newer))
+		}
+	}
+
+	// A download that doesn't match its checksum leaves the executable as it was
+	checksums = strings.Replace(checksums, hex.EncodeToString(checksum[:]), strings.Repeat("0", 64), 1)
+	err = release.Install(context.Background(), executable)
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Error(unexpectedMessage("error", "checksum mismatch", fmt.Sprint(err)))
+	}
+	content, err := os.ReadFile(executable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "old" {
+		t.Error(unexpectedMessage("executable", "old", string(content)))
+	}
+
+	checksums = strings.Replace(checksums, strings.Repeat("0", 64)+"  "+binaryName, hex.EncodeToString(checksum[:])+"  "+binaryName, 1)
+	err = release.Install(context.Background(), executable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err = os.ReadFile(executable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "new" {
+		t.Error(unexpectedMessage("executable", "new", string(content)))
+	}
+	entries, err := os.ReadDir(filepath.Dir(executable))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" && len(entries) != 1 {
+		t.Error(unexpectedMessage("files beside executable", 1, len(entries)))
+	}
+}
+
+func TestTemplates(t *testing.T) {
+	templates := create.Templates()
+	names := map[string]bool{}
+	for _, template := range templates {
+		names[template.Name] = true
+		if template.Source != "embedded" || template.Description == "" {
+			t.Error(unexpectedMessage("template "+template.Name, "embedded, with a description", fmt.Sprintf("%s: %q", template.Source, template.Description)))
+		}
+	}
+	for _, name := range []string{"default", "ci-github", "scorecard"} {
+		if !names[name] {
+			t.Error(unexpectedMessage("templates", name, fmt.Sprint(templates)))
+		}
+	}
+}
+
+func TestAdopt(t *testing.T) {
+	chdirTemp(t)
+
+	// A module made by hand, with a GitHub Actions workflow and a golangci-lint configuration
+	files := map[string]string{
+		"go.mod":                     "module example.com/hand\n\ngo 1.21\n",
+		".github/workflows/test.yml": "on: push\n",
+		".golangci.yml":              "linters:\n",
+		"README.md":                  "# hand\n",
+	}
+	for name, content := range files {
+		err := os.MkdirAll(filepath.Dir(name), 0755)
+		if err == nil {
+			err = os.WriteFile(name, []byte(content), 0644)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var output bytes.Buffer
+	_, err := create.Adopt(context.Background(), create.AdoptOptions{RunOptions: create.RunOptions{Output: &output}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"- NOTE: Found --ci=github: .github/workflows/test.yml\n",
+		"- NOTE: Found --lint: .golangci.yml\n",
+		"- NOTE: Missing Git repository (add it with: gmc add git)\n",
+		"- NOTE: Missing file: LICENSE (add it with: gmc add license <license>)\n",
+		"- Created file     : .gmc/manifest.json\n",
+	} {
+		if !strings.Contains(output.String(), expected) {
+			t.Error(unexpectedMessage("output", expected, output.String()))
+		}
+	}
+	content, err := os.ReadFile(filepath.Join(".gmc", "manifest.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m struct {
+		Module    string   `json:"module"`
+		Flags     []string `json:"flags"`
+		Templates []string `json:"templates"`
+		Files     []struct {
+			Path string `json:"path"`
+		} `json:"files"`
+		Adopted bool `json:"adopted"`
+	}
+	err = json.Unmarshal(content, &m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths := []string{}
+	for _, f := range m.Files {
+		paths = append(paths, f.Path)
+	}
+	actual := fmt.Sprintf("%s %v %v %v %t", m.Module, m.Flags, m.Templates, paths, m.Adopted)
+	if expected := "example.com/hand [--ci=github --lint] [ci-github lint] [.github/workflows/test.yml .golangci.yml README.md] true"; actual != expected {
+		t.Error(unexpectedMessage("manifest", expected, actual))
+	}
+
+	// A module is only adopted once
+	_, err = create.Adopt(context.Background(), create.AdoptOptions{})
+	if err == nil || !strings.Contains(err.Error(), "Already managed by gmc") {
+		t.Error(unexpectedMessage("error", "Already managed by gmc", fmt.Sprint(err)))
+	}
+}
+
+func TestReleasePrep(t *testing.T) {
+	chdirTemp(t)
+	setGoEnv(t)
+
+	_, err := create.Create(context.Background(), create.Options{Module: "a1", Git: true, GitInitialBranch: "main"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Chdir("a1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without a changelog entry, nothing is tagged
+	r, err := create.ReleasePrep(context.Background(), create.ReleasePrepOptions{Version: "v1.0.0"})
+	if !errors.Is(err, create.ErrNotReleasable) {
+		t.Error(unexpectedMessage("error", create.ErrNotReleasable, err))
+	}
+	expectedChecks := "Version: | Changelog: No CHANGELOG.md | go.mod tidy: | Tests:"
+	if checks := releaseChecks(r); checks != expectedChecks {
+		t.Error(unexpectedMessage("checks", expectedChecks, checks))
+	}
+
+	err = os.WriteFile("CHANGELOG.md", []byte("# Changelog\n\n## [1.0.0] - 2024-05-01\n\n- First release\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	output, err := exec.Command("git", "add", "CHANGELOG.md").CombinedOutput()
+	if err == nil {
+		output, err = exec.Command("git", "commit", "--quiet", "-m", "Add changelog").CombinedOutput()
+	}
+	if err != nil {
+		t.Fatalf("%s: %s", err, output)
+	}
+
+	// Checked, but not tagged
+	r, err = create.ReleasePrep(context.Background(), create.ReleasePrepOptions{Version: "v1.0.0", DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Tag != "" {
+		t.Error(unexpectedMessage("tag", "", r.Tag))
+	}
+
+	// Tagged
+	r, err = create.ReleasePrep(context.Background(), create.ReleasePrepOptions{Version: "v1.0.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	output, err = exec.Command("git", "tag", "--list").CombinedOutput()
+	if err != nil || r.Tag != "v1.0.0" || string(output) != "v1.0.0\n" {
+		t.Error(unexpectedMessage("tags", "v1.0.0\n", fmt.Sprintf("%s%v", output, err)))
+	}
+
+	// Versions that can't follow
+	tests := []struct {
+		version        string
+		expectedChecks string
+	}{
+		{"v1.0.0", "Version: v1.0.0 is already tagged | Changelog: | go.mod tidy: | Tests:"},
+		{"v0.9.0", "Version: v0.9.0 is earlier than the latest tag, v1.0.0 | Changelog: No entry for v0.9.0 in CHANGELOG.md | go.mod tidy: | Tests:"},
+		{"v2.0.0", "Version: v2.0.0 doesn't match the module path's major version: a1 | Changelog: No entry for v2.0.0 in CHANGELOG.md | go.mod tidy: | Tests:"},
+	}
+	for _, tc := range tests {
+		r, err := create.ReleasePrep(context.Background(), create.ReleasePrepOptions{Version: tc.version})
+		if !errors.Is(err, create.ErrNotReleasable) {
+			t.Error(unexpectedMessage("error for "+tc.version, create.ErrNotReleasable, err))
+		}
+		if checks := releaseChecks(r); checks != tc.expectedChecks {
+			t.Error(unexpectedMessage("checks for "+tc.version, tc.expectedChecks, checks))
+		}
+	}
+
+	// Invalid version
+	_, err = create.ReleasePrep(context.Background(), create.ReleasePrepOptions{Version: "1.1"})
+	var usage create.UsageError
+	if !errors.As(err, &usage) {
+		t.Error(unexpectedMessage("error", "create.UsageError", fmt.Sprintf("%T", err)))
+	}
+}
+
+// releaseChecks describes what ReleasePrep checked, e.g. "Version: | Changelog: No CHANGELOG.md"
+func releaseChecks(r *create.ReleasePrepResult) string {
+	if r == nil {
+		return ""
+	}
+	checks := []string{}
+	for _, check := range r.Checks {
+		checks = append(checks, strings.TrimSpace(check.Name+": "+check.Error))
+	}
+	return strings.Join(checks, " | ")
+}
+
+func TestAudit(t *testing.T) {
+	chdirTemp(t)
+
+	// An organization's repositories: three created by gmc (one changed since, one without CI, and one whose CI was added
+	// later without being recorded), and one made by hand
+	readme := "# a\n"
+	checksum := sha256.Sum256([]byte(readme))
+	files := map[string]string{
+		"org/a/go.mod":             "module example.com/a\n\ngo 1.21\n",
+		"org/a/README.md":          readme,
+		"org/a/.gmc/manifest.json": fmt.Sprintf(`{"gmcVersion":"v1.0.0","module":"example.com/a","flags":["--ci=github","--lint"],"files":[{"path":"README.md","sha256":"%x"}]}`, checksum),
+		"org/b/go.mod":             "module example.com/b\n\ngo 1.21\n",
+		"org/b/README.md":          "# b, changed\n",
+		"org/b/.gmc/manifest.json": fmt.Sprintf(`{"gmcVersion":"v1.2.0","module":"example.com/b","flags":["--ci=false","--lint"],"files":[{"path":"README.md","sha256":"%x"}]}`, checksum),
+		"org/c/go.mod":             "module example.com/c\n\ngo 1.21\n",
+		"org/d/go.mod":             "module example.com/d\n\ngo 1.21\n",
+		"org/d/.gitlab-ci.yml":     "test:\n",
+		"org/d/.gmc/manifest.json": `{"gmcVersion":"v1.2.0","module":"example.com/d","flags":[],"files":[]}`,
+		"org/notes.txt":            "Not a module\n",
+	}
+	for name, content := range files {
+		err := os.MkdirAll(filepath.Dir(name), 0755)
+		if err == nil {
+			err = os.WriteFile(name, []byte(content), 0644)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	audits, err := create.Audit(create.AuditOptions{Paths: []string{"org"}, Require: []string{"ci", "lint"}, Version: "v1.2.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual := []string{}
+	for _, a := range audits {
+		actual = append(actual, fmt.Sprintf("%s %s %t %s %t %v %v", filepath.ToSlash(a.Dir), a.Module, a.Managed, a.GmcVersion, a.Outdated, a.MissingFeatures, a.ModifiedFiles))
+	}
+	expected := []string{
+		"org/a example.com/a true v1.0.0 true [] []",
+		"org/b example.com/b true v1.2.0 false [ci] [README.md]",
+		"org/c example.com/c false  false [] []",
+		"org/d example.com/d true v1.2.0 false [lint] []",
+	}
+	if strings.Join(actual, "\n") != strings.Join(expected, "\n") {
+		t.Error(unexpectedMessage("audits", strings.Join(expected, "\n"), strings.Join(actual, "\n")))
+	}
+
+	// A module is audited itself, rather than its subdirectories
+	audits, err = create.Audit(create.AuditOptions{Paths: []string{filepath.Join("org", "a")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(audits) != 1 || audits[0].Module != "example.com/a" {
+		t.Error(unexpectedMessage("audits", "example.com/a", fmt.Sprint(audits)))
+	}
+
+	_, err = create.Audit(create.AuditOptions{Paths: []string{"missing"}})
+	if err == nil || !strings.HasPrefix(err.Error(), "Failed to audit: ") {
+		t.Error(unexpectedMessage("error", "Failed to audit: ...", fmt.Sprint(err)))
+	}
+}
+
+func TestPlanAndApply(t *testing.T) {
+	chdirTemp(t)
+
+	plan, err := create.Plan(context.Background(), create.Options{Module: "a1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat("a1"); !errors.Is(err, fs.ErrNotExist) {
+		t.Error("Directory was created by Plan: a1")
+	}
+
+	r, err := create.Apply(context.Background(), plan, create.RunOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Module != "a1" {
+		t.Error(unexpectedMessage("module", "a1", r.Module))
+	}
+	if _, err := os.Stat(filepath.Join("a1", "main.go")); err != nil {
+		t.Error("File was not created: a1/main.go")
+	}
+
+	_, err = create.Apply(context.Background(), []byte(`{"module": "a2"}`), create.RunOptions{})
+	if !errors.Is(err, create.ErrInvalidPlan) {
+		t.Error(unexpectedMessage("error", create.ErrInvalidPlan, err))
+	}
+}
+
+func TestAdd(t *testing.T) {
+	chdirTemp(t)
+	err := os.WriteFile("go.mod", []byte("module a1\n\ngo 1.18\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := create.Add(context.Background(), create.AddOptions{Feature: "make"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(r.CreatedFiles, " ") != "Makefile" {
+		t.Error(unexpectedMessage("created files", []string{"Makefile"}, r.CreatedFiles))
+	}
+
+	_, err = create.Add(context.Background(), create.AddOptions{Feature: "license"})
+	var usage create.UsageError
+	if !errors.As(err, &usage) {
+		t.Error(unexpectedMessage("error", "create.UsageError", fmt.Sprintf("%T", err)))
+	}
+}
+
+func TestAddUpdatesManifest(t *testing.T) {
+	chdirTemp(t)
+	_, err := create.Create(context.Background(), create.Options{Module: "github.com/a/widget"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Chdir("widget")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = create.Add(context.Background(), create.AddOptions{Feature: "ci", Arg: "github"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	audits, err := create.Audit(create.AuditOptions{Paths: []string{"."}, Require: []string{"ci"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(audits) != 1 || len(audits[0].MissingFeatures) != 0 || len(audits[0].ModifiedFiles) != 0 {
+		t.Error(unexpectedMessage("audits", "nothing missing or modified", fmt.Sprint(audits)))
+	}
+
+	content, err := os.ReadFile(filepath.Join(".gmc", "manifest.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m struct {
+		Templates []string `json:"templates"`
+		Files     []struct {
+			Path     string `json:"path"`
+			Template string `json:"template"`
+		} `json:"files"`
+	}
+	err = json.Unmarshal(content, &m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ci := ""
+	for _, f := range m.Files {
+		if f.Path == ".github/workflows/ci.yml" {
+			ci = f.Template
+		}
+	}
+	if strings.Join(m.Templates, " ") != "default ci-github" || ci != "ci-github/.github/workflows/ci.yml.tmpl" {
+		t.Error(unexpectedMessage("templates", "[default ci-github] and ci.yml from ci-github", fmt.Sprint(m.Templates, " ", ci)))
+	}
+}
+
+// chdirTemp changes into a new temporary directory for the rest of the test
+func chdirTemp(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Chdir(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		err = os.Chdir(cwd)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func localGoVersion(t *testing.T) string {
+	cmd := exec.Command("go", "env", "GOVERSION")
+	cmdOutput, err := cmd.Output()
+	if err != nil {
+		t.Fatal("Unable to look up Go version", err)
+	}
+	return regexp.MustCompile(`^go(\d+\.\d+)`).FindStringSubmatch(strings.TrimSpace(string(cmdOutput)))[1]
+}
+
+func unexpectedMessage[T any](thing string, expected T, actual T) string {
+	return fmt.Sprintf("Unexpected %s\nExpected: %v\nActual  : %v\n", thing, expected, actual)
+}
@@ -0,0 +1,45 @@
+package create
+
+// An editorExtra adds project configuration for an editor
+type editorExtra struct {
+	usage string
+
+	// Command that opens the module in the editor, suggested as a next step
+	command string
+}
+
+// Editor extras, keyed by name. Each is enabled by a flag of the same name, and copies the asset
+// directory of the same name into the module.
+var editorExtras = map[string]editorExtra{
+	"vscode": {
+		usage:   "add Visual Studio Code settings, launch configuration, and tasks",
+		command: "code",
+	},
+	"goland": {
+		usage:   "add GoLand run configurations for build, run, and test",
+		command: "goland",
+	},
+	"nvim": {
+		usage:   "add a project-local Neovim configuration for gopls and debugging",
+		command: "nvim",
+	},
+}
+
+var editorExtraNames = []string{"vscode", "goland", "nvim"}
+
+// An Editor can be configured in a module, with Options.Editors
+type Editor struct {
+	Name string
+
+	// What configuring the editor adds
+	Usage string
+}
+
+// Editors returns the editors that can be configured
+func Editors() []Editor {
+	editors := []Editor{}
+	for _, name := range editorExtraNames {
+		editors = append(editors, Editor{Name: name, Usage: editorExtras[name].usage})
+	}
+	return editors
+}
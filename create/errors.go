@@ -0,0 +1,66 @@
+package create
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors returned by Create, Add, and Apply wrap these, so that callers can tell failures apart with errors.Is
+var (
+	// A module name can't be used as a module path
+	ErrInvalidModulePath = errors.New("Invalid module name")
+
+	// The module's directory already exists, and doesn't hold exactly what would be created
+	ErrDirExists = errors.New("Directory already exists")
+
+	// Options.Archive names a file that already exists
+	ErrArchiveExists = errors.New("Archive already exists")
+
+	// The module would be created inside an existing module
+	ErrModuleExists = errors.New("Already a Go module")
+
+	// Add (or Options.Resume) found no module to add to
+	ErrNoModule = errors.New("No Go module")
+
+	// `git config --global user.name` or `user.email` isn't set
+	ErrGitNotConfigured = errors.New("Git is not configured")
+
+	// A Git action failed
+	ErrGit = errors.New("Failed to create as Git repository")
+
+	// The go command couldn't be run (e.g. to add a dependency)
+	ErrGoToolchainMissing = errors.New("Go toolchain not found")
+)
+
+// Returned (wrapped) when ctx is canceled during creation, e.g. by Ctrl-C
+var ErrInterrupted = errors.New("Interrupted")
+
+// Returned (wrapped) when ctx times out during creation
+var ErrTimedOut = errors.New("Timed out")
+
+// Returned (wrapped) by ReleasePrep when a check fails
+var ErrNotReleasable = errors.New("Not ready to release")
+
+// A wrappedError keeps its own message, but matches its kind, and unwraps to its cause (if any)
+type wrappedError struct {
+	message string
+	kind    error
+	cause   error
+}
+
+// wrap returns an error with a formatted message, of a kind (e.g. ErrDirExists), caused by cause. Either can be nil.
+func wrap(kind error, cause error, format string, a ...any) error {
+	return &wrappedError{message: fmt.Sprintf(format, a...), kind: kind, cause: cause}
+}
+
+func (e *wrappedError) Error() string {
+	return e.message
+}
+
+func (e *wrappedError) Is(target error) bool {
+	return e.kind != nil && target == e.kind
+}
+
+func (e *wrappedError) Unwrap() error {
+	return e.cause
+}
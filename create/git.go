@@ -0,0 +1,482 @@
+package create
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/packfile"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Initial branch used by the built-in Git implementation when init.defaultBranch is not set
+const gitDefaultBranch string = "main"
+
+// A gitClient carries out the Git actions of a plan
+type gitClient interface {
+	// globalConfig returns the value of a global Git config key (e.g. "user.name"), or "" if unset
+	globalConfig(key string) (string, error)
+
+	// init creates a repository in dir. If initialBranch is nil, the configured default is used.
+	init(dir string, initialBranch *string) error
+
+	// commitAll stages every file in dir (respecting .gitignore) and commits them
+	commitAll(dir string, message string) error
+
+	addRemote(dir string, name string, url string) error
+
+	// setHooksPath sets the directory (relative to dir) that the repository in dir runs hooks from (core.hooksPath)
+	setHooksPath(dir string, hooksPath string) error
+
+	// hooksPath returns the directory that the repository in dir runs hooks from, or "" if it's the default
+	hooksPath(dir string) string
+
+	// bundle writes the repository in dir (every branch, and HEAD) to a bundle file, which can be cloned
+	bundle(dir string, path string) error
+
+	// push pushes branch to the remote with this name, and sets it as the branch's upstream (as git push -u does)
+	push(dir string, remote string, branch string) error
+
+	// hasCommits reports whether the repository in dir has a commit checked out
+	hasCommits(dir string) bool
+
+	// hasRemote reports whether the repository in dir has a remote with this name
+	hasRemote(dir string, name string) bool
+
+	// currentBranch returns the name of the checked out branch, or "" if it can't be determined
+	currentBranch(dir string) string
+
+	// tags returns the names of the repository's tags
+	tags(dir string) ([]string, error)
+
+	// tag creates an annotated tag of the commit checked out
+	tag(dir string, name string, message string) error
+}
+
+// newGitClient returns the client for Git actions. Commands the client runs are killed if ctx is canceled.
+func newGitClient(ctx context.Context, useExecutable bool) gitClient {
+	if useExecutable {
+		return gitExecutable{ctx: ctx}
+	}
+	return goGit{ctx: ctx}
+}
+
+// goGit implements Git actions without needing a git executable
+type goGit struct {
+	ctx context.Context
+}
+
+func (goGit) globalConfig(key string) (string, error) {
+	section, option, ok := strings.Cut(key, ".")
+	if !ok {
+		return "", errors.New("Invalid Git config key: " + key)
+	}
+	cfg, err := loadGlobalGitConfig()
+	if err != nil {
+		return "", err
+	}
+	return cfg.Raw.Section(section).Option(option), nil
+}
+
+// loadGlobalGitConfig reads the global Git config from the file `git config --global` (as --git-exec runs it) reads:
+// $GIT_CONFIG_GLOBAL, or else ~/.gitconfig, or else the XDG config file ($XDG_CONFIG_HOME/git/config, or
+// ~/.config/git/config). Like it, only that one file is read, and [include] and [includeIf] aren't followed. git itself
+// also reads the XDG config file when ~/.gitconfig exists, and follows includes, so a value set only there is missed.
+func loadGlobalGitConfig() (*gitconfig.Config, error) {
+	path := os.Getenv("GIT_CONFIG_GLOBAL")
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(home, ".gitconfig")
+		if _, err := os.Stat(path); err != nil {
+			xdgConfigHome := os.Getenv("XDG_CONFIG_HOME")
+			if xdgConfigHome == "" {
+				xdgConfigHome = filepath.Join(home, ".config")
+			}
+			path = filepath.Join(xdgConfigHome, "git", "config")
+		}
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return gitconfig.NewConfig(), nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return gitconfig.ReadConfig(f)
+}
+
+func (g goGit) init(dir string, initialBranch *string) error {
+	branch := gitDefaultBranch
+	if initialBranch != nil {
+		branch = *initialBranch
+	} else if configuredBranch, err := g.globalConfig("init.defaultBranch"); err == nil && configuredBranch != "" {
+		branch = configuredBranch
+	}
+	start := time.Now()
+	repo, err := git.PlainInit(dir, false)
+	if err == nil {
+		err = repo.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.NewBranchReferenceName(branch)))
+	}
+	trace(g.ctx, "(built-in) git init --initial-branch "+branch, dir, start, err)
+	return err
+}
+
+func (g goGit) commitAll(dir string, message string) (err error) {
+	start := time.Now()
+	defer func() {
+		trace(g.ctx, commandLine([]string{"(built-in)", "git", "commit", "-a", "-m", message}), dir, start, err)
+	}()
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return err
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+	err = worktree.AddWithOptions(&git.AddOptions{All: true})
+	if err != nil {
+		return err
+	}
+	name, err := g.globalConfig("user.name")
+	if err != nil {
+		return err
+	}
+	email, err := g.globalConfig("user.email")
+	if err != nil {
+		return err
+	}
+	_, err = worktree.Commit(message, &git.CommitOptions{
+		Author: &object.Signature{Name: name, Email: email, When: time.Now()},
+	})
+	return err
+}
+
+func (g goGit) addRemote(dir string, name string, url string) error {
+	start := time.Now()
+	repo, err := git.PlainOpen(dir)
+	if err == nil {
+		_, err = repo.CreateRemote(&gitconfig.RemoteConfig{Name: name, URLs: []string{url}})
+	}
+	trace(g.ctx, commandLine([]string{"(built-in)", "git", "remote", "add", name, url}), dir, start, err)
+	return err
+}
+
+func (g goGit) setHooksPath(dir string, hooksPath string) error {
+	start := time.Now()
+	repo, err := git.PlainOpen(dir)
+	if err == nil {
+		var cfg *gitconfig.Config
+		cfg, err = repo.Config()
+		if err == nil {
+			cfg.Raw.Section("core").SetOption("hooksPath", hooksPath)
+			err = repo.SetConfig(cfg)
+		}
+	}
+	trace(g.ctx, commandLine([]string{"(built-in)", "git", "config", "core.hooksPath", hooksPath}), dir, start, err)
+	return err
+}
+
+func (goGit) hooksPath(dir string) string {
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return ""
+	}
+	cfg, err := repo.Config()
+	if err != nil {
+		return ""
+	}
+	return cfg.Raw.Section("core").Option("hooksPath")
+}
+
+func (g goGit) bundle(dir string, path string) (err error) {
+	start := time.Now()
+	defer func() {
+		trace(g.ctx, commandLine([]string{"(built-in)", "git", "bundle", "create", path, "--all"}), dir, start, err)
+	}()
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return err
+	}
+
+	// A v2 bundle is a header listing its references, then a pack of their objects
+	head, err := repo.Head()
+	if err != nil {
+		return err
+	}
+	header := fmt.Sprintf("# v2 git bundle\n%s HEAD\n", head.Hash())
+	refs, err := repo.References()
+	if err != nil {
+		return err
+	}
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() == plumbing.HashReference && (ref.Name().IsBranch() || ref.Name().IsTag()) {
+			header += fmt.Sprintf("%s %s\n", ref.Hash(), ref.Name())
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	hashes := []plumbing.Hash{}
+	objects, err := repo.Storer.IterEncodedObjects(plumbing.AnyObject)
+	if err != nil {
+		return err
+	}
+	err = objects.ForEach(func(o plumbing.EncodedObject) error {
+		hashes = append(hashes, o.Hash())
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	_, err = f.WriteString(header + "\n")
+	if err == nil {
+		_, err = packfile.NewEncoder(f, repo.Storer, false).Encode(hashes, 10)
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func (g goGit) push(dir string, remote string, branch string) (err error) {
+	start := time.Now()
+	defer func() {
+		trace(g.ctx, commandLine([]string{"(built-in)", "git", "push", "-u", remote, branch}), dir, start, err)
+	}()
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return err
+	}
+	ref := plumbing.NewBranchReferenceName(branch)
+	// Over SSH, keys come from ssh-agent
+	err = repo.PushContext(g.ctx, &git.PushOptions{
+		RemoteName: remote,
+		RefSpecs:   []gitconfig.RefSpec{gitconfig.RefSpec(fmt.Sprintf("%s:%s", ref, ref))},
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return err
+	}
+	cfg, err := repo.Config()
+	if err != nil {
+		return err
+	}
+	cfg.Branches[branch] = &gitconfig.Branch{Name: branch, Remote: remote, Merge: ref}
+	return repo.SetConfig(cfg)
+}
+
+func (goGit) hasCommits(dir string) bool {
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return false
+	}
+	_, err = repo.Head()
+	return err == nil
+}
+
+func (goGit) hasRemote(dir string, name string) bool {
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return false
+	}
+	_, err = repo.Remote(name)
+	return err == nil
+}
+
+func (goGit) currentBranch(dir string) string {
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return ""
+	}
+	head, err := repo.Storer.Reference(plumbing.HEAD)
+	if err != nil || head.Type() != plumbing.SymbolicReference {
+		return ""
+	}
+	return head.Target().Short()
+}
+
+func (goGit) tags(dir string) ([]string, error) {
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return nil, err
+	}
+	refs, err := repo.Tags()
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		names = append(names, ref.Name().Short())
+		return nil
+	})
+	return names, err
+}
+
+func (g goGit) tag(dir string, name string, message string) (err error) {
+	start := time.Now()
+	defer func() {
+		trace(g.ctx, commandLine([]string{"(built-in)", "git", "tag", "-a", name, "-m", message}), dir, start, err)
+	}()
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return err
+	}
+	taggerName, err := g.globalConfig("user.name")
+	if err != nil {
+		return err
+	}
+	taggerEmail, err := g.globalConfig("user.email")
+	if err != nil {
+		return err
+	}
+	_, err = repo.CreateTag(name, head.Hash(), &git.CreateTagOptions{
+		Tagger:  &object.Signature{Name: taggerName, Email: taggerEmail, When: time.Now()},
+		Message: message,
+	})
+	return err
+}
+
+// gitExecutable implements Git actions by running git, for parity with the user's hooks and config
+type gitExecutable struct {
+	ctx context.Context
+}
+
+func (g gitExecutable) globalConfig(key string) (string, error) {
+	cmdOutput, err := runCommand(g.ctx, "", "git", "config", "--global", key)
+	if err != nil {
+		var exitError *exec.ExitError
+		if errors.As(err, &exitError) && exitError.ExitCode() == 1 {
+			// Key is not set
+			return "", nil
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(cmdOutput)), nil
+}
+
+func (g gitExecutable) init(dir string, initialBranch *string) error {
+	args := []string{"init"}
+	if initialBranch != nil {
+		args = append(args, "--initial-branch", *initialBranch)
+	}
+	_, err := runCommand(g.ctx, dir, "git", args...)
+	return err
+}
+
+func (g gitExecutable) commitAll(dir string, message string) error {
+	if _, err := runCommand(g.ctx, dir, "git", "add", "."); err != nil {
+		return err
+	}
+	_, err := runCommand(g.ctx, dir, "git", "commit", "-m", message)
+	return err
+}
+
+func (g gitExecutable) addRemote(dir string, name string, url string) error {
+	_, err := runCommand(g.ctx, dir, "git", "remote", "add", name, url)
+	return err
+}
+
+func (g gitExecutable) setHooksPath(dir string, hooksPath string) error {
+	_, err := runCommand(g.ctx, dir, "git", "config", "core.hooksPath", hooksPath)
+	return err
+}
+
+func (g gitExecutable) hooksPath(dir string) string {
+	cmdOutput, err := runCommand(g.ctx, dir, "git", "config", "core.hooksPath")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(cmdOutput))
+}
+
+func (g gitExecutable) bundle(dir string, path string) error {
+	// git runs in dir, where a relative path would mean something else
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	_, err = runCommand(g.ctx, dir, "git", "bundle", "create", absPath, "--all")
+	return err
+}
+
+func (g gitExecutable) push(dir string, remote string, branch string) error {
+	_, err := runCommand(g.ctx, dir, "git", "push", "-u", remote, branch)
+	return err
+}
+
+func (g gitExecutable) hasCommits(dir string) bool {
+	_, err := runCommand(g.ctx, dir, "git", "rev-parse", "--verify", "--quiet", "HEAD")
+	return err == nil
+}
+
+func (g gitExecutable) hasRemote(dir string, name string) bool {
+	_, err := runCommand(g.ctx, dir, "git", "remote", "get-url", name)
+	return err == nil
+}
+
+func (g gitExecutable) currentBranch(dir string) string {
+	cmdOutput, err := runCommand(g.ctx, dir, "git", "symbolic-ref", "--short", "HEAD")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(cmdOutput))
+}
+
+func (g gitExecutable) tags(dir string) ([]string, error) {
+	cmdOutput, err := runCommand(g.ctx, dir, "git", "tag", "--list")
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(cmdOutput)), nil
+}
+
+func (g gitExecutable) tag(dir string, name string, message string) error {
+	_, err := runCommand(g.ctx, dir, "git", "tag", "-a", name, "-m", message)
+	return err
+}
+
+// GitHubLogin looks up the user's GitHub login, as gmc --infer does. With gitExec, the git executable is run to read
+// Git config, instead of the built-in implementation.
+func GitHubLogin(ctx context.Context, gitExec bool) (string, error) {
+	return githubLogin(ctx, newGitClient(ctx, gitExec))
+}
+
+// GitGlobalConfig returns the value of a key in the user's global Git config (e.g. user.name), or "" if it isn't set
+func GitGlobalConfig(ctx context.Context, gitExec bool, key string) (string, error) {
+	return newGitClient(ctx, gitExec).globalConfig(key)
+}
+
+// githubLogin looks up the user's GitHub login with the GitHub CLI, falling back to `git config --global github.user`
+func githubLogin(ctx context.Context, client gitClient) (string, error) {
+	cmdOutput, err := runCommand(ctx, "", "gh", "api", "user", "--jq", ".login")
+	login := strings.TrimSpace(string(cmdOutput))
+	if err == nil && login != "" {
+		return login, nil
+	}
+	login, err = client.globalConfig("github.user")
+	if err != nil || login == "" {
+		return "", errors.New("Unable to infer GitHub login: sign in with `gh auth login`, or set `git config --global github.user`")
+	}
+	return login, nil
+}
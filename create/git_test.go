@@ -0,0 +1,59 @@
+package create
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestGlobalConfig(t *testing.T) {
+	home := t.TempDir()
+	xdgConfigHome := filepath.Join(home, "xdg")
+	xdgConfigPath := filepath.Join(xdgConfigHome, "git", "config")
+	homeConfigPath := filepath.Join(home, ".gitconfig")
+	err := os.MkdirAll(filepath.Dir(xdgConfigPath), 0755)
+	if err == nil {
+		err = os.WriteFile(xdgConfigPath, []byte("[user]\n\tname = XDG Gopher\n[init]\n\tdefaultBranch = trunk\n"), 0644)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HOME", home) // Automatically reset
+	t.Setenv("XDG_CONFIG_HOME", xdgConfigHome)
+	t.Setenv("GIT_CONFIG_GLOBAL", "")
+	os.Unsetenv("GIT_CONFIG_GLOBAL")
+
+	// The built-in implementation reads the same file as the git executable
+	clients := map[string]gitClient{"built-in": goGit{ctx: context.Background()}}
+	if _, err := exec.LookPath("git"); err == nil {
+		clients["git"] = gitExecutable{ctx: context.Background()}
+	}
+	tests := []struct {
+		homeConfig string
+		expected   map[string]string
+	}{
+		{"", map[string]string{"user.name": "XDG Gopher", "init.defaultBranch": "trunk"}},
+		{"[user]\n\tname = Home Gopher\n", map[string]string{"user.name": "Home Gopher", "init.defaultBranch": ""}},
+	}
+	for _, tc := range tests {
+		if tc.homeConfig != "" {
+			err := os.WriteFile(homeConfigPath, []byte(tc.homeConfig), 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		for name, client := range clients {
+			for key, expected := range tc.expected {
+				actual, err := client.globalConfig(key)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if actual != expected {
+					t.Errorf("Unexpected %s (%s, ~/.gitconfig: %q)\nExpected: %q\nActual  : %q\n", key, name, tc.homeConfig, expected, actual)
+				}
+			}
+		}
+	}
+}
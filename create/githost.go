@@ -0,0 +1,83 @@
+package create
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/mod/module"
+)
+
+// Protocols that a Git remote's URL can use
+const remoteProtocolSSH string = "ssh"
+const remoteProtocolHTTPS string = "https"
+
+// RemoteProtocols returns the protocols that a Git remote's URL can use, the default first
+func RemoteProtocols() []string {
+	return []string{remoteProtocolSSH, remoteProtocolHTTPS}
+}
+
+// A host of Git repositories that module paths name, with how its URLs are formed and where repositories are created
+type gitHost struct {
+	// First element of the module paths that name the host (e.g. "github.com")
+	host string
+
+	// Number of path elements after the host that name a repository (e.g. 2, for an owner and a name), or 0 for any
+	// number (e.g. GitLab's nested groups)
+	repoElems int
+
+	// Whether URLs end with ".git"
+	gitSuffix bool
+
+	// Page that creates a repository
+	newRepoURL string
+}
+
+var gitHosts = []gitHost{
+	{host: "github.com", repoElems: 2, gitSuffix: true, newRepoURL: "https://github.com/new"},
+	{host: "gitlab.com", repoElems: 0, gitSuffix: true, newRepoURL: "https://gitlab.com/projects/new"},
+	{host: "bitbucket.org", repoElems: 2, gitSuffix: true, newRepoURL: "https://bitbucket.org/repo/create"},
+	{host: "codeberg.org", repoElems: 2, gitSuffix: true, newRepoURL: "https://codeberg.org/repo/create"},
+	// Module paths name SourceHut repositories with their owner's ~ (e.g. git.sr.ht/~owner/name)
+	{host: "git.sr.ht", repoElems: 2, gitSuffix: false, newRepoURL: "https://git.sr.ht/create"},
+}
+
+// GitHosts returns the hosts of Git repositories that module paths are known to name
+func GitHosts() []string {
+	hosts := []string{}
+	for _, h := range gitHosts {
+		hosts = append(hosts, h.host)
+	}
+	return hosts
+}
+
+// gitRemoteForModule returns the URL of the Git repository that a module path names, with protocol (ssh, or else https),
+// and where the repository can be created, if its host is known. A module path without a host (e.g. "mymodule") names
+// no repository.
+func gitRemoteForModule(modulePath string, protocol string) (url string, newRepoURL string) {
+	host, repoPath, ok := strings.Cut(modulePath, "/")
+	if !ok {
+		return "", ""
+	}
+	format := "git@%s:%s"
+	if protocol == remoteProtocolHTTPS {
+		format = "https://%s/%s"
+	}
+	for _, h := range gitHosts {
+		if h.host != host {
+			continue
+		}
+		// The repository is named by the path, less any major version suffix (e.g. /v2) and package directories
+		if prefix, _, ok := module.SplitPathVersion(modulePath); ok {
+			repoPath = strings.TrimPrefix(prefix, host+"/")
+		}
+		if elems := strings.Split(repoPath, "/"); h.repoElems > 0 && len(elems) > h.repoElems {
+			repoPath = strings.Join(elems[:h.repoElems], "/")
+		}
+		url = fmt.Sprintf(format, host, repoPath)
+		if h.gitSuffix {
+			url += ".git"
+		}
+		return url, h.newRepoURL
+	}
+	return fmt.Sprintf(format+".git", host, repoPath), ""
+}
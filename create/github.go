@@ -0,0 +1,118 @@
+package create
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Where the GitHub API is, unless $GITHUB_API_URL (as GitHub Actions and GitHub Enterprise Server set it) says otherwise
+const githubAPIURL string = "https://api.github.com"
+
+// githubToken returns a token to call the GitHub API with: $GITHUB_TOKEN, or else the GitHub CLI's
+func githubToken(ctx context.Context) (string, error) {
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		return token, nil
+	}
+	cmdOutput, err := runCommand(ctx, "", "gh", "auth", "token")
+	token := strings.TrimSpace(string(cmdOutput))
+	if err != nil || token == "" {
+		return "", errors.New("No GitHub token to create the remote repository with: set GITHUB_TOKEN, or sign in with `gh auth login`")
+	}
+	return token, nil
+}
+
+// githubRepoExists reports whether the repository exists, as far as the token can see
+func githubRepoExists(ctx context.Context, token string, repo *githubRepo) bool {
+	return githubRequest(ctx, token, http.MethodGet, fmt.Sprintf("/repos/%s/%s", repo.Owner, repo.Name), nil, nil) == nil
+}
+
+// createGitHubRepo creates an empty repository, for the user if they're its owner, or else in the organization that is
+func createGitHubRepo(ctx context.Context, token string, repo *githubRepo, public bool) error {
+	var user struct {
+		Login string `json:"login"`
+	}
+	err := githubRequest(ctx, token, http.MethodGet, "/user", nil, &user)
+	if err != nil {
+		return err
+	}
+	path := fmt.Sprintf("/orgs/%s/repos", repo.Owner)
+	if strings.EqualFold(user.Login, repo.Owner) {
+		path = "/user/repos"
+	}
+	body := struct {
+		Name    string `json:"name"`
+		Private bool   `json:"private"`
+	}{repo.Name, !public}
+	return githubRequest(ctx, token, http.MethodPost, path, body, nil)
+}
+
+// deleteGitHubRepo deletes a repository, which the token needs the delete_repo scope for
+func deleteGitHubRepo(ctx context.Context, token string, repo *githubRepo) error {
+	return githubRequest(ctx, token, http.MethodDelete, fmt.Sprintf("/repos/%s/%s", repo.Owner, repo.Name), nil, nil)
+}
+
+// githubRequest calls the GitHub API, sending body and decoding the response into result, if either is given
+func githubRequest(ctx context.Context, token string, method string, path string, body any, result any) error {
+	apiURL := os.Getenv("GITHUB_API_URL")
+	if apiURL == "" {
+		apiURL = githubAPIURL
+	}
+	url := strings.TrimSuffix(apiURL, "/") + path
+
+	var reqBody io.Reader
+	if body != nil {
+		content, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(content)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	start := time.Now()
+	resp, err := http.DefaultClient.Do(req)
+	trace(ctx, method+" "+url, "", start, err)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		// Errors explain themselves, with details for each field that was refused (e.g. a name that's taken)
+		var apiError struct {
+			Message string `json:"message"`
+			Errors  []struct {
+				Message string `json:"message"`
+			} `json:"errors"`
+		}
+		message := resp.Status
+		if json.NewDecoder(resp.Body).Decode(&apiError) == nil && apiError.Message != "" {
+			message += ": " + apiError.Message
+			for _, detail := range apiError.Errors {
+				if detail.Message != "" {
+					message += ": " + detail.Message
+				}
+			}
+		}
+		return errors.New(fmt.Sprintf("GitHub API: %s %s: %s", method, path, message))
+	}
+	if result != nil {
+		return json.NewDecoder(resp.Body).Decode(result)
+	}
+	return nil
+}
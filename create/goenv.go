@@ -0,0 +1,35 @@
+package create
+
+import (
+	"context"
+	"strings"
+)
+
+// Settings of go env that determine where (and how securely) Go commands fetch modules
+var goEnvSettings = []string{"GOFLAGS", "GOPROXY", "GOPRIVATE", "GONOPROXY", "GONOSUMDB", "GOSUMDB", "GOINSECURE"}
+
+// goEnv returns the settings that Go commands run with, on top of the user's go env: GOPROXY, if the plan overrides it
+func (p *plan) goEnv() []string {
+	if p.goProxy == "" {
+		return nil
+	}
+	return []string{"GOPROXY=" + p.goProxy}
+}
+
+// traceGoEnv reports (and logs) the go env settings that Go commands fetch modules with, if ctx has a trace or a log
+func (p *plan) traceGoEnv(ctx context.Context) {
+	if !traced(ctx) {
+		return
+	}
+	cmdOutput, err := p.goCommand(ctx, "", append([]string{"env"}, goEnvSettings...)...)
+	if err != nil {
+		// The failure is traced with the command
+		return
+	}
+	values := strings.Split(strings.TrimSuffix(string(cmdOutput), "\n"), "\n")
+	for i, name := range goEnvSettings {
+		if i < len(values) {
+			tracef(ctx, "    %s=%s", name, values[i])
+		}
+	}
+}
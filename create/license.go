@@ -0,0 +1,85 @@
+package create
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+//go:embed licenses
+var licenseTemplates embed.FS
+
+const licensesDir string = "licenses"
+const licenseFileName string = "LICENSE"
+
+// Licenses that can be generated, keyed by (lowercase) SPDX identifier
+var licenseNames = map[string]string{
+	"mit":          "MIT License",
+	"apache-2.0":   "Apache License 2.0",
+	"bsd-3-clause": "BSD 3-Clause License",
+}
+
+var licenseIds = []string{"mit", "apache-2.0", "bsd-3-clause"}
+
+// Licenses returns the (lowercase) SPDX identifiers of the licenses that can be added
+func Licenses() []string {
+	return append([]string{}, licenseIds...)
+}
+
+type license struct {
+	id     string
+	author string
+	year   int
+}
+
+func (l *license) name() string {
+	return licenseNames[l.id]
+}
+
+// content renders the full text of the license
+func (l *license) content() ([]byte, error) {
+	tmpl, err := template.ParseFS(licenseTemplates, filepath.Join(licensesDir, l.id+".txt"))
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, struct {
+		Year   int
+		Author string
+	}{l.year, l.author})
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func parseLicenseId(id string) (string, error) {
+	id = strings.ToLower(id)
+	if _, ok := licenseNames[id]; !ok {
+		return "", errors.New(fmt.Sprintf("Error: Unsupported license: %s (supported: %s)", id, strings.Join(licenseIds, ", ")))
+	}
+	return id, nil
+}
+
+// Author that a dry run describes the license with, since it doesn't look up the Git user.name
+const dryRunLicenseAuthor string = "<git user.name>"
+
+// licenseAuthor looks up the name to attribute the license to: the Git user.name, or else the configured author. A dry
+// run runs no git, so it looks up neither.
+func licenseAuthor(client gitClient, configured string, dryRun bool) (string, error) {
+	if dryRun {
+		return dryRunLicenseAuthor, nil
+	}
+	author, err := client.globalConfig("user.name")
+	if err != nil || author == "" {
+		if configured != "" {
+			return configured, nil
+		}
+		return "", wrap(ErrGitNotConfigured, err, "`git config --global user.name` (or an author in gmc's config) must be set to attribute the license")
+	}
+	return author, nil
+}
@@ -0,0 +1,169 @@
+package create
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Directory (in the module's directory) of what gmc records about the module
+const manifestDirName string = ".gmc"
+
+const manifestFileName string = "manifest.json"
+
+// A manifest records how a module was created, and what each file gmc generated contained, so that generated files can
+// be told apart from later changes (e.g. to upgrade or regenerate them safely)
+type manifest struct {
+	GmcVersion string `json:"gmcVersion"`
+	Module     string `json:"module"`
+	GoVersion  string `json:"goVersion"`
+	// Flags the module was created with, if known (e.g. "--ci=github")
+	Flags []string `json:"flags"`
+	// Asset sets the module's files came from (e.g. "default", "ci-github")
+	Templates []string       `json:"templates"`
+	Files     []manifestFile `json:"files"`
+	// Whether the module was made by hand and adopted with `gmc adopt`, so that its flags and templates are inferred,
+	// and its files are recorded as they were when adopted
+	Adopted bool `json:"adopted,omitempty"`
+}
+
+type manifestFile struct {
+	Path   string `json:"path"` // Relative to the module's directory, with forward slashes
+	SHA256 string `json:"sha256"`
+	// Asset the file was generated from (e.g. "default/main.go"), if any
+	Template string `json:"template,omitempty"`
+}
+
+// addManifest adds the steps that write the module's manifest, of the files planned so far (except those that will be
+// kept, if the module is created in an existing directory). They're added before the Git repository's initial commit,
+// so that the manifest is committed along with the files it records. go.mod isn't recorded, since Go commands change it.
+func (p *plan) addManifest(inExistingDir bool) error {
+	m := manifest{
+		GmcVersion: Version,
+		Module:     p.module,
+		GoVersion:  p.goVersion,
+		Flags:      p.flags,
+		Templates:  p.assetSets,
+		Files:      []manifestFile{},
+	}
+	if m.Flags == nil {
+		m.Flags = []string{}
+	}
+	if m.Templates == nil {
+		m.Templates = []string{}
+	}
+	for _, s := range p.steps {
+		if s.action != actionCreateFile {
+			continue
+		}
+		if _, err := os.Stat(s.path); inExistingDir && err == nil {
+			continue
+		}
+		f, err := p.manifestFile(s)
+		if err != nil {
+			return err
+		}
+		m.Files = append(m.Files, f)
+	}
+	return p.addManifestSteps(m)
+}
+
+// updateManifest adds the steps that record, in the existing module's manifest (if it has one), the flags, asset sets,
+// and files the plan adds, so that the manifest stays current as features are added with `gmc add`. A file the manifest
+// already records is recorded as it's now generated.
+func (p *plan) updateManifest() error {
+	path := filepath.Join(p.dir, manifestDirName, manifestFileName)
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	var m manifest
+	err = json.Unmarshal(content, &m)
+	if err != nil {
+		return errors.New(fmt.Sprintf("Invalid manifest: %s: %s", path, err))
+	}
+
+	for _, flag := range p.flags {
+		if !containsString(m.Flags, flag) {
+			m.Flags = append(m.Flags, flag)
+		}
+	}
+	for _, assetSet := range p.assetSets {
+		if !containsString(m.Templates, assetSet) {
+			m.Templates = append(m.Templates, assetSet)
+		}
+	}
+	for _, s := range p.steps {
+		if s.action != actionCreateFile {
+			continue
+		}
+		f, err := p.manifestFile(s)
+		if err != nil {
+			return err
+		}
+		recorded := false
+		for i, existing := range m.Files {
+			if existing.Path == f.Path {
+				m.Files[i] = f
+				recorded = true
+			}
+		}
+		if !recorded {
+			m.Files = append(m.Files, f)
+		}
+	}
+	return p.addManifestSteps(m)
+}
+
+// manifestFile records the file that s creates, as the manifest does
+func (p *plan) manifestFile(s step) (manifestFile, error) {
+	path, err := filepath.Rel(p.dir, s.path)
+	if err != nil {
+		return manifestFile{}, err
+	}
+	checksum := sha256.Sum256(s.content)
+	return manifestFile{
+		Path:     filepath.ToSlash(path),
+		SHA256:   hex.EncodeToString(checksum[:]),
+		Template: p.assetSources[s.path],
+	}, nil
+}
+
+// addManifestSteps adds the steps that write m as the module's manifest
+func (p *plan) addManifestSteps(m manifest) error {
+	content, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// The directory of a manifest being updated already exists
+	dir := filepath.Join(p.dir, manifestDirName)
+	manifestSteps := []step{}
+	if _, err := os.Stat(dir); err != nil {
+		manifestSteps = append(manifestSteps, step{action: actionCreateDir, path: dir})
+	}
+	manifestSteps = append(manifestSteps, step{action: actionCreateFile, path: filepath.Join(dir, manifestFileName), content: append(content, '\n')})
+	for i, s := range p.steps {
+		if s.action == actionCommitGitRepo {
+			p.steps = append(p.steps[:i], append(manifestSteps, p.steps[i:]...)...)
+			return nil
+		}
+	}
+	p.steps = append(p.steps, manifestSteps...)
+	return nil
+}
+
+func containsString(ss []string, s string) bool {
+	for _, e := range ss {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,1753 @@
+package create
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"golang.org/x/mod/modfile"
+)
+
+// A plan describes everything that creating a module (or adding to one) will do, without doing any of it
+type plan struct {
+	task         string // e.g. "creating Go module"
+	existing     bool   // Whether the module already exists
+	resuming     bool   // Whether an interrupted run is being finished
+	rerunning    bool   // Whether the module's directory exists, and must already hold exactly what's planned
+	module       string
+	moduleBase   string
+	dir          string // Where the module's files are
+	repo         *gitRepo
+	license      *license
+	static       bool
+	reproducible bool
+	pgo          bool
+	docker       bool
+	goreleaser   bool
+	targets      []buildTarget
+	i18n         bool
+	layout       string // Package layout added (e.g. apiv1), if any
+	linters      []string
+	mutation     bool
+	notice       bool // Whether NOTICE attributes the dependencies added, as generated with go-licenses
+	secretScan   bool
+	scorecard    bool
+	splitCmd     bool   // Whether the module is a library, with its command in a separate module
+	examples     bool   // Whether the library has example programs, in a separate module
+	fmtCheck     string // Formatter that CI checks formatting with, if any
+	scripts      bool
+	powershell   bool
+	editor       string
+	wsl          bool // Whether gmc is running under WSL
+	goVersion    string
+	toolchain    string // Toolchain pinned in go.mod (e.g. go1.22.3), if any
+	// Module proxy that the module resolves dependencies through exclusively, if any
+	privateProxy *url.URL
+	gitUrl       string
+	createRemote bool   // Whether the remote repository is created on GitHub
+	push         bool   // Whether the initial commit is pushed to the remote
+	pushErr      error  // Why the push failed, if it did
+	failOnVuln   bool   // Whether known vulnerabilities in dependencies fail the run, instead of being noted
+	vulnsNote    string // What the vulnerability check found (or why it couldn't check), if it's worth noting
+	publicRemote bool   // Whether the remote repository created is public, instead of private
+	// Protocol of the Git remote's URL: ssh or https
+	remoteProtocol string
+	bundle         string // Where the Git repository is bundled, if it is
+	bundleOnly     bool   // Whether the module's directory is removed once bundled
+	container      string // Image that Go commands run in, instead of the installed toolchain, if set
+	goToolchain    string // go executable that Go commands run with, instead of the one on PATH, if set
+	goProxy        string // GOPROXY that Go commands run with, instead of the user's, if set
+	flags          []string
+	// Asset sets that files came from, and the asset each file came from, for the manifest
+	assetSets    []string
+	assetSources map[string]string
+	steps        []step
+}
+
+type stepAction string
+
+const (
+	actionCreateDir      stepAction = "createDir"
+	actionCreateFile     stepAction = "createFile"
+	actionInitGoModule   stepAction = "initGoModule"
+	actionAddDependency  stepAction = "addDependency"
+	actionCheckVulns     stepAction = "checkVulns"
+	actionWriteNotice    stepAction = "writeNotice"
+	actionCheckGitConfig stepAction = "checkGitConfig"
+	actionInitGitRepo    stepAction = "initGitRepo"
+	actionCommitGitRepo  stepAction = "commitGitRepo"
+	actionAddGitRemote   stepAction = "addGitRemote"
+	actionCreateRemote   stepAction = "createRemoteRepo"
+	actionPushGitRepo    stepAction = "pushGitRepo"
+	actionSetGitHooks    stepAction = "setGitHooks"
+	actionBundleGitRepo  stepAction = "bundleGitRepo"
+	actionRemoveDir      stepAction = "removeDir"
+	actionNote           stepAction = "note"
+)
+
+// A step is a single action in a plan
+type step struct {
+	action     stepAction
+	path       string
+	content    []byte
+	executable bool
+	arg        string
+}
+
+// Options that determine what a plan will create
+type planOptions struct {
+	shortName      string // Module name as given, if it was expanded with a prefix
+	dir            string // Existing directory to create the module in, instead of a new one
+	outputDir      string // Directory to create the module's directory in, if not the current one
+	fullPath       bool   // Whether the module's directory is at its full module path, instead of its last element
+	force          bool   // Whether to create the module in its directory even if the directory exists
+	resume         bool   // Whether to finish creating a module in its directory, skipping what was already done
+	dryRun         bool   // Whether the plan is only described, so that no Go command may run while planning
+	repo           *gitRepo
+	createRemote   bool // Whether to create the remote repository on GitHub
+	push           bool // Whether to push the initial commit to the remote
+	publicRemote   bool // Whether the remote repository created is public, instead of private
+	remoteProtocol string
+	bundle         bool // Whether to bundle the Git repository next to the module's directory
+	bundleOnly     bool // Whether to remove the module's directory once the Git repository is bundled
+	inContainer    bool // Whether to run Go commands in a container, instead of with the installed toolchain
+	goToolchain    string
+	goProxy        string
+	flags          []string // Flags the module is created with, as recorded in its manifest
+	extraDirs      []string
+	ci             ciProvider
+	license        *license
+	static         bool
+	reproducible   bool
+	pgo            bool
+	goreleaser     bool
+	targets        []buildTarget
+	docker         bool
+	embedAssets    bool
+	i18n           bool
+	featureFlags   string
+	layout         string
+	golden         bool
+	mutation       bool
+	notice         bool
+	secretScan     bool
+	scorecard      bool
+	splitCmd       bool
+	examples       bool
+	propTests      bool
+	fmtCheck       string
+	make           bool
+	taskfile       bool
+	scripts        bool
+	powershell     bool
+	bootstrap      bool
+	goVersion      string
+	toolchain      string
+	privateProxy   *url.URL
+	linters        []string
+	editor         string
+	failOnVuln     bool
+}
+
+func newPlan(ctx context.Context, module string, opts planOptions) (*plan, error) {
+	p := &plan{
+		module:         module,
+		task:           "creating Go module",
+		moduleBase:     filepath.Base(module),
+		dir:            filepath.Base(module),
+		repo:           opts.repo,
+		createRemote:   opts.createRemote,
+		push:           opts.push,
+		publicRemote:   opts.publicRemote,
+		remoteProtocol: opts.remoteProtocol,
+		failOnVuln:     opts.failOnVuln,
+		license:        opts.license,
+		static:         opts.static,
+		reproducible:   opts.reproducible,
+		pgo:            opts.pgo,
+		docker:         opts.docker,
+		goreleaser:     opts.goreleaser,
+		targets:        opts.targets,
+		i18n:           opts.i18n,
+		layout:         opts.layout,
+		linters:        opts.linters,
+		mutation:       opts.mutation,
+		notice:         opts.notice,
+		secretScan:     opts.secretScan,
+		scorecard:      opts.scorecard,
+		splitCmd:       opts.splitCmd,
+		examples:       opts.examples,
+		fmtCheck:       opts.fmtCheck,
+		scripts:        opts.scripts,
+		powershell:     opts.powershell,
+		editor:         opts.editor,
+		toolchain:      opts.toolchain,
+		privateProxy:   opts.privateProxy,
+		goToolchain:    opts.goToolchain,
+		goProxy:        opts.goProxy,
+		flags:          opts.flags,
+		assetSources:   map[string]string{},
+		wsl:            runningInWSL(),
+	}
+
+	// Dependencies can only be resolved through the private proxy, unless another one is given
+	if p.goProxy == "" && p.privateProxy != nil {
+		p.goProxy = p.privateProxy.String()
+	}
+
+	// Explain where the module path came from
+	if opts.shortName != "" {
+		p.add(step{action: actionNote, arg: fmt.Sprintf("Expanded %s to %s (use --local to keep it as is)", opts.shortName, module)})
+	}
+
+	// Create module directory
+	if opts.fullPath {
+		p.dir = filepath.FromSlash(module)
+	}
+	if opts.outputDir != "" {
+		p.dir = filepath.Join(opts.outputDir, p.dir)
+	}
+	inExistingDir := opts.dir != ""
+	if inExistingDir {
+		p.dir = opts.dir
+	}
+	if opts.resume {
+		// Only a module that was started can be resumed
+		m, err := readModule(p.dir)
+		if err != nil || m.path != module {
+			return nil, wrap(ErrNoModule, nil, "Nothing to resume: %s has no go.mod for %s", p.dir, module)
+		}
+		p.resuming = true
+		inExistingDir = false
+	} else if !inExistingDir {
+		if _, err := os.Stat(p.dir); err == nil {
+			// Without --force, the directory is only acceptable if it already holds exactly this module
+			p.rerunning = !opts.force
+			inExistingDir = opts.force
+		} else {
+			// At its full path, the module's directory may be the first of several that don't exist yet (e.g. for
+			// github.com/jbrudvik/mymodule, github.com and github.com/jbrudvik too)
+			if opts.fullPath {
+				p.addParentDirs(opts.outputDir, module)
+			}
+			p.add(step{action: actionCreateDir, path: p.dir})
+		}
+	}
+
+	// Unless pinned, match the Go version to the pinned toolchain, or else the installed toolchain (or latest container
+	// image), when there is one. A dry run runs no Go commands, so it matches the module's go.mod, if it was already
+	// started, or else assumes the default.
+	if opts.inContainer {
+		p.container = goContainerImage("")
+	}
+	p.goVersion = opts.goVersion
+	if p.goVersion == "" && p.toolchain != "" {
+		p.goVersion = toolchainLanguageVersion(p.toolchain)
+	}
+	if p.goVersion == "" {
+		p.goVersion = defaultGoVersion
+		if !opts.dryRun {
+			if goVersion, err := p.toolchainGoVersion(ctx); err == nil {
+				p.goVersion = goVersion
+			}
+		} else if m, err := readModule(p.dir); err == nil && m.path == module {
+			p.goVersion = m.goVersion
+		}
+	}
+	if opts.inContainer {
+		p.container = goContainerImage(p.goVersion)
+		if p.toolchain != "" {
+			p.container = goContainerImage(p.goToolchainVersion())
+		}
+	}
+
+	if opts.bundle || opts.bundleOnly {
+		p.bundle = filepath.Join(p.dir, "..", p.moduleBase+".bundle")
+		p.bundleOnly = opts.bundleOnly
+	}
+
+	// Never create a module inside an existing one
+	if inExistingDir {
+		if _, err := os.Stat(filepath.Join(p.dir, goModFileName)); err == nil {
+			return nil, wrap(ErrModuleExists, nil, "Already a Go module (use `%s add` to add features)", Name)
+		}
+	}
+
+	if p.wsl {
+		p.addWSLNotes()
+	}
+
+	// Create go.mod
+	goMod, err := goModContent(module, p.goVersion, p.toolchain)
+	if err != nil {
+		return nil, err
+	}
+	p.add(step{action: actionInitGoModule, path: p.dir, content: goMod, arg: module})
+
+	// Copy over assets: a command, or a library with its command (and any examples) in separate modules
+	if p.splitCmd {
+		err = p.addSplitCmd()
+		if err == nil && p.examples {
+			err = p.addExamples()
+		}
+	} else {
+		err = p.addEmbeddedFS(assets, assetsDefaultDir)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Recommend the pinned toolchain and the private proxy to direnv
+	if p.toolchain != "" || p.privateProxy != nil {
+		err = p.addEmbeddedFS(assets, "envrc")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Copy over extras
+	for _, extraDir := range opts.extraDirs {
+		err = p.addEmbeddedFS(assets, extraDir)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Add profile-guided optimization scaffolding
+	if p.pgo {
+		err = p.addEmbeddedFS(assets, "pgo")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Add embedded assets example
+	if opts.embedAssets {
+		err = p.addEmbeddedFS(assets, "embed-assets")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Add internationalization
+	if opts.i18n {
+		err = p.addEmbeddedFS(assets, "i18n")
+		if err != nil {
+			return nil, err
+		}
+		p.add(step{action: actionAddDependency, path: p.dir, arg: "golang.org/x/text"})
+	}
+
+	// Add feature flags
+	if opts.featureFlags != "" {
+		err = p.addEmbeddedFS(assets, "feature-flags-"+opts.featureFlags)
+		if err != nil {
+			return nil, err
+		}
+		p.add(step{action: actionAddDependency, path: p.dir, arg: featureFlagsDependencies[opts.featureFlags]})
+	}
+
+	// Add package layout
+	if opts.layout != "" {
+		err = p.addEmbeddedFS(assets, layoutAssetSets[opts.layout])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Add property-based testing of the library
+	if opts.propTests {
+		err = p.addEmbeddedFS(assets, "prop-tests")
+		if err != nil {
+			return nil, err
+		}
+		p.add(step{action: actionAddDependency, path: p.dir, arg: "pgregory.net/rapid"})
+	}
+
+	// Add golden file testing
+	if opts.golden {
+		err = p.addEmbeddedFS(assets, "golden")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Add mutation testing
+	if p.mutation {
+		err = p.addEmbeddedFS(assets, "mutation")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Add the template that NOTICE is generated from
+	if p.notice {
+		err = p.addEmbeddedFS(assets, "notice")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Add secret scanning
+	if p.secretScan {
+		err = p.addEmbeddedFS(assets, "secret-scan")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Add linter configuration
+	if p.linters != nil {
+		err = p.addEmbeddedFS(assets, "lint")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Add formatting conventions, which CI checks
+	if p.fmtCheck != "" {
+		err = p.addEmbeddedFS(assets, "fmt-check")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Add CI configuration
+	if opts.ci != nil {
+		err = opts.ci.configure(p)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Document the private proxy's setup
+	if p.privateProxy != nil {
+		err = p.addEmbeddedFS(assets, "private-proxy")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Add supply-chain security checks
+	if p.scorecard {
+		err = p.addEmbeddedFS(assets, "scorecard")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Add release configuration
+	if opts.goreleaser {
+		err = p.addEmbeddedFS(assets, "goreleaser")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Add container image build
+	if p.docker {
+		err = p.addEmbeddedFS(assets, "docker")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Add Makefile
+	if opts.make {
+		err = p.addEmbeddedFS(assets, "make")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Add Taskfile
+	if opts.taskfile {
+		err = p.addEmbeddedFS(assets, "taskfile")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Add scripts
+	if opts.scripts {
+		err = p.addEmbeddedFS(assets, "scripts")
+		if err != nil {
+			return nil, err
+		}
+	}
+	if opts.powershell {
+		err = p.addEmbeddedFS(assets, "powershell")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Add bootstrap script (replacing the scripts' bootstrap, which only resolves dependencies)
+	if opts.bootstrap {
+		err = p.addEmbeddedFS(assets, "bootstrap-script")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Create .gitignore
+	gitignoreEntries := []string{p.moduleBase}
+	if p.splitCmd {
+		// The binary, which a bare name would match along with its directory
+		gitignoreEntries = []string{filepath.ToSlash(filepath.Join(p.cmdDir(), p.moduleBase))}
+	}
+	if opts.powershell {
+		gitignoreEntries = append(gitignoreEntries, p.moduleBase+".exe")
+	}
+	if opts.goreleaser {
+		gitignoreEntries = append(gitignoreEntries, "dist/")
+	}
+	if len(opts.targets) > 0 {
+		gitignoreEntries = append(gitignoreEntries, "bin/dist/")
+	}
+	p.add(step{
+		action:  actionCreateFile,
+		path:    filepath.Join(p.dir, gitignoreFileName),
+		content: []byte(strings.Join(gitignoreEntries, "\n")),
+	})
+
+	// Create LICENSE
+	if p.license != nil {
+		err = p.addLicense()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Check the dependencies added for known vulnerabilities, before they're committed
+	for _, s := range p.steps {
+		if s.action == actionAddDependency {
+			p.add(step{action: actionCheckVulns, path: p.dir})
+			break
+		}
+	}
+
+	// Attribute the dependencies added, once they're all added
+	if p.notice {
+		p.add(step{action: actionWriteNotice, path: filepath.Join(p.dir, noticeFileName)})
+	}
+
+	// Set up Git repo
+	if p.repo != nil {
+		p.addGitRepo()
+	}
+
+	// Record what was generated
+	err = p.addManifest(inExistingDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.rerunning {
+		if !p.alreadyCreated(ctx) {
+			return nil, wrap(ErrDirExists, nil, "Directory already exists: %s (use --force to create the module in it)", p.dir)
+		}
+		p.existing = true
+		p.steps = []step{{action: actionNote, arg: fmt.Sprintf("Already created with these options: %s (nothing to do)", p.dir)}}
+	}
+	if inExistingDir {
+		p.keepExisting()
+	}
+	if p.resuming {
+		p.skipDone(ctx)
+	}
+
+	return p, nil
+}
+
+// skipDone drops the steps of an interrupted run that were already done. A file is only done if it holds all of its
+// content, since the interruption may have cut it short.
+func (p *plan) skipDone(ctx context.Context) {
+	steps := []step{}
+	gitSteps := 0
+	for _, s := range p.steps {
+		done := false
+		switch s.action {
+		case actionCreateDir:
+			info, err := os.Stat(s.path)
+			done = err == nil && info.IsDir()
+		case actionCreateFile:
+			content, err := os.ReadFile(s.path)
+			done = err == nil && bytes.Equal(content, s.content)
+		case actionInitGoModule:
+			done = true // Checked before planning
+		case actionAddDependency:
+			done = requiresModule(s.path, s.arg)
+		case actionWriteNotice:
+			_, err := os.Stat(s.path)
+			done = err == nil
+		case actionInitGitRepo:
+			_, err := os.Stat(filepath.Join(p.dir, ".git"))
+			done = err == nil
+		case actionCommitGitRepo:
+			done = p.repo.client.hasCommits(p.dir)
+		case actionAddGitRemote:
+			done = p.repo.client.hasRemote(p.dir, "origin")
+		case actionCreateRemote:
+			done = remoteRepoExists(ctx, s.arg)
+		case actionPushGitRepo:
+			done = false // Pushing what was already pushed changes nothing
+		case actionSetGitHooks:
+			done = p.repo.client.hooksPath(p.dir) == s.arg
+		case actionBundleGitRepo:
+			_, err := os.Stat(s.path)
+			done = err == nil
+		}
+		if done {
+			continue
+		}
+		if isGitAction(s.action) && s.action != actionCheckGitConfig {
+			gitSteps++
+		}
+		steps = append(steps, s)
+	}
+
+	// Git config only needs checking if there's still Git to do
+	p.steps = []step{{action: actionNote, arg: fmt.Sprintf("Resuming in %s, skipping what was already done", p.dir)}}
+	for _, s := range steps {
+		if s.action != actionCheckGitConfig || gitSteps > 0 {
+			p.steps = append(p.steps, s)
+		}
+	}
+}
+
+// alreadyCreated reports whether executing the plan would change nothing: every file exists with the planned
+// content, go.mod declares the module and its dependencies, and the Git repository exists
+func (p *plan) alreadyCreated(ctx context.Context) bool {
+	for _, s := range p.steps {
+		switch s.action {
+		case actionCreateDir:
+			if info, err := os.Stat(s.path); err != nil || !info.IsDir() {
+				return false
+			}
+		case actionCreateFile:
+			content, err := os.ReadFile(s.path)
+			if err != nil || !bytes.Equal(content, s.content) {
+				return false
+			}
+		case actionInitGoModule:
+			m, err := readModule(s.path)
+			if err != nil || m.path != s.arg {
+				return false
+			}
+		case actionAddDependency:
+			if !requiresModule(s.path, s.arg) {
+				return false
+			}
+		case actionWriteNotice:
+			if _, err := os.Stat(s.path); err != nil {
+				return false
+			}
+		case actionCheckGitConfig, actionInitGitRepo, actionCommitGitRepo, actionAddGitRemote, actionPushGitRepo:
+			if _, err := os.Stat(filepath.Join(p.dir, ".git")); err != nil {
+				return false
+			}
+		case actionSetGitHooks:
+			if p.repo.client.hooksPath(p.dir) != s.arg {
+				return false
+			}
+		case actionCreateRemote:
+			if !remoteRepoExists(ctx, s.arg) {
+				return false
+			}
+		case actionBundleGitRepo:
+			if _, err := os.Stat(s.path); err != nil {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// requiresModule reports whether the go.mod in dir requires the module at modulePath
+func requiresModule(dir string, modulePath string) bool {
+	goModPath := filepath.Join(dir, goModFileName)
+	content, err := os.ReadFile(goModPath)
+	if err != nil {
+		return false
+	}
+	f, err := modfile.ParseLax(goModPath, content, nil)
+	if err != nil {
+		return false
+	}
+	for _, r := range f.Require {
+		if r.Mod.Path == modulePath {
+			return true
+		}
+	}
+	return false
+}
+
+// addParentDirs adds steps to create the directories, in outputDir (if given), of each element of module's path before
+// the last (e.g. github.com and github.com/jbrudvik, for github.com/jbrudvik/mymodule) that don't exist yet
+func (p *plan) addParentDirs(outputDir string, module string) {
+	elems := strings.Split(module, "/")
+	dir := outputDir
+	for _, elem := range elems[:len(elems)-1] {
+		dir = filepath.Join(dir, elem)
+		if _, err := os.Stat(dir); err != nil {
+			p.add(step{action: actionCreateDir, path: dir})
+		}
+	}
+}
+
+func (p *plan) add(s step) {
+	if s.action == actionCreateDir || s.action == actionCreateFile {
+		for i, existing := range p.steps {
+			if existing.action == s.action && existing.path == s.path {
+				// Asset sets may share directories (e.g. .github), which only need creating once, and
+				// may replace files from earlier asset sets (e.g. main.go)
+				p.steps[i] = s
+				return
+			}
+		}
+	}
+	p.steps = append(p.steps, s)
+}
+
+// keepExisting drops steps that would overwrite existing files or directories, noting each file that is kept. A kept
+// main.go also keeps out main_test.go, which tests what the generated main.go does.
+func (p *plan) keepExisting() {
+	_, err := os.Stat(filepath.Join(p.dir, "main.go"))
+	keptMain := err == nil
+	steps := []step{}
+	for _, s := range p.steps {
+		if keptMain && s.action == actionCreateFile && s.path == filepath.Join(p.dir, "main_test.go") {
+			continue
+		}
+		if s.action == actionCreateDir || s.action == actionCreateFile {
+			if _, err := os.Stat(s.path); err == nil {
+				if s.action == actionCreateFile {
+					steps = append(steps, step{action: actionNote, arg: fmt.Sprintf("Kept existing file: %s", s.path)})
+				}
+				continue
+			}
+		}
+		steps = append(steps, s)
+	}
+	p.steps = steps
+}
+
+func (p *plan) addEmbeddedFS(srcFS embed.FS, src string) error {
+	return p.addEmbeddedFSAt(srcFS, src, p.dir)
+}
+
+// addEmbeddedFSAt adds an asset set's files under dstDir, which must be in the module's directory
+func (p *plan) addEmbeddedFSAt(srcFS embed.FS, src string, dstDir string) error {
+	srcRoot := filepath.Join(assetsDir, src)
+	p.assetSets = append(p.assetSets, src)
+
+	return fs.WalkDir(srcFS, srcRoot, func(srcPath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if srcRoot == srcPath {
+			// Ignore the root -- we only want its contents
+			return nil
+		}
+
+		dstPath := filepath.Join(dstDir, withoutFilepathPrefix(srcPath, srcRoot))
+
+		if entry.IsDir() {
+			p.add(step{action: actionCreateDir, path: dstPath})
+		} else {
+			fileBytes, err := fs.ReadFile(srcFS, srcPath)
+			if err != nil {
+				return err
+			}
+			if strings.HasSuffix(dstPath, templateFileExtension) {
+				dstPath = strings.TrimSuffix(dstPath, templateFileExtension)
+				fileBytes, err = p.render(srcPath, fileBytes)
+				if err != nil {
+					return err
+				}
+			}
+			// Scripts (e.g. script/bootstrap) are made executable
+			executable := bytes.HasPrefix(fileBytes, []byte("#!"))
+			p.add(step{action: actionCreateFile, path: dstPath, content: fileBytes, executable: executable})
+			if p.assetSources != nil {
+				p.assetSources[dstPath] = strings.TrimPrefix(srcPath, assetsDir+"/")
+			}
+		}
+
+		return nil
+	})
+}
+
+// Data available to asset templates
+type templateData struct {
+	Module     string
+	ModuleBase string
+	GithubRepo *githubRepo
+	GoVersion  string
+	// Go version to install, as named for downloads (e.g. "1.21.0"): the pinned toolchain's, if any
+	GoToolchainVersion string
+	Toolchain          string // Pinned toolchain (e.g. "go1.22.3"), if any
+	PrivateProxy       string // URL of the module proxy that dependencies are resolved through exclusively, if any
+	PrivateProxyHost   string
+	Static             bool
+	Reproducible       bool
+	Pgo                bool
+	Docker             bool
+	Goreleaser         bool
+	Targets            []buildTarget // Platforms to build binaries for into bin/dist/, if any
+	I18n               bool
+	Lint               bool
+	Linters            []string
+	Mutation           bool
+	Notice             bool
+	SecretScan         bool
+	// Command that lists unformatted files, for CI to check formatting with (e.g. "gofmt"), if any
+	FmtCheck string
+	// Whether the module is a library, with its command in a separate module in CmdDir
+	SplitCmd    bool
+	CmdDir      string
+	Library     bool   // Whether the module is a library (with SplitCmd or an API layout), whose API compatibility CI checks
+	Examples    bool   // Whether the library has example programs, in a separate module in examples/
+	PackageName string // Name of the module's root package
+	Scripts     bool
+	PowerShell  bool
+}
+
+// A repository hosted on GitHub, as named by a github.com/<owner>/<name> module path
+type githubRepo struct {
+	Owner string
+	Name  string
+}
+
+func githubRepoForModule(module string) *githubRepo {
+	parts := strings.Split(module, "/")
+	if len(parts) < 3 || parts[0] != "github.com" {
+		return nil
+	}
+	return &githubRepo{Owner: parts[1], Name: parts[2]}
+}
+
+// render executes an asset template against the plan
+func (p *plan) render(name string, content []byte) ([]byte, error) {
+	tmpl, err := template.New(name).Parse(string(content))
+	if err != nil {
+		return nil, err
+	}
+	privateProxy, privateProxyHost := "", ""
+	if p.privateProxy != nil {
+		privateProxy, privateProxyHost = p.privateProxy.String(), p.privateProxy.Hostname()
+	}
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, templateData{
+		Module:             p.module,
+		ModuleBase:         p.moduleBase,
+		GithubRepo:         githubRepoForModule(p.module),
+		GoVersion:          p.goVersion,
+		GoToolchainVersion: p.goToolchainVersion(),
+		Toolchain:          p.toolchain,
+		PrivateProxy:       privateProxy,
+		PrivateProxyHost:   privateProxyHost,
+		Static:             p.static,
+		Reproducible:       p.reproducible,
+		Pgo:                p.pgo,
+		Docker:             p.docker,
+		Goreleaser:         p.goreleaser,
+		Targets:            p.targets,
+		I18n:               p.i18n,
+		Lint:               p.linters != nil,
+		Linters:            p.linters,
+		Mutation:           p.mutation,
+		Notice:             p.notice,
+		SecretScan:         p.secretScan,
+		FmtCheck:           formatterCommands[p.fmtCheck],
+		SplitCmd:           p.splitCmd,
+		Library:            p.splitCmd || p.layout == "apiv1",
+		Examples:           p.examples,
+		CmdDir:             filepath.ToSlash(p.cmdDir()),
+		PackageName:        p.packageName(),
+		Scripts:            p.scripts,
+		PowerShell:         p.powershell,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Matches the language version (e.g. "1.21") at the start of a toolchain version (e.g. "go1.21.3")
+var goLanguageVersionRegexp = regexp.MustCompile(`^go(\d+\.\d+)`)
+
+// toolchainGoVersion returns the language version of the Go toolchain that runs Go commands (e.g. "1.21"), for the go
+// directive
+func (p *plan) toolchainGoVersion(ctx context.Context) (string, error) {
+	cmdOutput, err := p.goCommand(ctx, "", "env", "GOVERSION")
+	if err != nil {
+		return "", wrap(ErrGoToolchainMissing, err, "Failed to look up Go version")
+	}
+	match := goLanguageVersionRegexp.FindStringSubmatch(strings.TrimSpace(string(cmdOutput)))
+	if match == nil {
+		// e.g. a development build: "devel go1.22-a1b2c3d"
+		return "", errors.New(fmt.Sprintf("Unrecognized Go version: %s", strings.TrimSpace(string(cmdOutput))))
+	}
+	return match[1], nil
+}
+
+// goToolchainVersion returns the first release of a Go version (e.g. "1.21" -> "1.21.0"). Releases before Go 1.21 have
+// no ".0" suffix.
+func goToolchainVersion(goVersion string) string {
+	parts := strings.Split(goVersion, ".")
+	if len(parts) != 2 {
+		return goVersion
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil || minor < 21 {
+		return goVersion
+	}
+	return goVersion + ".0"
+}
+
+// goToolchainVersion returns the version of Go to install for the module: the pinned toolchain's, or else the first
+// release of its Go version
+func (p *plan) goToolchainVersion() string {
+	if p.toolchain != "" {
+		return strings.TrimPrefix(p.toolchain, "go")
+	}
+	return goToolchainVersion(p.goVersion)
+}
+
+// goModContent formats a go.mod declaring module, with a go directive for goVersion, and a toolchain directive for
+// toolchain, if set
+func goModContent(module string, goVersion string, toolchain string) ([]byte, error) {
+	f := &modfile.File{}
+	err := f.AddModuleStmt(module)
+	if err != nil {
+		return nil, err
+	}
+	err = f.AddGoStmt(goVersion)
+	if err != nil {
+		return nil, err
+	}
+	content, err := f.Format()
+	if err != nil || toolchain == "" {
+		return content, err
+	}
+	// This version of modfile predates toolchain directives, so it's added as go mod edit -toolchain formats it
+	return append(content, []byte(fmt.Sprintf("\ntoolchain %s\n", toolchain))...), nil
+}
+
+// addSplitCmd adds a library, and a separate module for its command in cmd/<name>, which requires the library through a
+// replace directive
+func (p *plan) addSplitCmd() error {
+	err := p.addEmbeddedFS(assets, "split-cmd")
+	if err != nil {
+		return err
+	}
+
+	cmdDir := filepath.Join(p.dir, p.cmdDir())
+	p.add(step{action: actionCreateDir, path: filepath.Dir(cmdDir)})
+	p.add(step{action: actionCreateDir, path: cmdDir})
+	err = p.addRequiringModule(p.cmdDir())
+	if err != nil {
+		return err
+	}
+	return p.addEmbeddedFSAt(assets, "split-cmd-command", cmdDir)
+}
+
+// addExamples adds example programs that use the library, in a separate module in examples/, so that what they need
+// stays out of the library's go.mod
+func (p *plan) addExamples() error {
+	examplesDir := filepath.Join(p.dir, examplesDirName)
+	p.add(step{action: actionCreateDir, path: examplesDir})
+	err := p.addRequiringModule(examplesDirName)
+	if err != nil {
+		return err
+	}
+	return p.addEmbeddedFSAt(assets, "examples", examplesDir)
+}
+
+// addRequiringModule adds the go.mod of a module in dir (relative to the module's directory), which requires the module
+// through a replace directive, so that it's always built with the module's own code
+func (p *plan) addRequiringModule(dir string) error {
+	f := &modfile.File{}
+	err := f.AddModuleStmt(p.module + "/" + filepath.ToSlash(dir))
+	if err == nil {
+		err = f.AddGoStmt(p.goVersion)
+	}
+	if err == nil {
+		err = f.AddRequire(p.module, "v0.0.0")
+	}
+	if err == nil {
+		var replacement string
+		replacement, err = filepath.Rel(dir, ".")
+		if err == nil {
+			err = f.AddReplace(p.module, "", filepath.ToSlash(replacement), "")
+		}
+	}
+	if err != nil {
+		return err
+	}
+	goMod, err := f.Format()
+	if err != nil {
+		return err
+	}
+	if p.toolchain != "" {
+		goMod = append(goMod, []byte(fmt.Sprintf("\ntoolchain %s\n", p.toolchain))...)
+	}
+	p.add(step{action: actionCreateFile, path: filepath.Join(p.dir, dir, goModFileName), content: goMod})
+	return nil
+}
+
+// cmdDir returns the directory of the module's command, relative to the module's directory, when it's split out
+func (p *plan) cmdDir() string {
+	return filepath.Join("cmd", p.moduleBase)
+}
+
+// packageName returns the name of the module's root package, when it's a library: its last element, without what
+// isn't allowed in a package name (e.g. "go-widget" -> "gowidget")
+func (p *plan) packageName() string {
+	name := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' {
+			return r
+		}
+		return -1
+	}, strings.ToLower(p.moduleBase))
+	name = strings.TrimLeft(name, "0123456789")
+	if name == "" {
+		return "lib"
+	}
+	return name
+}
+
+func (p *plan) addLicense() error {
+	licenseContent, err := p.license.content()
+	if err != nil {
+		return err
+	}
+	p.add(step{
+		action:  actionCreateFile,
+		path:    filepath.Join(p.dir, licenseFileName),
+		content: licenseContent,
+	})
+	return nil
+}
+
+func (p *plan) addGitRepo() {
+	// A Git repository that gmc didn't create is left alone. One it may have created (when resuming, or re-run with the
+	// same options) is planned as it was, to be checked against.
+	if _, err := os.Stat(filepath.Join(p.dir, ".git")); err == nil && !p.resuming && !p.rerunning {
+		p.add(step{action: actionNote, arg: "Already a Git repository"})
+		p.repo = nil
+		return
+	}
+
+	p.add(step{action: actionCheckGitConfig})
+	p.add(step{action: actionInitGitRepo})
+
+	// Run the module's own hooks (e.g. the secret scan), from the first commit
+	if p.secretScan {
+		p.add(step{action: actionSetGitHooks, arg: gitHooksDirName})
+	}
+
+	// Create README.md (with title)
+	readmeContent := fmt.Sprintf("# %s\n\n", p.moduleBase)
+	if github := githubRepoForModule(p.module); p.scorecard && github != nil {
+		readmeContent += fmt.Sprintf("[![OpenSSF Scorecard](https://api.scorecard.dev/projects/github.com/%[1]s/%[2]s/badge)](https://scorecard.dev/viewer/?uri=github.com/%[1]s/%[2]s)\n\n", github.Owner, github.Name)
+	}
+	if p.license != nil {
+		readmeContent += fmt.Sprintf("## License\n\n[%s](%s)\n", p.license.name(), licenseFileName)
+	}
+	p.add(step{
+		action:  actionCreateFile,
+		path:    filepath.Join(p.dir, readmeFileName),
+		content: []byte(readmeContent),
+	})
+
+	p.add(step{action: actionCommitGitRepo, arg: "Initial commit"})
+
+	// Add Git repository remote
+	if gitUrl, _ := gitRemoteForModule(p.module, p.remoteProtocol); gitUrl != "" {
+		p.gitUrl = gitUrl
+		p.add(step{action: actionAddGitRemote, arg: p.gitUrl})
+		if github := githubRepoForModule(p.module); p.createRemote && github != nil {
+			p.add(step{action: actionCreateRemote, arg: github.Owner + "/" + github.Name})
+		}
+		if p.push {
+			p.add(step{action: actionPushGitRepo, arg: "origin"})
+		}
+	} else {
+		p.add(step{action: actionNote, arg: "Unable to add remote for Git repository"})
+	}
+
+	// Bundle Git repository, e.g. to carry into a network the remote can't be reached from
+	if p.bundle != "" {
+		p.add(step{action: actionBundleGitRepo, path: p.bundle})
+		if p.bundleOnly {
+			p.add(step{action: actionRemoveDir, path: p.dir})
+		}
+	}
+}
+
+// checkStdlibOnly returns an error if any planned Go file imports a package outside the standard library
+func (p *plan) checkStdlibOnly() error {
+	fset := token.NewFileSet()
+	for _, s := range p.steps {
+		if s.action == actionAddDependency {
+			return errors.New(fmt.Sprintf("Dependency on non-standard library module: %s", s.arg))
+		}
+		if s.action != actionCreateFile || filepath.Ext(s.path) != ".go" {
+			continue
+		}
+		f, err := parser.ParseFile(fset, s.path, s.content, parser.ImportsOnly)
+		if err != nil {
+			return err
+		}
+		for _, importSpec := range f.Imports {
+			importPath, err := strconv.Unquote(importSpec.Path.Value)
+			if err != nil {
+				return err
+			}
+			// The module's own packages (e.g. imported by its split-out command) aren't dependencies
+			if importPath == p.module || strings.HasPrefix(importPath, p.module+"/") {
+				continue
+			}
+			// Standard library import paths never contain a dot in their first element
+			firstElement := strings.Split(importPath, "/")[0]
+			if strings.Contains(firstElement, ".") {
+				return errors.New(fmt.Sprintf("%s imports non-standard library package: %s", s.path, importPath))
+			}
+		}
+	}
+	return nil
+}
+
+// describe writes what executing the plan would do, without doing it
+func (p *plan) describe(output io.Writer, quiet bool) *Result {
+	r := newReport(p.module, p.goVersion, true)
+	flogf(output, quiet, "%s (dry run): %s\n", capitalize(p.task), p.module)
+
+	for _, s := range p.steps {
+		r.record(s)
+		switch s.action {
+		case actionCreateDir:
+			reportAtPath(output, quiet, "", "Would create", "directory", s.path)
+		case actionCreateFile:
+			reportAtPath(output, quiet, "", "Would create", "file", s.path)
+		case actionInitGoModule:
+			flogln(output, quiet, "- Would initialize Go module")
+		case actionAddDependency:
+			flogf(output, quiet, "- Would add dependency: %s\n", s.arg)
+		case actionCheckVulns:
+			if p.failOnVuln {
+				flogln(output, quiet, "- Would check dependencies for known vulnerabilities, failing if any are found")
+			} else {
+				flogln(output, quiet, "- Would check dependencies for known vulnerabilities")
+			}
+		case actionWriteNotice:
+			reportAtPath(output, quiet, "", "Would create", "file", s.path)
+		case actionInitGitRepo:
+			flogln(output, quiet, "- Would initialize Git repository")
+		case actionCommitGitRepo:
+			flogln(output, quiet, "- Would commit all files to Git repository")
+		case actionAddGitRemote:
+			flogf(output, quiet, "- Would add remote for Git repository: %s\n", s.arg)
+		case actionCreateRemote:
+			flogf(output, quiet, "- Would create remote Git repository on GitHub: %s (%s)\n", s.arg, p.remoteVisibility())
+		case actionPushGitRepo:
+			flogf(output, quiet, "- Would push to remote Git repository: %s\n", p.gitUrl)
+		case actionSetGitHooks:
+			flogf(output, quiet, "- Would run Git hooks from: %s\n", s.arg)
+		case actionBundleGitRepo:
+			reportAtPath(output, quiet, "", "Would create", "bundle", s.path)
+		case actionRemoveDir:
+			reportAtPath(output, quiet, "", "Would remove", "directory", s.path)
+		case actionNote:
+			reportNote(output, quiet, s.arg)
+		}
+	}
+
+	reportFinished(output, quiet, true, p.task, p.module)
+
+	branch := ""
+	if p.repo != nil && p.repo.initialBranch != nil {
+		branch = *p.repo.initialBranch
+	}
+	r.NextSteps = p.nextSteps(branch)
+	reportNextSteps(output, quiet, r.NextSteps)
+
+	return r
+}
+
+// execute carries out every step of the plan, stopping early if ctx is canceled. If a step fails (or is
+// interrupted), whatever the plan created is removed, unless keepPartial is set. With spinning, a spinner shows while
+// each slow step runs.
+func (p *plan) execute(ctx context.Context, output io.Writer, quiet bool, keepPartial bool, spinning bool) (*Result, error) {
+	r := newReport(p.module, p.goVersion, false)
+	flogf(output, quiet, "%s: %s\n", capitalize(p.task), p.module)
+	logf(ctx, "%s: %s (in %s)", capitalize(p.task), p.module, p.dir)
+	p.traceGoEnv(ctx)
+
+	created := []string{}
+	createdRemote := "" // GitHub repository that was created (e.g. "owner/name"), if any
+	for _, s := range p.steps {
+		// Recorded before the step runs, since a failed step may still leave its path behind
+		for _, path := range p.createdPaths(s) {
+			if _, err := os.Lstat(path); errors.Is(err, fs.ErrNotExist) {
+				created = append(created, path)
+			}
+		}
+		err := ctx.Err()
+		if err == nil {
+			stepOutput := output
+			var sp *spinner
+			if message := s.progress(); spinning && message != "" {
+				sp = spin(output, message)
+				stepOutput = sp.writer()
+			}
+			err = p.executeStep(ctx, s, stepOutput, quiet)
+			if sp != nil {
+				sp.stop()
+			}
+			if err != nil {
+				logf(ctx, "Step failed: %s: %s", s, err)
+			} else {
+				logf(ctx, "Step done: %s", s)
+			}
+		}
+		if err != nil {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				err = wrap(ErrTimedOut, contextCause(ctx, err), "%s", ErrTimedOut)
+			} else if ctx.Err() != nil {
+				err = wrap(ErrInterrupted, contextCause(ctx, err), "%s", ErrInterrupted)
+			} else if p.repo != nil && isGitAction(s.action) {
+				err = wrap(ErrGit, err, "Failed to create as Git repository: %s", err)
+			}
+			if !keepPartial {
+				if rollbackErr := rollback(ctx, created, output, quiet); rollbackErr != nil {
+					err = wrap(nil, err, "%s (and failed to remove what was created: %s)", err, rollbackErr)
+				}
+				if createdRemote != "" {
+					if deleteErr := deleteRemote(ctx, createdRemote, output, quiet); deleteErr != nil {
+						err = wrap(nil, err, "%s (and failed to delete the remote Git repository it created, which was left behind: https://github.com/%s: %s)", err, createdRemote, deleteErr)
+					}
+				}
+			}
+			logf(ctx, "Failed %s: %s: %s", p.task, p.module, err)
+			return nil, err
+		}
+		if s.action == actionCreateRemote {
+			createdRemote = s.arg
+		}
+		if s.action == actionPushGitRepo && p.pushErr != nil {
+			s = step{action: actionNote, arg: p.pushFailedNote()}
+		}
+		if s.action == actionCheckVulns && p.vulnsNote != "" {
+			s = step{action: actionNote, arg: p.vulnsNote}
+		}
+		r.record(s)
+	}
+
+	// Checked only once executed, since it runs ssh-add, which a dry run must not
+	if p.repo != nil && p.gitUrl != "" && !strings.HasPrefix(p.gitUrl, "https://") {
+		if hint := sshAgentHint(ctx, runtime.GOOS); hint != "" {
+			s := step{action: actionNote, arg: hint}
+			reportNote(output, quiet, s.arg)
+			r.record(s)
+		}
+	}
+
+	// Output success
+	reportFinished(output, quiet, false, p.task, p.module)
+	logf(ctx, "Finished %s: %s", p.task, p.module)
+
+	branch := ""
+	if p.repo != nil {
+		branch = p.repo.client.currentBranch(p.dir)
+	}
+	r.NextSteps = p.nextSteps(branch)
+	reportNextSteps(output, quiet, r.NextSteps)
+
+	return r, nil
+}
+
+func (p *plan) executeStep(ctx context.Context, s step, output io.Writer, quiet bool) error {
+	switch s.action {
+	case actionCreateDir:
+		err := os.Mkdir(s.path, 0755)
+		if err != nil {
+			return err
+		}
+		reportCreatedDir(output, quiet, s.path)
+	case actionCreateFile:
+		var perm fs.FileMode = 0644
+		if s.executable {
+			perm = 0755
+		}
+		err := os.WriteFile(s.path, s.content, perm)
+		if err != nil {
+			return err
+		}
+		reportCreatedFile(output, quiet, s.path)
+	case actionInitGoModule:
+		err := os.WriteFile(filepath.Join(s.path, goModFileName), s.content, 0644)
+		if err != nil {
+			return err
+		}
+		reportDone(output, quiet, "Initialized Go module")
+	case actionAddDependency:
+		if _, err := p.goCommand(ctx, s.path, "get", s.arg); err != nil {
+			return goCommandError(err, "Failed to add dependency: %s", s.arg)
+		}
+		// Also record the dependency's own requirements (e.g. for tests) in go.mod and go.sum
+		if _, err := p.goCommand(ctx, s.path, "mod", "tidy"); err != nil {
+			return goCommandError(err, "Failed to add dependency: %s", s.arg)
+		}
+		reportDone(output, quiet, "Added dependency: %s", s.arg)
+	case actionCheckVulns:
+		vulns, err := p.checkVulns(ctx, s.path)
+		if err != nil {
+			if p.failOnVuln {
+				return goCommandError(err, "Failed to check dependencies for known vulnerabilities: %s", err)
+			}
+			p.vulnsNote = fmt.Sprintf("Unable to check dependencies for known vulnerabilities: %s", err)
+			reportNote(output, quiet, p.vulnsNote)
+			return nil
+		}
+		if len(vulns) > 0 {
+			note := fmt.Sprintf("Dependencies have known vulnerabilities: %s (see `govulncheck ./...`)", strings.Join(vulns, ", "))
+			if p.failOnVuln {
+				return errors.New(note)
+			}
+			p.vulnsNote = note
+			reportNote(output, quiet, p.vulnsNote)
+			return nil
+		}
+		reportDone(output, quiet, "Checked dependencies for known vulnerabilities: none found")
+	case actionWriteNotice:
+		dir := filepath.Dir(s.path)
+		cmdOutput, err := p.goCommand(ctx, dir, "run", goLicensesPackage, "report", "./...", "--template", noticeTemplateFileName, "--ignore", p.module)
+		if err != nil {
+			return goCommandError(err, "Failed to generate %s", noticeFileName)
+		}
+		if err := os.WriteFile(s.path, cmdOutput, 0644); err != nil {
+			return err
+		}
+		reportCreatedFile(output, quiet, s.path)
+	case actionCheckGitConfig:
+		return checkGitConfig(p.repo.client)
+	case actionInitGitRepo:
+		if err := p.repo.client.init(p.dir, p.repo.initialBranch); err != nil {
+			return wrap(nil, err, "Failed to initialize Git repository")
+		}
+		reportDone(output, quiet, "Initialized Git repository")
+	case actionCommitGitRepo:
+		if err := p.repo.client.commitAll(p.dir, s.arg); err != nil {
+			return wrap(nil, err, "Failed to commit files into Git repository")
+		}
+		reportDone(output, quiet, "Committed all files to Git repository")
+	case actionAddGitRemote:
+		if err := p.repo.client.addRemote(p.dir, "origin", s.arg); err != nil {
+			return wrap(nil, err, "Failed to add remote for Git repository")
+		}
+		reportDone(output, quiet, "Added remote for Git repository: %s", s.arg)
+	case actionCreateRemote:
+		token, err := githubToken(ctx)
+		if err == nil {
+			owner, name, _ := strings.Cut(s.arg, "/")
+			err = createGitHubRepo(ctx, token, &githubRepo{Owner: owner, Name: name}, p.publicRemote)
+		}
+		if err != nil {
+			return wrap(nil, err, "Failed to create remote Git repository: %s", err)
+		}
+		reportDone(output, quiet, "Created remote Git repository on GitHub: https://github.com/%s (%s)", s.arg, p.remoteVisibility())
+	case actionPushGitRepo:
+		// Everything is created by now, so a failed push is reported, and left as a next step, instead of undoing it all
+		branch := p.repo.client.currentBranch(p.dir)
+		err := errors.New("no branch is checked out")
+		if branch != "" {
+			err = p.repo.client.push(p.dir, s.arg, branch)
+		}
+		if err != nil {
+			p.pushErr = err
+			reportNote(output, quiet, p.pushFailedNote())
+			return nil
+		}
+		reportDone(output, quiet, "Pushed %s to remote Git repository: %s", branch, p.gitUrl)
+	case actionSetGitHooks:
+		if err := p.repo.client.setHooksPath(p.dir, s.arg); err != nil {
+			return wrap(nil, err, "Failed to set Git hooks directory")
+		}
+		reportDone(output, quiet, "Set Git hooks directory: %s", s.arg)
+	case actionBundleGitRepo:
+		if err := p.repo.client.bundle(p.dir, s.path); err != nil {
+			return wrap(nil, err, "Failed to bundle Git repository")
+		}
+		reportAtPath(output, quiet, colorGreen, "Created", "bundle", s.path)
+	case actionRemoveDir:
+		if err := os.RemoveAll(s.path); err != nil {
+			return err
+		}
+		reportAtPath(output, quiet, colorGreen, "Removed", "directory", s.path)
+	case actionNote:
+		reportNote(output, quiet, s.arg)
+	}
+	return nil
+}
+
+// contextCause returns the error of a step that failed as ctx ended, so that it's also ctx's error (e.g.
+// context.DeadlineExceeded), whatever the step made of it (e.g. a killed command)
+func contextCause(ctx context.Context, err error) error {
+	if errors.Is(err, ctx.Err()) {
+		return err
+	}
+	return fmt.Errorf("%w: %s", ctx.Err(), err)
+}
+
+// String describes the step, as it's logged
+func (s step) String() string {
+	description := string(s.action)
+	if s.path != "" {
+		description += " " + s.path
+	}
+	if s.arg != "" {
+		description += " " + s.arg
+	}
+	return description
+}
+
+// progress returns what a slow step is doing (e.g. "Adding dependency: ..."), or "" for a step that's quick
+func (s step) progress() string {
+	switch s.action {
+	case actionAddDependency:
+		return fmt.Sprintf("Adding dependency: %s", s.arg)
+	case actionCheckVulns:
+		return "Checking dependencies for known vulnerabilities"
+	case actionWriteNotice:
+		return fmt.Sprintf("Generating %s", noticeFileName)
+	case actionCommitGitRepo:
+		return "Committing all files to Git repository"
+	case actionPushGitRepo:
+		return "Pushing to remote Git repository"
+	case actionBundleGitRepo:
+		return "Bundling Git repository"
+	}
+	return ""
+}
+
+// goCommandError returns an error for a go command that failed, which is ErrGoToolchainMissing if go couldn't be run
+func goCommandError(err error, format string, a ...any) error {
+	var kind error
+	if errors.Is(err, exec.ErrNotFound) {
+		kind = ErrGoToolchainMissing
+	}
+	return wrap(kind, err, format, a...)
+}
+
+// createdPaths returns the paths a step may create. Git steps after initialization only change what's inside .git.
+func (p *plan) createdPaths(s step) []string {
+	switch s.action {
+	case actionCreateDir, actionCreateFile, actionWriteNotice:
+		return []string{s.path}
+	case actionInitGoModule:
+		return []string{filepath.Join(s.path, goModFileName)}
+	case actionAddDependency:
+		return []string{filepath.Join(s.path, "go.sum")}
+	case actionInitGitRepo:
+		return []string{filepath.Join(p.dir, ".git")}
+	case actionBundleGitRepo:
+		return []string{s.path}
+	}
+	return nil
+}
+
+// rollback removes created paths, newest first. Paths inside a removed directory go with it.
+func rollback(ctx context.Context, created []string, output io.Writer, quiet bool) error {
+	for i := len(created) - 1; i >= 0; i-- {
+		path := created[i]
+		if insideAny(path, created[:i]) {
+			continue
+		}
+		info, err := os.Lstat(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		err = os.RemoveAll(path)
+		if err != nil {
+			return err
+		}
+		fileType := "file"
+		if info.IsDir() {
+			fileType = "directory"
+		}
+		reportAtPath(output, quiet, colorYellow, "Removed", fileType, path)
+		logf(ctx, "Removed %s: %s", fileType, path)
+	}
+	return nil
+}
+
+// deleteRemote deletes the GitHub repository named by repo (e.g. "owner/name"), which was created. It's deleted even
+// once ctx has ended (e.g. the run was interrupted), keeping only ctx's values, such as its trace.
+func deleteRemote(ctx context.Context, repo string, output io.Writer, quiet bool) error {
+	ctx, cancel := context.WithTimeout(detachedContext{ctx}, 30*time.Second)
+	defer cancel()
+	token, err := githubToken(ctx)
+	if err != nil {
+		return err
+	}
+	owner, name, _ := strings.Cut(repo, "/")
+	err = deleteGitHubRepo(ctx, token, &githubRepo{Owner: owner, Name: name})
+	if err != nil {
+		return err
+	}
+	reportAtPath(output, quiet, colorYellow, "Deleted", "remote Git repository", "https://github.com/"+repo)
+	logf(ctx, "Deleted remote Git repository: https://github.com/%s", repo)
+	return nil
+}
+
+// A detachedContext has the values of the context it wraps, but is never done
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+func insideAny(path string, dirs []string) bool {
+	for _, dir := range dirs {
+		if strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Stages of a plan, which --skip and --only select from
+var stageNames = []string{"files", "deps", "git"}
+
+// Stages returns the stages of creation, which Options.Skip and Options.Only select from
+func Stages() []string {
+	return append([]string{}, stageNames...)
+}
+
+// stage returns the stage an action belongs to, or "" for notes, which belong to every stage
+func stage(action stepAction) string {
+	switch action {
+	case actionCreateDir, actionCreateFile, actionInitGoModule:
+		return "files"
+	case actionAddDependency, actionCheckVulns, actionWriteNotice:
+		return "deps"
+	case actionCheckGitConfig, actionInitGitRepo, actionSetGitHooks, actionCommitGitRepo, actionAddGitRemote, actionCreateRemote, actionPushGitRepo, actionBundleGitRepo, actionRemoveDir:
+		return "git"
+	}
+	return ""
+}
+
+// selectStages drops the steps of stages that are skipped, or (if only is set) not included
+func (p *plan) selectStages(skip []string, only []string) {
+	selected := map[string]bool{}
+	for _, name := range stageNames {
+		selected[name] = len(only) == 0
+	}
+	for _, name := range only {
+		selected[name] = true
+	}
+	for _, name := range skip {
+		selected[name] = false
+	}
+
+	steps := []step{}
+	for _, s := range p.steps {
+		if name := stage(s.action); name == "" || selected[name] {
+			steps = append(steps, s)
+		}
+	}
+	p.steps = steps
+
+	// Without Git, there's no repository to suggest pushing
+	if !selected["git"] {
+		p.repo = nil
+	}
+}
+
+// checkVulns runs govulncheck on the module in dir, returning the IDs of the known vulnerabilities its code calls into
+func (p *plan) checkVulns(ctx context.Context, dir string) ([]string, error) {
+	cmdOutput, err := p.goCommand(ctx, dir, "run", govulncheckPackage, "-format", "json", "./...")
+	if err != nil {
+		return nil, err
+	}
+	// A stream of messages, with a finding for each vulnerability in the module's dependencies. Those whose trace
+	// reaches a function are called by the module's code.
+	vulns := []string{}
+	seen := map[string]bool{}
+	decoder := json.NewDecoder(bytes.NewReader(cmdOutput))
+	for decoder.More() {
+		var message struct {
+			Finding *struct {
+				OSV   string `json:"osv"`
+				Trace []struct {
+					Function string `json:"function"`
+				} `json:"trace"`
+			} `json:"finding"`
+		}
+		err := decoder.Decode(&message)
+		if err != nil {
+			return nil, err
+		}
+		finding := message.Finding
+		if finding == nil || len(finding.Trace) == 0 || finding.Trace[0].Function == "" || seen[finding.OSV] {
+			continue
+		}
+		seen[finding.OSV] = true
+		vulns = append(vulns, finding.OSV)
+	}
+	return vulns, nil
+}
+
+// pushFailedNote returns the note that reports a failed push
+func (p *plan) pushFailedNote() string {
+	return fmt.Sprintf("Failed to push to remote Git repository: %s", p.pushErr)
+}
+
+// remoteVisibility returns the visibility of the remote repository created on GitHub
+func (p *plan) remoteVisibility() string {
+	if p.publicRemote {
+		return "public"
+	}
+	return "private"
+}
+
+// remoteRepoExists reports whether the GitHub repository named by repo (e.g. "owner/name") exists, as far as the
+// GitHub token (if any) can see
+func remoteRepoExists(ctx context.Context, repo string) bool {
+	token, err := githubToken(ctx)
+	if err != nil {
+		return false
+	}
+	owner, name, _ := strings.Cut(repo, "/")
+	return githubRepoExists(ctx, token, &githubRepo{Owner: owner, Name: name})
+}
+
+func isGitAction(action stepAction) bool {
+	switch action {
+	case actionCheckGitConfig, actionInitGitRepo, actionSetGitHooks, actionCommitGitRepo, actionAddGitRemote, actionCreateRemote, actionPushGitRepo, actionBundleGitRepo:
+		return true
+	}
+	return false
+}
+
+func checkGitConfig(client gitClient) error {
+	for _, key := range []string{"user.email", "user.name"} {
+		value, err := client.globalConfig(key)
+		if err != nil {
+			return wrap(nil, err, "Failed to look up Git %s", key)
+		}
+		if value == "" {
+			return wrap(ErrGitNotConfigured, nil, "`git config --global %s` must be set", key)
+		}
+	}
+	return nil
+}
+
+func (p *plan) nextSteps(gitBranch string) []string {
+	nextSteps := []string{}
+
+	if p.bundleOnly {
+		nextSteps = append(nextSteps, fmt.Sprintf("Clone module from bundle: $ git clone %s %s", p.bundle, p.dir))
+	}
+	if p.dir != "." {
+		nextSteps = append(nextSteps, fmt.Sprintf("Change into module's directory: $ cd %s", p.dir))
+	}
+	if !p.existing {
+		if p.splitCmd {
+			nextSteps = append(nextSteps, fmt.Sprintf("Run module's command: $ cd %s && go run .", filepath.ToSlash(p.cmdDir())))
+		} else {
+			nextSteps = append(nextSteps, "Run module: $ go run .")
+		}
+	}
+
+	if p.repo != nil {
+		// Once pushed, the remote exists, and has the initial commit
+		pushed := p.push && p.pushErr == nil
+
+		// Add next step: Create remote repository, unless it was
+		if !p.createRemote && !pushed {
+			nextStepCreateRemote := "Create remote Git repository"
+			if len(p.gitUrl) > 0 {
+				nextStepCreateRemote += fmt.Sprintf(" %s", p.gitUrl)
+				if _, newRepoURL := gitRemoteForModule(p.module, p.remoteProtocol); newRepoURL != "" {
+					nextStepCreateRemote += ": " + newRepoURL
+				}
+			}
+			nextSteps = append(nextSteps, nextStepCreateRemote)
+		}
+
+		// A clone of the bundle has the bundle as its origin
+		if p.bundleOnly && len(p.gitUrl) > 0 {
+			nextSteps = append(nextSteps, fmt.Sprintf("Set remote Git repository: $ git remote set-url origin %s", p.gitUrl))
+		}
+
+		// Add next step: Push to remote, unless it was
+		if !pushed {
+			nextStepPush := "Push to remote Git repository: $ git push -u origin "
+			if gitBranch != "" {
+				nextStepPush += gitBranch
+			} else {
+				nextStepPush += "$(git branch --show-current)"
+			}
+			nextSteps = append(nextSteps, nextStepPush)
+		}
+
+		if p.bundle != "" && !p.bundleOnly {
+			nextSteps = append(nextSteps, fmt.Sprintf("Clone module from bundle (e.g. on another machine): $ git clone %s", p.bundle))
+		}
+	}
+
+	// Add next step: Build container image
+	if p.docker {
+		nextSteps = append(nextSteps, fmt.Sprintf("Build container image: $ docker build -t %s .", p.moduleBase))
+	}
+
+	// Add next step: Start coding!
+	editor := p.editor
+	if editor == "" {
+		editor = "$EDITOR"
+		editorEnvVar := os.Getenv("EDITOR")
+		if editorEnvVar != "" {
+			editor = editorEnvVar
+		} else if p.wsl {
+			editor = wslEditor
+		}
+	}
+	nextSteps = append(nextSteps, fmt.Sprintf("Start coding: $ %s .", editor))
+
+	return nextSteps
+}
+
+func reportNextSteps(output io.Writer, quiet bool, nextSteps []string) {
+	if len(nextSteps) > 0 {
+		flogf(output, quiet, "\n%s\n", colored(output, colorBold, "Next steps:"))
+		for _, nextStep := range nextSteps {
+			flogf(output, quiet, "- %s\n", nextStep)
+		}
+	}
+}
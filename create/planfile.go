@@ -0,0 +1,156 @@
+package create
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+)
+
+// Returned (wrapped) by Apply when a plan can't be read
+var ErrInvalidPlan = errors.New("Invalid plan")
+
+// Apply carries out a plan returned by Plan, as `gmc apply` does
+func Apply(ctx context.Context, planJSON []byte, opts RunOptions) (*Result, error) {
+	var f planFile
+	err := json.Unmarshal(planJSON, &f)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPlan, err)
+	}
+	p, err := f.plan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return runPlan(ctx, p, opts)
+}
+
+// A planFile is a plan saved as JSON by Plan (or `gmc plan`), to be reviewed, and then carried out by Apply
+type planFile struct {
+	GmcVersion  string         `json:"gmcVersion"`
+	Task        string         `json:"task"`
+	Module      string         `json:"module"`
+	Dir         string         `json:"dir"`
+	GoVersion   string         `json:"goVersion"`
+	Existing    bool           `json:"existing,omitempty"`
+	Git         *planFileGit   `json:"git,omitempty"`
+	Docker      bool           `json:"docker,omitempty"`
+	Editor      string         `json:"editor,omitempty"`
+	Container   string         `json:"container,omitempty"`   // Image that Go commands run in
+	GoToolchain string         `json:"goToolchain,omitempty"` // go executable that Go commands run with
+	GoProxy     string         `json:"goProxy,omitempty"`     // GOPROXY that Go commands run with
+	FailOnVuln  bool           `json:"failOnVuln,omitempty"`  // Whether known vulnerabilities in dependencies fail
+	Steps       []planFileStep `json:"steps"`
+}
+
+type planFileGit struct {
+	Exec          bool   `json:"exec,omitempty"`
+	InitialBranch string `json:"initialBranch,omitempty"`
+	URL           string `json:"url,omitempty"`
+	// Whether the remote repository that's created (with a createRemoteRepo step) is public, instead of private
+	PublicRemote bool `json:"publicRemote,omitempty"`
+}
+
+type planFileStep struct {
+	Action     stepAction `json:"action"`
+	Path       string     `json:"path,omitempty"`
+	Content    *string    `json:"content,omitempty"` // Text, so that it can be reviewed
+	Executable bool       `json:"executable,omitempty"`
+	Arg        string     `json:"arg,omitempty"`
+}
+
+func newPlanFile(p *plan) *planFile {
+	f := &planFile{
+		GmcVersion:  Version,
+		Task:        p.task,
+		Module:      p.module,
+		Dir:         p.dir,
+		GoVersion:   p.goVersion,
+		Existing:    p.existing,
+		Docker:      p.docker,
+		Editor:      p.editor,
+		Container:   p.container,
+		GoToolchain: p.goToolchain,
+		GoProxy:     p.goProxy,
+		FailOnVuln:  p.failOnVuln,
+		Steps:       []planFileStep{},
+	}
+	if p.repo != nil {
+		f.Git = &planFileGit{URL: p.gitUrl, PublicRemote: p.publicRemote}
+		_, f.Git.Exec = p.repo.client.(gitExecutable)
+		if p.repo.initialBranch != nil {
+			f.Git.InitialBranch = *p.repo.initialBranch
+		}
+	}
+	for _, s := range p.steps {
+		fileStep := planFileStep{Action: s.action, Path: s.path, Executable: s.executable, Arg: s.arg}
+		if s.content != nil {
+			content := string(s.content)
+			fileStep.Content = &content
+		}
+		f.Steps = append(f.Steps, fileStep)
+	}
+	return f
+}
+
+// plan turns a plan file back into a plan, whose Git actions run in ctx
+func (f *planFile) plan(ctx context.Context) (*plan, error) {
+	p := &plan{
+		task:        f.Task,
+		existing:    f.Existing,
+		module:      f.Module,
+		moduleBase:  filepath.Base(f.Module),
+		dir:         f.Dir,
+		docker:      f.Docker,
+		editor:      f.Editor,
+		goVersion:   f.GoVersion,
+		container:   f.Container,
+		goToolchain: f.GoToolchain,
+		goProxy:     f.GoProxy,
+		failOnVuln:  f.FailOnVuln,
+		wsl:         runningInWSL(),
+	}
+	if f.Module == "" || f.Dir == "" {
+		return nil, fmt.Errorf("%w: module and dir are required", ErrInvalidPlan)
+	}
+	if f.Git != nil {
+		p.repo = &gitRepo{client: newGitClient(ctx, f.Git.Exec)}
+		if f.Git.InitialBranch != "" {
+			p.repo.initialBranch = &f.Git.InitialBranch
+		}
+		p.gitUrl = f.Git.URL
+		p.publicRemote = f.Git.PublicRemote
+	}
+	for _, fileStep := range f.Steps {
+		s := step{action: fileStep.Action, path: fileStep.Path, executable: fileStep.Executable, arg: fileStep.Arg}
+		if fileStep.Content != nil {
+			s.content = []byte(*fileStep.Content)
+		}
+		switch s.action {
+		case actionCreateDir, actionCreateFile, actionInitGoModule, actionAddDependency, actionCheckVulns, actionWriteNotice, actionNote:
+		case actionCheckGitConfig, actionInitGitRepo, actionSetGitHooks, actionCommitGitRepo, actionAddGitRemote, actionCreateRemote, actionPushGitRepo, actionBundleGitRepo:
+			if p.repo == nil {
+				return nil, fmt.Errorf("%w: %s step without git", ErrInvalidPlan, s.action)
+			}
+			if s.action == actionCreateRemote {
+				p.createRemote = true
+			}
+			if s.action == actionPushGitRepo {
+				p.push = true
+			}
+			if s.action == actionBundleGitRepo {
+				p.bundle = s.path
+			}
+		case actionRemoveDir:
+			// Only the module's own directory is removed, once bundled
+			if s.path != p.dir || p.bundle == "" {
+				return nil, fmt.Errorf("%w: %s step for %s", ErrInvalidPlan, s.action, s.path)
+			}
+			p.bundleOnly = true
+		default:
+			return nil, fmt.Errorf("%w: unknown action: %s", ErrInvalidPlan, s.action)
+		}
+		p.steps = append(p.steps, s)
+	}
+	return p, nil
+}
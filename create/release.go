@@ -0,0 +1,190 @@
+package create
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+const changelogFileName string = "CHANGELOG.md"
+
+// ReleasePrepOptions determine what ReleasePrep releases, and how
+type ReleasePrepOptions struct {
+	// Version to release, as it's tagged (e.g. v1.2.0)
+	Version string
+
+	// Check that the module is ready to release, without creating the tag
+	DryRun bool
+
+	// Run the git executable for Git actions, instead of the built-in implementation
+	GitExec bool
+}
+
+// A ReleaseCheck is one thing that must hold before a version is released
+type ReleaseCheck struct {
+	Name string `json:"name"`
+
+	// Why the check failed, or "" if it passed
+	Error string `json:"error,omitempty"`
+}
+
+// A ReleasePrepResult reports what ReleasePrep checked, and the tag it created, if any
+type ReleasePrepResult struct {
+	Module string         `json:"module"`
+	Checks []ReleaseCheck `json:"checks"`
+	Tag    string         `json:"tag,omitempty"`
+}
+
+// ReleasePrep checks that the Go module in the current directory is ready to release as opts.Version, as `gmc
+// release-prep` does: the version follows the module's major version and its existing tags, CHANGELOG.md has an entry
+// for it, go.mod is tidy, and the tests pass. If every check passes, the commit checked out is tagged with the version.
+// If any fails, nothing is tagged, and the error wraps ErrNotReleasable.
+func ReleasePrep(ctx context.Context, opts ReleasePrepOptions) (*ReleasePrepResult, error) {
+	version := opts.Version
+	if !strings.HasPrefix(version, "v") || !semver.IsValid(version) || semver.Build(version) != "" {
+		return nil, UsageError{errors.New(fmt.Sprintf("Error: Invalid version: %s (e.g. v1.2.0)", version))}
+	}
+	m, err := readModule(".")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to prepare release: %w", err)
+	}
+	git := newGitClient(ctx, opts.GitExec)
+	if !git.hasCommits(".") {
+		return nil, wrap(ErrGit, nil, "Failed to prepare release: %s: Not a Git repository with commits (run from its root)", m.path)
+	}
+
+	r := &ReleasePrepResult{Module: m.path}
+	checks := []struct {
+		name string
+		run  func() error
+	}{
+		{"Version", func() error { return checkReleaseVersion(git, m.path, version) }},
+		{"Changelog", func() error { return checkChangelog(version) }},
+		{"go.mod tidy", func() error { return checkTidy(ctx) }},
+		{"Tests", func() error { return checkTests(ctx) }},
+	}
+	failed := []string{}
+	for _, check := range checks {
+		err := check.run()
+		if ctx.Err() != nil {
+			return nil, wrap(ErrInterrupted, contextCause(ctx, err), "Failed to prepare release: %s: %s", m.path, ErrInterrupted)
+		}
+		c := ReleaseCheck{Name: check.name}
+		if err != nil {
+			c.Error = err.Error()
+			failed = append(failed, check.name)
+		}
+		r.Checks = append(r.Checks, c)
+	}
+	if len(failed) > 0 {
+		return r, wrap(ErrNotReleasable, nil, "Failed to prepare release: %s: Not ready to release %s (failed: %s)", m.path, version, strings.Join(failed, ", "))
+	}
+
+	if !opts.DryRun {
+		err = git.tag(".", version, "Release "+version)
+		if err != nil {
+			return r, wrap(ErrGit, err, "Failed to prepare release: %s: Failed to tag %s: %s", m.path, version, err)
+		}
+		r.Tag = version
+	}
+	return r, nil
+}
+
+// checkReleaseVersion checks that version can be the next release of the module: it matches the module path's major
+// version (e.g. /v2 for v2.x.x), isn't tagged yet, and is later than every version that is
+func checkReleaseVersion(git gitClient, modulePath string, version string) error {
+	if _, pathMajor, ok := module.SplitPathVersion(modulePath); ok {
+		if err := module.CheckPathMajor(version, pathMajor); err != nil {
+			return errors.New(fmt.Sprintf("%s doesn't match the module path's major version: %s", version, modulePath))
+		}
+	}
+	tags, err := git.tags(".")
+	if err != nil {
+		return err
+	}
+	latest := ""
+	for _, tag := range tags {
+		if tag == version {
+			return errors.New(fmt.Sprintf("%s is already tagged", version))
+		}
+		if semver.IsValid(tag) && semver.Compare(tag, latest) > 0 {
+			latest = tag
+		}
+	}
+	if latest != "" && semver.Compare(version, latest) < 0 {
+		return errors.New(fmt.Sprintf("%s is earlier than the latest tag, %s", version, latest))
+	}
+	return nil
+}
+
+// checkChangelog checks that CHANGELOG.md has a heading for version (e.g. "## [1.2.0] - 2024-05-01", or "## v1.2.0")
+func checkChangelog(version string) error {
+	content, err := os.ReadFile(changelogFileName)
+	if errors.Is(err, os.ErrNotExist) {
+		return errors.New(fmt.Sprintf("No %s", changelogFileName))
+	} else if err != nil {
+		return err
+	}
+	heading := regexp.MustCompile(`(?m)^#+.*[\s\[]v?` + regexp.QuoteMeta(strings.TrimPrefix(version, "v")) + `([\s\]]|$)`)
+	if !heading.Match(content) {
+		return errors.New(fmt.Sprintf("No entry for %s in %s", version, changelogFileName))
+	}
+	return nil
+}
+
+// checkTidy checks that `go mod tidy` changes nothing, leaving go.mod and go.sum as they were either way
+func checkTidy(ctx context.Context) error {
+	files := []string{goModFileName, "go.sum"}
+	before := map[string][]byte{}
+	for _, name := range files {
+		content, err := os.ReadFile(name)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		before[name] = content
+	}
+	cmdOutput, tidyErr := runCommand(ctx, "", "go", "mod", "tidy")
+
+	untidy := []string{}
+	for _, name := range files {
+		content, err := os.ReadFile(name)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		if bytes.Equal(content, before[name]) {
+			continue
+		}
+		untidy = append(untidy, name)
+		if before[name] == nil {
+			err = os.Remove(name)
+		} else {
+			err = os.WriteFile(name, before[name], 0644)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if tidyErr != nil {
+		return errors.New(fmt.Sprintf("`go mod tidy` failed: %s", strings.TrimSpace(string(cmdOutput))))
+	}
+	if len(untidy) > 0 {
+		return errors.New(fmt.Sprintf("Not tidy: %s (run `go mod tidy`)", strings.Join(untidy, ", ")))
+	}
+	return nil
+}
+
+// checkTests checks that `go test ./...` passes
+func checkTests(ctx context.Context) error {
+	_, err := runCommand(ctx, "", "go", "test", "./...")
+	if err != nil {
+		return errors.New("`go test ./...` failed")
+	}
+	return nil
+}
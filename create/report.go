@@ -0,0 +1,75 @@
+package create
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// A Result is a machine-readable record of what creating a module did (or would do, for a dry run)
+type Result struct {
+	Module             string   `json:"module"`
+	GoVersion          string   `json:"goVersion"`
+	DryRun             bool     `json:"dryRun"`
+	CreatedDirectories []string `json:"createdDirectories"`
+	CreatedFiles       []string `json:"createdFiles"`
+	RemovedDirectories []string `json:"removedDirectories,omitempty"`
+	Dependencies       []string `json:"dependencies"`
+	GitActions         []string `json:"gitActions"`
+	GitRemote          string   `json:"gitRemote,omitempty"`
+	Bundle             string   `json:"bundle,omitempty"`
+	Archive            string   `json:"archive,omitempty"`
+	Notes              []string `json:"notes"`
+	NextSteps          []string `json:"nextSteps"`
+}
+
+func newReport(module string, goVersion string, dryRun bool) *Result {
+	return &Result{
+		Module:             module,
+		GoVersion:          goVersion,
+		DryRun:             dryRun,
+		CreatedDirectories: []string{},
+		CreatedFiles:       []string{},
+		Dependencies:       []string{},
+		GitActions:         []string{},
+		Notes:              []string{},
+		NextSteps:          []string{},
+	}
+}
+
+// record adds the effect of a step to the report
+func (r *Result) record(s step) {
+	switch s.action {
+	case actionCreateDir:
+		r.CreatedDirectories = append(r.CreatedDirectories, s.path)
+	case actionCreateFile, actionWriteNotice:
+		r.CreatedFiles = append(r.CreatedFiles, s.path)
+	case actionAddDependency:
+		r.Dependencies = append(r.Dependencies, s.arg)
+	case actionInitGitRepo:
+		r.GitActions = append(r.GitActions, "init")
+	case actionSetGitHooks:
+		r.GitActions = append(r.GitActions, "setHooks")
+	case actionCommitGitRepo:
+		r.GitActions = append(r.GitActions, "commit")
+	case actionAddGitRemote:
+		r.GitActions = append(r.GitActions, "addRemote")
+		r.GitRemote = s.arg
+	case actionCreateRemote:
+		r.GitActions = append(r.GitActions, "createRemote")
+	case actionPushGitRepo:
+		r.GitActions = append(r.GitActions, "push")
+	case actionBundleGitRepo:
+		r.GitActions = append(r.GitActions, "bundle")
+		r.Bundle = s.path
+	case actionRemoveDir:
+		r.RemovedDirectories = append(r.RemovedDirectories, s.path)
+	case actionNote:
+		r.Notes = append(r.Notes, s.arg)
+	}
+}
+
+func (r *Result) write(output io.Writer) error {
+	encoder := json.NewEncoder(output)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
@@ -0,0 +1,63 @@
+package create
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Frames of the spinner, drawn in turn
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+const spinnerInterval = 100 * time.Millisecond
+
+// A spinner is drawn on a terminal, along with what a slow step is doing, until the step reports what it did
+type spinner struct {
+	output  io.Writer
+	done    chan struct{}
+	stopped chan struct{}
+	once    sync.Once
+}
+
+// spin draws a spinner and message on output, a terminal, until stopped
+func spin(output io.Writer, message string) *spinner {
+	s := &spinner{output: output, done: make(chan struct{}), stopped: make(chan struct{})}
+	go func() {
+		defer close(s.stopped)
+		ticker := time.NewTicker(spinnerInterval)
+		defer ticker.Stop()
+		for i := 0; ; i++ {
+			fmt.Fprintf(output, "\r%s %s", spinnerFrames[i%len(spinnerFrames)], message)
+			select {
+			case <-s.done:
+				fmt.Fprint(output, "\r\x1b[2K") // Erased, so that what's written next starts the line
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return s
+}
+
+// stop erases the spinner. It can be called more than once.
+func (s *spinner) stop() {
+	s.once.Do(func() {
+		close(s.done)
+		<-s.stopped
+	})
+}
+
+// Write stops the spinner, and then writes p to its output
+func (s *spinner) Write(p []byte) (int, error) {
+	s.stop()
+	return s.output.Write(p)
+}
+
+// writer returns a writer that stops the spinner once anything is written, and writes in color if its output does
+func (s *spinner) writer() io.Writer {
+	if _, ok := s.output.(ColorWriter); ok {
+		return ColorWriter{Writer: s}
+	}
+	return s
+}
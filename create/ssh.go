@@ -0,0 +1,25 @@
+package create
+
+import (
+	"context"
+	"os/exec"
+)
+
+// Hint for loading an SSH identity into the agent, with the ~/.ssh/config that keeps it loaded across restarts
+const macOSSSHAgentHint string = "No SSH identity is loaded, so pushing over SSH will fail: run `ssh-add --apple-use-keychain ~/.ssh/id_ed25519`, " +
+	"and add `AddKeysToAgent yes` and `UseKeychain yes` under `Host *` in ~/.ssh/config"
+
+// sshAgentHint returns a hint if pushing to an SSH remote from goos (e.g. runtime.GOOS) looks likely to fail, or "" if
+// it doesn't (or can't be told). Only macOS is checked, where identities commonly live in the Keychain rather than the
+// agent.
+func sshAgentHint(ctx context.Context, goos string) string {
+	if goos != "darwin" {
+		return ""
+	}
+	// Exits 1 when the agent has no identities, and 2 when there is no agent to ask
+	_, err := runCommand(ctx, "", "ssh-add", "-l")
+	if exitError, ok := err.(*exec.ExitError); ok && (exitError.ExitCode() == 1 || exitError.ExitCode() == 2) {
+		return macOSSSHAgentHint
+	}
+	return ""
+}
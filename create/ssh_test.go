@@ -0,0 +1,42 @@
+package create
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestSSHAgentHint(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("ssh-add is stubbed with a shell script")
+	}
+
+	// ssh-add -l exits with each code
+	tests := []struct {
+		goos         string
+		exitCode     int
+		expectedHint string
+	}{
+		{"darwin", 0, ""},
+		{"darwin", 1, macOSSSHAgentHint},
+		{"darwin", 2, macOSSSHAgentHint},
+		{"linux", 1, ""},
+	}
+	for _, tc := range tests {
+		binDir := t.TempDir()
+		script := fmt.Sprintf("#!/bin/sh\nexit %d\n", tc.exitCode)
+		err := os.WriteFile(filepath.Join(binDir, "ssh-add"), []byte(script), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv("PATH", binDir) // Automatically reset
+
+		hint := sshAgentHint(context.Background(), tc.goos)
+		if hint != tc.expectedHint {
+			t.Errorf("Unexpected hint for %s, ssh-add exiting %d\nExpected: %q\nActual  : %q\n", tc.goos, tc.exitCode, tc.expectedHint, hint)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package create
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Platforms (GOOS/GOARCH) that binaries can be built for with cgo disabled, as listed by go tool dist list. Android
+// and iOS aren't, since their executables need cgo, and so a C toolchain for the target.
+var targetPlatforms = []string{
+	"aix/ppc64",
+	"darwin/amd64", "darwin/arm64",
+	"dragonfly/amd64",
+	"freebsd/386", "freebsd/amd64", "freebsd/arm", "freebsd/arm64",
+	"illumos/amd64",
+	"js/wasm",
+	"linux/386", "linux/amd64", "linux/arm", "linux/arm64", "linux/loong64", "linux/mips", "linux/mips64",
+	"linux/mips64le", "linux/mipsle", "linux/ppc64", "linux/ppc64le", "linux/riscv64", "linux/s390x",
+	"netbsd/386", "netbsd/amd64", "netbsd/arm", "netbsd/arm64",
+	"openbsd/386", "openbsd/amd64", "openbsd/arm", "openbsd/arm64", "openbsd/ppc64", "openbsd/riscv64",
+	"plan9/386", "plan9/amd64", "plan9/arm",
+	"solaris/amd64",
+	"wasip1/wasm",
+	"windows/386", "windows/amd64", "windows/arm64",
+}
+
+// A buildTarget is a platform that the module's binary is built for (e.g. linux/amd64)
+type buildTarget struct {
+	OS   string
+	Arch string
+}
+
+// Ext returns the extension of the binary built for the target (e.g. ".exe" for Windows), if any
+func (t buildTarget) Ext() string {
+	switch {
+	case t.OS == "windows":
+		return ".exe"
+	case t.Arch == "wasm":
+		return ".wasm"
+	}
+	return ""
+}
+
+// parseTargets checks platforms to build for (e.g. "linux/amd64"), returning each once, in the order given
+func parseTargets(targets []string) ([]buildTarget, error) {
+	var parsed []buildTarget
+	seen := map[string]bool{}
+	for _, target := range targets {
+		supported := false
+		for _, platform := range targetPlatforms {
+			if platform == target {
+				supported = true
+			}
+		}
+		if !supported {
+			return nil, errors.New(fmt.Sprintf("Error: Unsupported target: %s (e.g. linux/amd64, darwin/arm64, or windows/amd64)", target))
+		}
+		if !seen[target] {
+			seen[target] = true
+			goos, goarch, _ := strings.Cut(target, "/")
+			parsed = append(parsed, buildTarget{OS: goos, Arch: goarch})
+		}
+	}
+	return parsed, nil
+}
@@ -0,0 +1,71 @@
+package create
+
+import "io/fs"
+
+// Source of the asset sets built into gmc
+const templateSourceEmbedded string = "embedded"
+
+// What each embedded asset set adds, and the option that adds it, keyed by name
+var templateDescriptions = map[string]string{
+	"default":                   "main.go that prints hello, world, and main_test.go that builds and runs it (every module, unless --split-cmd)",
+	"split-cmd":                 "library package with a test, in place of main.go (--split-cmd)",
+	"split-cmd-command":         "main.go of the command module in cmd/<name>, which uses the library (--split-cmd)",
+	"prop-tests":                "example property-based test of the library with rapid (--prop-tests)",
+	"examples":                  "example program in a separate module in examples/, which uses the library (--examples)",
+	"ci-github":                 "GitHub Actions CI workflow (--ci github)",
+	"ci-gitlab":                 "GitLab CI pipeline (--ci gitlab)",
+	"lint":                      "golangci-lint configuration (--lint)",
+	"fmt-check":                 ".editorconfig that CI checks files against (--fmt-check)",
+	"goreleaser":                "GoReleaser configuration and release workflow (--goreleaser)",
+	"docker":                    "Dockerfile and .dockerignore (--docker)",
+	"make":                      "Makefile (--make)",
+	"taskfile":                  "Taskfile.yml (--taskfile)",
+	"scripts":                   "script/bootstrap, build, test, and server (--scripts)",
+	"powershell":                "PowerShell equivalents of the scripts (--powershell)",
+	"bootstrap-script":          "script/bootstrap that installs Go and tools on a fresh Linux machine (--bootstrap-script)",
+	"embed-assets":              "assets directory embedded with go:embed (--embed-assets)",
+	"i18n":                      "translated messages with golang.org/x/text (--i18n)",
+	"feature-flags-openfeature": "feature flags with OpenFeature (--feature-flags openfeature)",
+	"notice":                    "go-licenses template that NOTICE is generated from (--notice)",
+	"layout-apiv1":              "versioned public API package in api/v1, with a guide to adding v2 beside it (--layout apiv1)",
+	"golden":                    "golden file test helper and example test (--golden)",
+	"mutation":                  "Gremlins mutation testing configuration (--mutation)",
+	"secret-scan":               "gitleaks configuration and pre-commit hook (--secret-scan)",
+	"scorecard":                 "OpenSSF Scorecard workflow and Best Practices checklist (--scorecard)",
+	"pgo":                       "default.pgo profile for profile-guided optimization (--pgo)",
+	"envrc":                     "direnv .envrc with Go settings (--toolchain or --private-proxy)",
+	"private-proxy":             "private module proxy setup guide (--private-proxy)",
+	"vscode":                    "Visual Studio Code settings, launch configuration, and tasks (--vscode)",
+	"goland":                    "GoLand run configurations (--goland)",
+	"nvim":                      "project-local Neovim configuration (--nvim)",
+	"cloud-dev-gitpod":          "Gitpod configuration (--cloud-dev gitpod)",
+	"cloud-dev-codespaces":      "Codespaces dev container (--cloud-dev codespaces)",
+}
+
+// A Template is a set of files that modules can be created from, as recorded in their manifests
+type Template struct {
+	Name string `json:"name"`
+
+	// Where the template comes from (e.g. "embedded", for those built into gmc)
+	Source string `json:"source"`
+
+	// What the template adds, and the option that adds it
+	Description string `json:"description"`
+}
+
+// Templates returns the templates that modules can be created from, by name
+func Templates() []Template {
+	templates := []Template{}
+	entries, _ := fs.ReadDir(assets, assetsDir)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		templates = append(templates, Template{
+			Name:        entry.Name(),
+			Source:      templateSourceEmbedded,
+			Description: templateDescriptions[entry.Name()],
+		})
+	}
+	return templates
+}
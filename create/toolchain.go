@@ -0,0 +1,320 @@
+package create
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Where official Go toolchains are downloaded from by ProvisionGo, unless another URL (e.g. a mirror) is given
+const GoDownloadURL string = "https://go.dev/dl/"
+
+// A goRelease is a Go release, as listed by the download site
+type goRelease struct {
+	Version string          `json:"version"`
+	Stable  bool            `json:"stable"`
+	Files   []goReleaseFile `json:"files"`
+}
+
+type goReleaseFile struct {
+	Filename string `json:"filename"`
+	OS       string `json:"os"`
+	Arch     string `json:"arch"`
+	Kind     string `json:"kind"`
+	SHA256   string `json:"sha256"`
+}
+
+// GoInstalled reports whether a go executable is found on PATH
+func GoInstalled() bool {
+	_, err := exec.LookPath("go")
+	return err == nil
+}
+
+// ProvisionGo returns the go executable of the latest official release of goVersion (e.g. "1.21" or "1.21.3", or the
+// latest release if ""), for this platform. Unless it's already in gmc's cache, it's downloaded from downloadURL (or
+// GoDownloadURL, if ""), verified against its published checksum, and unpacked there.
+func ProvisionGo(ctx context.Context, goVersion string, downloadURL string) (string, error) {
+	if downloadURL == "" {
+		downloadURL = GoDownloadURL
+	}
+	downloadURL = strings.TrimSuffix(downloadURL, "/") + "/"
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", wrap(ErrGoToolchainMissing, err, "Failed to download Go: no cache directory: %s", err)
+	}
+	toolchainsDir := filepath.Join(cacheDir, Name, "toolchains")
+
+	release, file, err := findGoRelease(ctx, downloadURL, goVersion)
+	if err != nil {
+		return "", wrap(ErrGoToolchainMissing, err, "Failed to download Go: %s", err)
+	}
+	dir := filepath.Join(toolchainsDir, release.Version)
+	goExecutable := filepath.Join(dir, "go", "bin", "go")
+	if runtime.GOOS == "windows" {
+		goExecutable += ".exe"
+	}
+	if _, err := os.Stat(goExecutable); err == nil {
+		return goExecutable, nil
+	}
+
+	err = os.MkdirAll(toolchainsDir, 0755)
+	if err == nil {
+		err = downloadGo(ctx, downloadURL, file, toolchainsDir, dir)
+	}
+	if err != nil {
+		return "", wrap(ErrGoToolchainMissing, err, "Failed to download Go: %s: %s", file.Filename, err)
+	}
+	return goExecutable, nil
+}
+
+// parseToolchain checks a toolchain to pin (e.g. "go1.22.3", or "1.22.3"), which must be Go 1.21 or later (the first
+// release that knows toolchain directives), and no older than goVersion, if set. It returns the toolchain's name.
+func parseToolchain(toolchain string, goVersion string) (string, error) {
+	if toolchain == "" {
+		return "", nil
+	}
+	if !strings.HasPrefix(toolchain, "go") {
+		toolchain = "go" + toolchain
+	}
+	match := toolchainRegexp.FindStringSubmatch(toolchain)
+	if match == nil {
+		return "", errors.New(fmt.Sprintf("Error: Invalid toolchain: %s (e.g. go1.22.3)", toolchain))
+	}
+	if compareGoVersions(match[1], "1.21") < 0 {
+		return "", errors.New(fmt.Sprintf("Error: Toolchain %s can't be pinned: toolchain directives need Go 1.21 or later", toolchain))
+	}
+	if goVersion != "" && compareGoVersions(strings.TrimPrefix(toolchain, "go"), goVersion) < 0 {
+		return "", errors.New(fmt.Sprintf("Error: Toolchain %s is older than Go version %s", toolchain, goVersion))
+	}
+	return toolchain, nil
+}
+
+// toolchainLanguageVersion returns the language version of a toolchain (e.g. "go1.22.3" -> "1.22")
+func toolchainLanguageVersion(toolchain string) string {
+	match := toolchainRegexp.FindStringSubmatch(toolchain)
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
+
+// compareGoVersions compares Go versions (e.g. "1.21", "1.21.3", or "1.22rc1"), returning -1, 0, or 1. As in go.mod,
+// a language version (e.g. "1.21") is its first release, which its release candidates come before.
+func compareGoVersions(a string, b string) int {
+	partsA, partsB := goVersionParts(a), goVersionParts(b)
+	for i := range partsA {
+		if partsA[i] < partsB[i] {
+			return -1
+		} else if partsA[i] > partsB[i] {
+			return 1
+		}
+	}
+	return 0
+}
+
+// goVersionParts returns the major, minor, and patch numbers of a Go version, and its release candidate number (or the
+// highest number, for a release)
+func goVersionParts(version string) [4]int {
+	parts := [4]int{0, 0, 0, math.MaxInt}
+	if i := strings.Index(version, "rc"); i >= 0 {
+		parts[3], _ = strconv.Atoi(version[i+len("rc"):])
+		version = version[:i]
+	}
+	for i, number := range strings.SplitN(version, ".", 3) {
+		parts[i], _ = strconv.Atoi(number)
+	}
+	return parts
+}
+
+// findGoRelease returns the latest stable release of goVersion (or any, if "") listed at downloadURL, and its archive
+// for this platform
+func findGoRelease(ctx context.Context, downloadURL string, goVersion string) (*goRelease, *goReleaseFile, error) {
+	resp, err := httpGet(ctx, downloadURL+"?mode=json&include=all")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	var releases []goRelease
+	err = json.NewDecoder(resp.Body).Decode(&releases)
+	if err != nil {
+		return nil, nil, errors.New(fmt.Sprintf("Invalid list of releases: %s", err))
+	}
+
+	// Releases are listed newest first
+	for i := range releases {
+		release := &releases[i]
+		version := "go" + goVersion
+		if !release.Stable || (goVersion != "" && release.Version != version && !strings.HasPrefix(release.Version, version+".")) {
+			continue
+		}
+		for j := range release.Files {
+			file := &release.Files[j]
+			if file.OS == runtime.GOOS && file.Arch == runtime.GOARCH && file.Kind == "archive" {
+				return release, file, nil
+			}
+		}
+		return nil, nil, errors.New(fmt.Sprintf("%s has no download for %s/%s", release.Version, runtime.GOOS, runtime.GOARCH))
+	}
+	if goVersion == "" {
+		return nil, nil, errors.New("No release found")
+	}
+	return nil, nil, errors.New(fmt.Sprintf("No release found for Go %s", goVersion))
+}
+
+// downloadGo downloads the archive file, checks its checksum, and unpacks it into dir, by way of a temporary directory
+// in toolchainsDir, so that an interrupted download leaves nothing behind to be mistaken for a toolchain
+func downloadGo(ctx context.Context, downloadURL string, file *goReleaseFile, toolchainsDir string, dir string) error {
+	resp, err := httpGet(ctx, downloadURL+file.Filename)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	archive, err := os.CreateTemp(toolchainsDir, ".download-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(archive.Name())
+	defer archive.Close()
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(archive, hash), resp.Body)
+	if err != nil {
+		return err
+	}
+	if checksum := hex.EncodeToString(hash.Sum(nil)); checksum != file.SHA256 {
+		return errors.New(fmt.Sprintf("checksum mismatch (expected %s, got %s)", file.SHA256, checksum))
+	}
+
+	tempDir, err := os.MkdirTemp(toolchainsDir, ".unpack-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tempDir)
+	if strings.HasSuffix(file.Filename, ".zip") {
+		err = unzip(archive, size, tempDir)
+	} else {
+		_, err = archive.Seek(0, io.SeekStart)
+		if err == nil {
+			err = untar(archive, tempDir)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tempDir, dir)
+}
+
+func httpGet(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, errors.New(fmt.Sprintf("%s: %s", url, resp.Status))
+	}
+	return resp, nil
+}
+
+// unpackPath returns where an archive entry named name is unpacked in dir, refusing names that would escape it
+func unpackPath(dir string, name string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(name))
+	if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", errors.New(fmt.Sprintf("invalid path in archive: %s", name))
+	}
+	return filepath.Join(dir, clean), nil
+}
+
+// untar unpacks a gzipped tarball read from r into dir
+func untar(r io.Reader, dir string) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		path, err := unpackPath(dir, header.Name)
+		if err != nil {
+			return err
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(path, 0755)
+		case tar.TypeReg:
+			err = unpackFile(path, header.FileInfo().Mode(), tr)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// unzip unpacks a zip file of size bytes read from r into dir
+func unzip(r io.ReaderAt, size int64, dir string) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return err
+	}
+	for _, f := range zr.File {
+		path, err := unpackPath(dir, f.Name)
+		if err != nil {
+			return err
+		}
+		if f.FileInfo().IsDir() {
+			err = os.MkdirAll(path, 0755)
+		} else {
+			var content io.ReadCloser
+			content, err = f.Open()
+			if err == nil {
+				err = unpackFile(path, f.Mode(), content)
+				content.Close()
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func unpackFile(path string, mode fs.FileMode, content io.Reader) error {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode.Perm())
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, content)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,117 @@
+package create
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type traceKey struct{}
+
+type logKey struct{}
+
+// Format of the timestamp that starts each line of a log
+const logTimeFormat string = "2006-01-02T15:04:05.000Z07:00"
+
+// WithTrace returns a context in which every command run (and built-in Git action taken) is traced to w, with its
+// directory and duration, along with the error output of commands that fail
+func WithTrace(ctx context.Context, w io.Writer) context.Context {
+	return context.WithValue(ctx, traceKey{}, w)
+}
+
+// WithLog returns a context in which every step carried out and every command run is logged to w, with timestamps, for
+// debugging. Unlike WithTrace, the error output of every command is logged, whether or not it fails.
+func WithLog(ctx context.Context, w io.Writer) context.Context {
+	return context.WithValue(ctx, logKey{}, w)
+}
+
+// logf logs a message (of one or more lines), if ctx has a log
+func logf(ctx context.Context, format string, a ...any) {
+	w, ok := ctx.Value(logKey{}).(io.Writer)
+	if !ok {
+		return
+	}
+	timestamp := time.Now().Format(logTimeFormat)
+	for _, line := range strings.Split(fmt.Sprintf(format, a...), "\n") {
+		fmt.Fprintf(w, "%s %s\n", timestamp, line)
+	}
+}
+
+// runCommand runs a command in dir (if not ""), killing it if ctx is canceled, and returns its output
+func runCommand(ctx context.Context, dir string, name string, args ...string) ([]byte, error) {
+	return runCommandWithEnv(ctx, dir, nil, name, args...)
+}
+
+// runCommandWithEnv runs a command as runCommand does, with env (e.g. "GOPROXY=off") added to its environment
+func runCommandWithEnv(ctx context.Context, dir string, env []string, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	if env != nil {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	start := time.Now()
+	cmdOutput, err := cmd.Output()
+	trace(ctx, commandLine(append(env, cmd.Args...)), dir, start, err)
+	for _, line := range strings.Split(strings.TrimSpace(stderr.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		if w, ok := ctx.Value(traceKey{}).(io.Writer); ok && err != nil {
+			fmt.Fprintf(w, "    %s\n", line)
+		}
+		logf(ctx, "    %s", line)
+	}
+	return cmdOutput, err
+}
+
+// trace reports (and logs) an action that started at start, and failed with err (if not nil)
+func trace(ctx context.Context, action string, dir string, start time.Time, err error) {
+	if dir == "" {
+		dir = "."
+	}
+	result := ""
+	if err != nil {
+		result = ": " + err.Error()
+	}
+	line := fmt.Sprintf("+ %s (in %s, %s)%s", action, dir, time.Since(start).Round(time.Millisecond), result)
+	if w, ok := ctx.Value(traceKey{}).(io.Writer); ok {
+		fmt.Fprintln(w, line)
+	}
+	logf(ctx, "%s", line)
+}
+
+// traced reports whether ctx has a trace or a log
+func traced(ctx context.Context) bool {
+	_, tracing := ctx.Value(traceKey{}).(io.Writer)
+	_, logging := ctx.Value(logKey{}).(io.Writer)
+	return tracing || logging
+}
+
+// tracef reports (and logs) a detail of what was traced last
+func tracef(ctx context.Context, format string, a ...any) {
+	line := fmt.Sprintf(format, a...)
+	if w, ok := ctx.Value(traceKey{}).(io.Writer); ok {
+		fmt.Fprintln(w, line)
+	}
+	logf(ctx, "%s", line)
+}
+
+// commandLine returns args as they could be typed into a shell
+func commandLine(args []string) string {
+	quoted := []string{}
+	for _, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\n\"'$`\\") {
+			arg = strconv.Quote(arg)
+		}
+		quoted = append(quoted, arg)
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,161 @@
+package create
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// Where gmc's latest release is looked up by LatestRelease, unless another URL (e.g. a mirror) is given
+const LatestReleaseURL string = "https://api.github.com/repos/jbrudvik/gmc/releases/latest"
+
+// Name of the file attached to each release that lists the SHA-256 checksum of each binary, as sha256sum prints them
+const releaseChecksumsName string = "checksums.txt"
+
+// A Release is a published release of gmc, as listed by GitHub
+type Release struct {
+	// The release's version (e.g. "v1.2.3")
+	Version string         `json:"tag_name"`
+	Assets  []releaseAsset `json:"assets"`
+}
+
+type releaseAsset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// LatestRelease returns gmc's latest release, as listed at releaseURL (or LatestReleaseURL, if "")
+func LatestRelease(ctx context.Context, releaseURL string) (*Release, error) {
+	if releaseURL == "" {
+		releaseURL = LatestReleaseURL
+	}
+	resp, err := httpGet(ctx, releaseURL)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("Failed to find the latest release: %s", err))
+	}
+	defer resp.Body.Close()
+	var release Release
+	err = json.NewDecoder(resp.Body).Decode(&release)
+	if err == nil && !semver.IsValid(release.Version) {
+		err = errors.New(fmt.Sprintf("invalid version: %q", release.Version))
+	}
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("Failed to find the latest release: %s", err))
+	}
+	return &release, nil
+}
+
+// NewerThan reports whether the release is newer than version (e.g. Version). A development build has no version to
+// compare, so every release is newer.
+func (r *Release) NewerThan(version string) bool {
+	return semver.Compare(r.Version, version) > 0
+}
+
+// Install downloads the release's binary for this platform, verifies it against the release's checksums, and replaces
+// executable with it. Until the download is verified, executable is left as it was.
+func (r *Release) Install(ctx context.Context, executable string) error {
+	binaryName := fmt.Sprintf("%s_%s_%s", Name, runtime.GOOS, runtime.GOARCH)
+	if runtime.GOOS == "windows" {
+		binaryName += ".exe"
+	}
+	binary, checksums := r.asset(binaryName), r.asset(releaseChecksumsName)
+	if binary == nil {
+		return errors.New(fmt.Sprintf("Failed to upgrade %s: %s has no download for %s/%s", Name, r.Version, runtime.GOOS, runtime.GOARCH))
+	}
+	if checksums == nil {
+		return errors.New(fmt.Sprintf("Failed to upgrade %s: %s has no %s", Name, r.Version, releaseChecksumsName))
+	}
+
+	checksum, err := releaseChecksum(ctx, checksums.URL, binaryName)
+	if err == nil {
+		err = downloadExecutable(ctx, binary.URL, checksum, executable)
+	}
+	if err != nil {
+		return errors.New(fmt.Sprintf("Failed to upgrade %s: %s: %s", Name, binaryName, err))
+	}
+	return nil
+}
+
+func (r *Release) asset(name string) *releaseAsset {
+	for i := range r.Assets {
+		if r.Assets[i].Name == name {
+			return &r.Assets[i]
+		}
+	}
+	return nil
+}
+
+// releaseChecksum returns the checksum of the file named name, from the list of checksums at url
+func releaseChecksum(ctx context.Context, url string, name string) (string, error) {
+	resp, err := httpGet(ctx, url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		// Each line is a checksum and a file name, which sha256sum marks with "*" when read as binary
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return fields[0], nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", errors.New(fmt.Sprintf("no checksum in %s", releaseChecksumsName))
+}
+
+// downloadExecutable downloads the executable at url, checks its checksum, and replaces executable with it, by way of a
+// temporary file beside it, so that an interrupted download leaves executable as it was
+func downloadExecutable(ctx context.Context, url string, checksum string, executable string) error {
+	resp, err := httpGet(ctx, url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	download, err := os.CreateTemp(filepath.Dir(executable), "."+filepath.Base(executable)+".download-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(download.Name())
+	defer download.Close()
+	hash := sha256.New()
+	_, err = io.Copy(io.MultiWriter(download, hash), resp.Body)
+	if err != nil {
+		return err
+	}
+	if downloaded := hex.EncodeToString(hash.Sum(nil)); downloaded != checksum {
+		return errors.New(fmt.Sprintf("checksum mismatch (expected %s, got %s)", checksum, downloaded))
+	}
+	err = download.Close()
+	if err == nil {
+		err = os.Chmod(download.Name(), 0755)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Windows won't replace a running executable, but will rename it out of the way
+	if runtime.GOOS == "windows" {
+		old := executable + ".old"
+		os.Remove(old)
+		err = os.Rename(executable, old)
+		if err != nil {
+			return err
+		}
+	}
+	return os.Rename(download.Name(), executable)
+}
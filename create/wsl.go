@@ -0,0 +1,40 @@
+package create
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Editor suggested under WSL when $EDITOR isn't set: VS Code opens the module through its WSL bridge
+const wslEditor string = "code"
+
+// Paths on Windows drives, which WSL mounts at /mnt/<drive letter>
+var wslWindowsDriveRegexp = regexp.MustCompile(`^/mnt/[a-z](/|$)`)
+
+// runningInWSL reports whether gmc is running under Windows Subsystem for Linux. It's a variable, so that tests can plan
+// as if under WSL or not, whatever the host.
+var runningInWSL = func() bool {
+	if os.Getenv("WSL_DISTRO_NAME") != "" {
+		return true
+	}
+	osRelease, err := os.ReadFile("/proc/sys/kernel/osrelease")
+	return err == nil && strings.Contains(strings.ToLower(string(osRelease)), "microsoft")
+}
+
+// addWSLNotes warns about the ways WSL commonly trips up a new module
+func (p *plan) addWSLNotes() {
+	// Files on Windows drives are accessed over a slow network filesystem
+	if dir, err := filepath.Abs(p.dir); err == nil && wslWindowsDriveRegexp.MatchString(dir) {
+		p.add(step{action: actionNote, arg: fmt.Sprintf("%s is on a Windows drive, where builds and Git are slow under WSL (consider a directory under ~ instead)", dir)})
+	}
+
+	// Git for Windows' default converts line endings, which shell scripts and gofmt don't expect
+	if p.repo != nil {
+		if autocrlf, err := p.repo.client.globalConfig("core.autocrlf"); err == nil && autocrlf == "true" {
+			p.add(step{action: actionNote, arg: "core.autocrlf is true, which checks out CRLF line endings under WSL (consider `git config --global core.autocrlf input`)"})
+		}
+	}
+}
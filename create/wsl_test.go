@@ -0,0 +1,64 @@
+package create
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWSL(t *testing.T) {
+	// Git for Windows' default line endings
+	gitConfigPath := filepath.Join(t.TempDir(), "gitconfig")
+	err := os.WriteFile(gitConfigPath, []byte("[core]\n\tautocrlf = true\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", gitConfigPath) // Automatically reset
+	t.Setenv("EDITOR", "")
+
+	tests := []struct {
+		wsl            bool
+		expectedNotes  []string
+		expectedEditor string
+	}{
+		{
+			true,
+			[]string{
+				"/mnt/c/Users/gopher/a1 is on a Windows drive, where builds and Git are slow under WSL (consider a directory under ~ instead)",
+				"core.autocrlf is true, which checks out CRLF line endings under WSL (consider `git config --global core.autocrlf input`)",
+			},
+			"Start coding: $ code .",
+		},
+		{false, nil, "Start coding: $ $EDITOR ."},
+	}
+	for _, tc := range tests {
+		wsl := runningInWSL
+		runningInWSL = func() bool { return tc.wsl }
+		ctx := context.Background()
+		p, err := newPlan(ctx, "a1", planOptions{
+			dir:    "/mnt/c/Users/gopher/a1",
+			repo:   &gitRepo{client: newGitClient(ctx, false)},
+			dryRun: true,
+		})
+		runningInWSL = wsl
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		notes := []string{}
+		for _, s := range p.steps {
+			if s.action == actionNote && strings.Contains(s.arg, "WSL") {
+				notes = append(notes, s.arg)
+			}
+		}
+		if strings.Join(notes, "\n") != strings.Join(tc.expectedNotes, "\n") {
+			t.Errorf("Unexpected notes (WSL: %t)\nExpected: %q\nActual  : %q\n", tc.wsl, tc.expectedNotes, notes)
+		}
+		nextSteps := p.nextSteps("main")
+		if editor := nextSteps[len(nextSteps)-1]; editor != tc.expectedEditor {
+			t.Errorf("Unexpected next step (WSL: %t)\nExpected: %q\nActual  : %q\n", tc.wsl, tc.expectedEditor, editor)
+		}
+	}
+}
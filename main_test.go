@@ -2,10 +2,17 @@ package main_test
 
 import (
 	"bytes"
+	"errors"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -15,7 +22,8 @@ const helpOutput string = "NAME:\n" +
 	"   gmc - (Go mod create) creates Go modules\n" +
 	"\n" +
 	"USAGE:\n" +
-	"   gmc [global options] [module name]\n" +
+	"   gmc [global options] [module name...]\n" +
+	"   gmc command [command options] [arguments...]\n" +
 	"\n" +
 	"VERSION:\n" +
 	"   (devel)\n" +
@@ -24,6 +32,7 @@ const helpOutput string = "NAME:\n" +
 	"   `gmc [module name]` creates a directory containing:\n" +
 	"   - Go module metadata: go.mod\n" +
 	"   - A place to start writing code: main.go\n" +
+	"   - A test that builds and runs it: main_test.go\n" +
 	"   - A .gitignore file\n" +
 	"   \n" +
 	"   This module can be immediately run:\n" +
@@ -33,14 +42,124 @@ const helpOutput string = "NAME:\n" +
 	"   \n" +
 	"   Optionally, the directory can also include:\n" +
 	"   - Git repository setup with .gitignore, README.md\n" +
+	"   - A LICENSE file\n" +
+	"   - A CI workflow\n" +
+	"   - A golangci-lint configuration\n" +
+	"   - A GoReleaser release configuration\n" +
+	"   - A Dockerfile\n" +
+	"   - A Makefile\n" +
+	"   - A Taskfile\n" +
+	"   - Scripts to bootstrap, build, test, and run (script/), optionally with PowerShell equivalents\n" +
+	"   - A bootstrap script for Linux machines\n" +
+	"   - Embedded assets (go:embed)\n" +
+	"   - Translated messages (golang.org/x/text)\n" +
+	"   - Feature flags (OpenFeature)\n" +
+	"   - Golden file tests\n" +
+	"   - Mutation testing (Gremlins)\n" +
+	"   - Secret scanning (gitleaks)\n" +
+	"   - OpenSSF Scorecard, with a Best Practices badge checklist\n" +
+	"   - Visual Studio Code, GoLand, or Neovim configuration\n" +
+	"   - A Gitpod or Codespaces configuration\n" +
 	"   \n" +
 	"   More information: https://github.com/jbrudvik/gmc\n" +
 	"\n" +
+	"COMMANDS:\n" +
+	"   new           create a Go module in a new directory (the default command)\n" +
+	"   init          create a Go module in the current directory, keeping any files already there\n" +
+	"   add           add a feature to the Go module in the current directory\n" +
+	"   adopt         bring the Go module in the current directory, which gmc didn't create, under gmc's management\n" +
+	"   plan          print what creating a Go module would do as JSON, to review, and then carry out with `gmc apply`\n" +
+	"   apply         carry out a plan saved from `gmc plan` (- for standard input)\n" +
+	"   config        print where the config file is read from, and the settings in effect\n" +
+	"   serve         create Go modules on request, over HTTP\n" +
+	"   templates     list the templates modules can be created from, with where each comes from and what it adds\n" +
+	"   audit         report which modules in directories of repositories are outdated, missing required features, or changed\n" +
+	"   release-prep  check that the Go module in the current directory is ready to release, then tag the release\n" +
+	"   doctor        check that the tools and settings gmc uses are installed and configured\n" +
+	"   upgrade-self  replace gmc with its latest release\n" +
+	"   completion    print a shell completion script: bash, zsh, fish, powershell\n" +
+	"   man           print the man page, generated from this help\n" +
+	"   help          show help, or a help topic: config, manifest, remote, templates\n" +
+	"\n" +
 	"GLOBAL OPTIONS:\n" +
-	"   --git, -g      create as Git repository (default: false)\n" +
-	"   --quiet, -q    silence output (default: false)\n" +
-	"   --help, -h     show help (default: false)\n" +
-	"   --version, -v  print the version (default: false)\n"
+	"   --local                       keep a module name without a slash as is, instead of adding the configured prefix (default: false)\n" +
+	"   --infer                       add github.com/<your GitHub login> to a module name without a slash (default: false)\n" +
+	"   --output-dir value, -C value  create the module in this directory, instead of the current one\n" +
+	"   --full-path                   create the module's directory at its full module path (e.g. github.com/you/mymodule), instead of its last element (default: false)\n" +
+	"   --force                       create the module in its directory even if the directory exists, keeping any files already there (default: false)\n" +
+	"   --resume                      finish creating a module whose creation was interrupted, skipping what was already done (default: false)\n" +
+	"   --skip value                  skip these stages (comma-separated): files, deps, git\n" +
+	"   --only value                  run only these stages (comma-separated): files, deps, git\n" +
+	"   --git, -g                     create as Git repository (default: false)\n" +
+	"   --git-exec                    run the git executable for Git actions, instead of the built-in implementation (default: false)\n" +
+	"   --create-remote               create the remote repository on GitHub (with $GITHUB_TOKEN, or gh's login), named by the module path, and add it as origin (requires --git) (default: false)\n" +
+	"   --private                     make the remote repository created by --create-remote private (the default) (default: false)\n" +
+	"   --public                      make the remote repository created by --create-remote public (default: false)\n" +
+	"   --push                        push the initial commit to the remote, once created by --create-remote or if it already exists (requires --git) (default: false)\n" +
+	"   --remote-protocol value       add the Git remote with an ssh or https URL (default: ssh)\n" +
+	"   --bundle                      also write the Git repository to <name>.bundle next to the module's directory, to clone elsewhere (implies --git) (default: false)\n" +
+	"   --bundle-only                 write the Git repository to <name>.bundle as --bundle does, and then remove the module's directory (default: false)\n" +
+	"   --ci value                    add a CI workflow: github, gitlab, auto\n" +
+	"   --fmt-check value             check formatting in CI with gofmt or gofumpt, and files against an added .editorconfig (requires --ci)\n" +
+	"   --go-version value            pin the Go version for go.mod, CI, and the Dockerfile (e.g. 1.21) instead of using the installed version\n" +
+	"   --toolchain value             pin the Go toolchain (e.g. go1.22.3) in go.mod, and with GOTOOLCHAIN in an .envrc and CI, so that it's used even where an older Go is installed\n" +
+	"   --in-container                run Go commands in a golang container (with Docker or Podman) instead of with the installed Go (default: false)\n" +
+	"   --provision-go                if Go isn't installed, download the official Go (checksum-verified) into gmc's cache and run Go commands with it, without asking (default: false)\n" +
+	"   --goproxy value               fetch dependencies from this module proxy (e.g. a private Athens or Artifactory URL) instead of the GOPROXY in go env\n" +
+	"   --private-proxy value         resolve modules only through this private proxy (e.g. an Athens or Artifactory URL), documenting its setup, and configuring .envrc and CI to use it\n" +
+	"   --static                      build and verify a fully static binary in CI (default: false)\n" +
+	"   --reproducible                build byte-for-byte reproducible binaries, and verify in CI that two builds match (default: false)\n" +
+	"   --embed-assets                add an assets directory embedded into the binary with go:embed (default: false)\n" +
+	"   --i18n                        add translated messages with golang.org/x/text (default: false)\n" +
+	"   --feature-flags value         add feature flags with an environment variable provider: openfeature\n" +
+	"   --notice                      generate NOTICE, attributing the dependencies added (with --i18n or --feature-flags), with go-licenses, and a make/task target that regenerates it (default: false)\n" +
+	"   --layout value                add a package layout: apiv1, for a public API in api/v1 that a v2 can be added beside\n" +
+	"   --golden                      add a golden file test helper and an example test (default: false)\n" +
+	"   --mutation                    add a Gremlins mutation testing configuration, with a CI job and make/task target (default: false)\n" +
+	"   --secret-scan                 configure gitleaks secret scanning, with a pre-commit hook the Git repository runs from its first commit, and a CI job (default: false)\n" +
+	"   --scorecard                   add an OpenSSF Scorecard workflow and README badge, and a Best Practices badge checklist (requires a module under github.com) (default: false)\n" +
+	"   --lint                        add a golangci-lint configuration, and run it in CI (default: false)\n" +
+	"   --pgo                         add a default.pgo profile for profile-guided optimization (default: false)\n" +
+	"   --goreleaser                  add a GoReleaser configuration and release workflow (default: false)\n" +
+	"   --make                        add a Makefile with build, test, lint, fmt, run, and clean targets (default: false)\n" +
+	"   --taskfile                    add a Taskfile.yml with the same tasks as --make (default: false)\n" +
+	"   --targets value               build for these platforms (comma-separated, e.g. linux/amd64,darwin/arm64,windows/amd64) with cgo disabled: into bin/dist/<os>_<arch>/ with a dist target in the Makefile and Taskfile, and with GoReleaser\n" +
+	"   --scripts                     add script/bootstrap, script/build, script/test, and script/server, used by --make, --taskfile, and CI (default: false)\n" +
+	"   --powershell                  add PowerShell equivalents of the --scripts scripts, and also run CI on windows-latest (default: false)\n" +
+	"   --bootstrap-script            add a script/bootstrap that installs the Go toolchain and tools on a fresh Linux machine (default: false)\n" +
+	"   --docker                      add a Dockerfile and .dockerignore (default: false)\n" +
+	"   --license value               add a LICENSE file: mit, apache-2.0, bsd-3-clause\n" +
+	"   --vscode                      add Visual Studio Code settings, launch configuration, and tasks (default: false)\n" +
+	"   --goland                      add GoLand run configurations for build, run, and test (default: false)\n" +
+	"   --nvim                        add a project-local Neovim configuration for gopls and debugging (default: false)\n" +
+	"   --cloud-dev value             add a prebuilt cloud development environment: gitpod, codespaces\n" +
+	"   --split-cmd                   create a library, with its command in a separate module in cmd/<name> that requires it through a replace directive (default: false)\n" +
+	"   --examples                    add a runnable example program in a separate module in examples/, which CI builds (requires --split-cmd) (default: false)\n" +
+	"   --prop-tests                  add an example property-based test of the library with rapid (requires --split-cmd) (default: false)\n" +
+	"   --no-deps                     fail unless only the standard library is used (default: false)\n" +
+	"   --fail-on-vuln                fail if govulncheck finds known vulnerabilities in the dependencies added, instead of noting them (default: false)\n" +
+	"   --batch value                 also create each module named in a file, one per line (- for standard input)\n" +
+	"   --archive value               write the module to an archive (e.g. out.tar.gz or out.zip, or - for a tarball on standard output) instead of a directory, without Git\n" +
+	"   --dry-run                     print what would be created without creating anything (default: false)\n" +
+	"   --keep-partial                keep what was created when creation fails partway, instead of removing it (default: false)\n" +
+	"   --timeout value               give up, removing what was created, if creating takes longer than this (e.g. 2m) (default: 0s)\n" +
+	"   --json                        print a JSON report instead of progress output (default: false)\n" +
+	"   --color value                 color output: auto (on a terminal, unless $NO_COLOR is set), always, never (default: \"auto\")\n" +
+	"   --verbose, -V                 print each command run (e.g. git, go), with where and how long it ran, to error output (default: false)\n" +
+	"   --log-file value              append a timestamped log of every step and command run (with its error output) to this file\n" +
+	"   --quiet, -q                   silence progress output, still reporting errors (default: false)\n" +
+	"   --silent                      silence all output, including errors (the exit code still reports failure) (default: false)\n" +
+	"   --help, -h                    show help (default: false)\n" +
+	"   --version, -v                 print the version (default: false)\n" +
+	"\n" +
+	"EXIT CODES:\n" +
+	"   0    success\n" +
+	"   1    failure\n" +
+	"   2    usage error (e.g. an unknown flag or an invalid module name)\n" +
+	"   3    filesystem conflict (e.g. the module's directory already exists)\n" +
+	"   4    Git failure (e.g. `git config --global user.email` is not set)\n" +
+	"   5    Go toolchain failure (e.g. go is not installed)\n" +
+	"   130  interrupted\n"
 
 type executableTestCase struct {
 	args             []string
@@ -49,12 +168,30 @@ type executableTestCase struct {
 	expectedStderr   string
 }
 
+// TestMain gives Git a global config with a test user, so that the tests don't depend on the config of the machine
+// they run on (which may set no user at all)
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "gmc-test")
+	if err != nil {
+		panic(err)
+	}
+	gitConfig := filepath.Join(dir, "gitconfig")
+	err = os.WriteFile(gitConfig, []byte("[user]\n\tname = Test Gopher\n\temail = gopher@example.com\n"), 0644)
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("GIT_CONFIG_GLOBAL", gitConfig)
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
 func TestExecutable(t *testing.T) {
 	// Test cases
 	tests := []executableTestCase{
 		{
 			args:             nil,
-			expectedExitCode: 1,
+			expectedExitCode: 2,
 			expectedStdout:   helpOutput,
 			expectedStderr:   "Error: Module name is required\n\n",
 		},
@@ -73,17 +210,82 @@ func TestExecutable(t *testing.T) {
 	}
 }
 
-func runExecutableTestCase(t *testing.T, tc executableTestCase) {
-	// Create a temporary test dir (automatically cleaned up)
-	tempTestDir := t.TempDir()
+func TestExecutableInterrupted(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Interrupts can't be sent to processes on Windows")
+	}
+	executablePath := buildExecutable(t)
+	workDir := t.TempDir()
 
-	// Build executable
-	buildCmd := exec.Command("go", "build", "-o", tempTestDir)
+	// A module proxy that never answers, so that adding a dependency is slow until interrupted
+	requested := make(chan struct{}, 1)
+	done := make(chan struct{})
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case requested <- struct{}{}:
+		default:
+		}
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+	}))
+	defer proxy.Close()
+	defer close(done)
+
+	cmd := exec.Command(executablePath, "--i18n", "a1")
+	cmd.Dir = workDir
+	cmd.Env = append(os.Environ(),
+		"GMC_CONFIG="+filepath.Join(t.TempDir(), "config.json"),
+		"GOPROXY="+proxy.URL,
+		"GOMODCACHE="+t.TempDir(),
+		"GOFLAGS=-mod=mod",
+		"GONOSUMDB=",
+		"GOSUMDB=off",
+		"GOPRIVATE=",
+	)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	err := cmd.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-requested:
+	case <-time.After(time.Minute):
+		_ = cmd.Process.Kill()
+		t.Fatal("Dependency was never requested")
+	}
+	err = cmd.Process.Signal(os.Interrupt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = cmd.Wait()
+	var exitError *exec.ExitError
+	if !errors.As(err, &exitError) {
+		t.Fatalf("Unable to parse exit code from: %v", err)
+	}
+	assert.Equal(t, 130, exitError.ExitCode(), "exit code")
+	assert.True(t, strings.HasSuffix(stderr.String(), "Failed to create Go module: a1: Interrupted\n"), "stderr: %s", stderr.String())
+	_, err = os.Stat(filepath.Join(workDir, "a1"))
+	assert.True(t, errors.Is(err, fs.ErrNotExist), "directory a1 was not removed")
+}
+
+// buildExecutable builds the executable into a temporary directory (automatically cleaned up), and returns its path.
+// It's built without VCS information, which Go 1.24 and later would otherwise make its version from.
+func buildExecutable(t *testing.T) string {
+	tempTestDir := t.TempDir()
+	buildCmd := exec.Command("go", "build", "-buildvcs=false", "-o", tempTestDir)
 	err := buildCmd.Run()
 	if err != nil {
 		t.Fatalf("Unable to `go build` %s: $ %s\n", executableName, buildCmd)
 	}
-	executablePath := filepath.Join(tempTestDir, executableName)
+	return filepath.Join(tempTestDir, executableName)
+}
+
+func runExecutableTestCase(t *testing.T, tc executableTestCase) {
+	executablePath := buildExecutable(t)
 
 	// Run executable and test outputs
 	cmd := exec.Command(executablePath, tc.args...)
@@ -91,7 +293,7 @@ func runExecutableTestCase(t *testing.T, tc executableTestCase) {
 	var stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
-	err = cmd.Run()
+	err := cmd.Run()
 	if err != nil {
 		if exitError, ok := err.(*exec.ExitError); ok {
 			assert.Equal(t, tc.expectedExitCode, exitError.ExitCode(), "exit code")
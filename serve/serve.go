@@ -0,0 +1,195 @@
+// Package serve exposes module creation over HTTP, for tools (e.g. developer portals) that create modules on request
+package serve
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/jbrudvik/gmc/create"
+)
+
+// A Request names a module to create, and what it contains. Fields correspond to the options of `gmc new`.
+type Request struct {
+	Module           string   `json:"module"`
+	Git              bool     `json:"git,omitempty"`
+	GitInitialBranch string   `json:"gitInitialBranch,omitempty"`
+	CI               string   `json:"ci,omitempty"`
+	GoVersion        string   `json:"goVersion,omitempty"`
+	License          string   `json:"license,omitempty"`
+	Static           bool     `json:"static,omitempty"`
+	Reproducible     bool     `json:"reproducible,omitempty"`
+	EmbedAssets      bool     `json:"embedAssets,omitempty"`
+	I18n             bool     `json:"i18n,omitempty"`
+	FeatureFlags     string   `json:"featureFlags,omitempty"`
+	Notice           bool     `json:"notice,omitempty"`
+	Golden           bool     `json:"golden,omitempty"`
+	Mutation         bool     `json:"mutation,omitempty"`
+	Lint             bool     `json:"lint,omitempty"`
+	Linters          []string `json:"linters,omitempty"`
+	PGO              bool     `json:"pgo,omitempty"`
+	GoReleaser       bool     `json:"goreleaser,omitempty"`
+	Make             bool     `json:"make,omitempty"`
+	Taskfile         bool     `json:"taskfile,omitempty"`
+	Targets          []string `json:"targets,omitempty"`
+	Scripts          bool     `json:"scripts,omitempty"`
+	PowerShell       bool     `json:"powershell,omitempty"`
+	BootstrapScript  bool     `json:"bootstrapScript,omitempty"`
+	Docker           bool     `json:"docker,omitempty"`
+	Editors          []string `json:"editors,omitempty"`
+	CloudDev         string   `json:"cloudDev,omitempty"`
+	NoDeps           bool     `json:"noDeps,omitempty"`
+	FailOnVuln       bool     `json:"failOnVuln,omitempty"`
+
+	// Git remote to push the module to (with the server's Git credentials), instead of returning it as a tarball.
+	// Implies Git.
+	Remote string `json:"remote,omitempty"`
+}
+
+// Largest request body accepted, well beyond any real request
+const maxRequestBytes int64 = 1 << 20
+
+// A Response reports a module pushed to a remote, or a failure
+type Response struct {
+	Result *create.Result `json:"result,omitempty"`
+	Remote string         `json:"remote,omitempty"`
+	Error  string         `json:"error,omitempty"`
+}
+
+func (req *Request) options() create.Options {
+	return create.Options{
+		Module:           req.Module,
+		Git:              req.Git || req.Remote != "",
+		GitInitialBranch: req.GitInitialBranch,
+		CI:               req.CI,
+		GoVersion:        req.GoVersion,
+		License:          req.License,
+		Static:           req.Static,
+		Reproducible:     req.Reproducible,
+		EmbedAssets:      req.EmbedAssets,
+		I18n:             req.I18n,
+		FeatureFlags:     req.FeatureFlags,
+		Notice:           req.Notice,
+		Golden:           req.Golden,
+		Mutation:         req.Mutation,
+		Lint:             req.Lint,
+		Linters:          req.Linters,
+		PGO:              req.PGO,
+		GoReleaser:       req.GoReleaser,
+		Make:             req.Make,
+		Taskfile:         req.Taskfile,
+		Targets:          req.Targets,
+		Scripts:          req.Scripts,
+		PowerShell:       req.PowerShell,
+		BootstrapScript:  req.BootstrapScript,
+		Docker:           req.Docker,
+		Editors:          req.Editors,
+		CloudDev:         req.CloudDev,
+		NoDeps:           req.NoDeps,
+		FailOnVuln:       req.FailOnVuln,
+	}
+}
+
+// Handler returns a handler that creates a module for each POSTed Request (as JSON). The module is created in a
+// temporary directory, and returned as a gzipped tarball of its directory, or pushed to Request.Remote.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeResponse(w, http.StatusMethodNotAllowed, &Response{Error: "Only POST is allowed"})
+			return
+		}
+		var req Request
+		err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes)).Decode(&req)
+		if err != nil {
+			writeResponse(w, http.StatusBadRequest, &Response{Error: fmt.Sprintf("Invalid request: %s", err)})
+			return
+		}
+		// The module is pushed with the server's credentials, so only to the hosts it creates modules for
+		if req.Remote != "" && !allowedRemote(req.Remote) {
+			writeResponse(w, http.StatusBadRequest, &Response{Error: fmt.Sprintf("Unsupported remote: %s (supported: ssh or https URLs on %s)", req.Remote, strings.Join(create.GitHosts(), ", "))})
+			return
+		}
+
+		// Create module
+		outputDir, err := os.MkdirTemp("", create.Name+"-serve-")
+		if err != nil {
+			writeResponse(w, http.StatusInternalServerError, &Response{Error: err.Error()})
+			return
+		}
+		defer os.RemoveAll(outputDir)
+		opts := req.options()
+		opts.OutputDir = outputDir
+		result, err := create.Create(r.Context(), opts)
+		var usage create.UsageError
+		if errors.As(err, &usage) {
+			writeResponse(w, http.StatusBadRequest, &Response{Error: err.Error()})
+			return
+		} else if err != nil {
+			writeResponse(w, http.StatusInternalServerError, &Response{Error: err.Error()})
+			return
+		}
+		moduleBase := path.Base(opts.Module)
+
+		// Push module
+		if req.Remote != "" {
+			cmd := exec.CommandContext(r.Context(), "git", "push", "--", req.Remote, "HEAD")
+			cmd.Dir = filepath.Join(outputDir, moduleBase)
+			cmdOutput, err := cmd.CombinedOutput()
+			if err != nil {
+				writeResponse(w, http.StatusBadGateway, &Response{Result: result, Error: fmt.Sprintf("Failed to push to %s: %s", req.Remote, cmdOutput)})
+				return
+			}
+			writeResponse(w, http.StatusOK, &Response{Result: result, Remote: req.Remote})
+			return
+		}
+
+		// Return module
+		w.Header().Set("Content-Type", "application/gzip")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", moduleBase+".tar.gz"))
+		// On failure, it's too late to change the status, so the tarball is left incomplete
+		_ = create.WriteTarball(w, outputDir, moduleBase)
+	})
+}
+
+// allowedRemote returns whether a Git remote's URL is one that gmc would add (ssh or https) for a known Git host. It
+// can't be taken for an option of `git push`.
+func allowedRemote(remote string) bool {
+	if strings.HasPrefix(remote, "-") {
+		return false
+	}
+	host := ""
+	if strings.HasPrefix(remote, "git@") {
+		var repoPath string
+		var ok bool
+		host, repoPath, ok = strings.Cut(strings.TrimPrefix(remote, "git@"), ":")
+		if !ok || repoPath == "" {
+			return false
+		}
+	} else {
+		u, err := url.Parse(remote)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "ssh") || strings.Trim(u.Path, "/") == "" {
+			return false
+		}
+		host = u.Host
+	}
+	for _, h := range create.GitHosts() {
+		if host == h {
+			return true
+		}
+	}
+	return false
+}
+
+func writeResponse(w http.ResponseWriter, status int, response *Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,94 @@
+package serve_test
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/jbrudvik/gmc/serve"
+)
+
+func TestHandler(t *testing.T) {
+	server := httptest.NewServer(serve.Handler())
+	defer server.Close()
+
+	// Tarball
+	response, err := http.Post(server.URL, "application/json", strings.NewReader(`{"module": "github.com/foo/a1", "make": true}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		t.Fatal(unexpectedMessage("status", http.StatusOK, response.StatusCode))
+	}
+	gz, err := gzip.NewReader(response.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	names := []string{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, header.Name)
+	}
+	sort.Strings(names)
+	expectedNames := []string{"a1/", "a1/.gitignore", "a1/.gmc/", "a1/.gmc/manifest.json", "a1/Makefile", "a1/go.mod", "a1/main.go", "a1/main_test.go"}
+	if strings.Join(names, " ") != strings.Join(expectedNames, " ") {
+		t.Error(unexpectedMessage("tarball", expectedNames, names))
+	}
+
+	// Failures
+	tests := []struct {
+		method         string
+		body           string
+		expectedStatus int
+		expectedError  string
+	}{
+		{http.MethodGet, "", http.StatusMethodNotAllowed, "Only POST is allowed"},
+		{http.MethodPost, "{", http.StatusBadRequest, "Invalid request: unexpected EOF"},
+		{http.MethodPost, `{"module": "a b"}`, http.StatusBadRequest, "Error: Invalid module name: a b: invalid char ' '"},
+		{http.MethodPost, `{"module": "a1", "ci": "travis"}`, http.StatusBadRequest, "Error: Unsupported CI provider: travis (supported: github, gitlab, auto)"},
+		{http.MethodPost, `{"module": "a1", "remote": "--receive-pack=touch pwned"}`, http.StatusBadRequest, "Unsupported remote: --receive-pack=touch pwned (supported: ssh or https URLs on github.com, gitlab.com, bitbucket.org, codeberg.org, git.sr.ht)"},
+		{http.MethodPost, `{"module": "a1", "remote": "https://example.com/foo/a1.git"}`, http.StatusBadRequest, "Unsupported remote: https://example.com/foo/a1.git (supported: ssh or https URLs on github.com, gitlab.com, bitbucket.org, codeberg.org, git.sr.ht)"},
+		{http.MethodPost, `{"module": "a1", "remote": "file:///tmp/a1.git"}`, http.StatusBadRequest, "Unsupported remote: file:///tmp/a1.git (supported: ssh or https URLs on github.com, gitlab.com, bitbucket.org, codeberg.org, git.sr.ht)"},
+		{http.MethodPost, `{"module": "a1", "license": "` + strings.Repeat("x", 1<<20) + `"}`, http.StatusBadRequest, "Invalid request: http: request body too large"},
+	}
+	for _, tc := range tests {
+		request, err := http.NewRequest(tc.method, server.URL, strings.NewReader(tc.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var r serve.Response
+		err = json.NewDecoder(response.Body).Decode(&r)
+		response.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if response.StatusCode != tc.expectedStatus {
+			t.Error(unexpectedMessage("status", tc.expectedStatus, response.StatusCode))
+		}
+		if r.Error != tc.expectedError {
+			t.Error(unexpectedMessage("error", tc.expectedError, r.Error))
+		}
+	}
+}
+
+func unexpectedMessage[T any](thing string, expected T, actual T) string {
+	return fmt.Sprintf("Unexpected %s\nExpected: %v\nActual  : %v\n", thing, expected, actual)
+}